@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types reported on a RouteBinding
+const (
+	// RouteBindingConditionRouteCreated is True once the service's Route exists and False while
+	// TinyLB fails to create it
+	RouteBindingConditionRouteCreated = "RouteCreated"
+
+	// RouteBindingConditionAdmitted mirrors the router's admission of the Route
+	RouteBindingConditionAdmitted = "Admitted"
+)
+
+// RouteBindingSpec identifies the service and the Route TinyLB manages for it.
+type RouteBindingSpec struct {
+	// ServiceName is the LoadBalancer service in the RouteBinding's namespace.
+	ServiceName string `json:"serviceName"`
+
+	// RouteName is the Route fronting the service.
+	RouteName string `json:"routeName"`
+}
+
+// RouteBindingStatus reports the state of the service's Route.
+type RouteBindingStatus struct {
+	// Host is the host the Route serves, once it exists.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Conditions describe whether the Route was created and admitted.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Service",type=string,JSONPath=`.spec.serviceName`
+// +kubebuilder:printcolumn:name="Route",type=string,JSONPath=`.spec.routeName`
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=`.status.host`
+// +kubebuilder:printcolumn:name="Created",type=string,JSONPath=`.status.conditions[?(@.type=="RouteCreated")].status`
+// +kubebuilder:printcolumn:name="Admitted",type=string,JSONPath=`.status.conditions[?(@.type=="Admitted")].status`
+
+// RouteBinding records the binding between a LoadBalancer service and the Route TinyLB manages
+// for it. It is owned by the service and maintained by TinyLB; editing it has no effect.
+type RouteBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RouteBindingSpec   `json:"spec,omitempty"`
+	Status RouteBindingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RouteBindingList contains a list of RouteBinding.
+type RouteBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RouteBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RouteBinding{}, &RouteBindingList{})
+}
@@ -17,41 +17,65 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	networkingv1alpha1 "github.com/jctanner/tinylb/api/v1alpha1"
 	"github.com/jctanner/tinylb/internal/controller"
 	routev1 "github.com/openshift/api/route/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	// +kubebuilder:scaffold:imports
 )
 
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+
+	// Set at build time with -ldflags "-X main.version=... -X main.commit=..."
+	version = "dev"
+	commit  = "unknown"
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(routev1.AddToScheme(scheme))
 	utilruntime.Must(gatewayv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
+	utilruntime.Must(networkingv1alpha1.AddToScheme(scheme))
 
 	// +kubebuilder:scaffold:scheme
 }
@@ -63,12 +87,75 @@ func main() {
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
+	var debugAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var optInAnnotation string
+	var requireOptIn bool
+	var baseDomains string
+	var routerShards int
+	var managementPorts string
+	var preferredPorts string
+	var portNamePriority string
+	var conditionMessages string
+	var otelEndpoint string
+	var hostSuffix string
+	var honorExternalDNSHostname bool
+	var hostStrategy string
+	var ingressMergeStrategy string
+	var preserveNodeIngress bool
+	var companionServices bool
+	var namespaceFailureThreshold int
+	var namespaceFailureBackoff time.Duration
+	var eagerRouteCreation bool
+	var singleGatewayPerClass bool
+	var reportBackendReady bool
+	var gatewayRequireEndpoints bool
+	var gatewayRequireRouteAdmission bool
+	var reportListenerStatus bool
+	var countAttachedRoutes bool
+	var domainPrefix string
+	var cleanupForeignStatus bool
+	var useServiceCA bool
+	var mirrorTLSSecrets bool
+	var routeNamespace string
+	var defaultGatewayHostTemplate string
+	var gatewayExposureAnnotations bool
+	var programmedRequires string
+	var gatewayProgrammedTimeout time.Duration
+	var serviceNotFoundMaxAttempts int
+	var mirrorServiceLabels string
+	var maxRoutesPerNamespace int
+	var gatewayAPIVersionFlag string
+	var ignoreStatusUpdates bool
+	var routeBindings bool
+	var manageDNS bool
+	var routePortByName bool
+	var warnPlainHTTPPassthrough bool
+	var zeroDowntimeHostChange bool
+	var validatePorts bool
+	var defaultRouteWeight int
+	var syncPeriod time.Duration
+	var serviceDebounce time.Duration
+	var serviceConcurrency int
+	var routeCreateQPS float64
+	var recordTargetPort bool
+	var hostRegistry string
+	var gatewaySummary string
+	var drainGracePeriod time.Duration
+	var fieldManager string
+	var auditLog string
+	var gatewayClasses string
+	var controllerNames string
+	var verifyDNS bool
+	var gatewayControllerPerClass bool
+	var gatewayControllerName string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&debugAddr, "debug-bind-address", "0", "The address the debug endpoint binds to. "+
+		"It serves the effective configuration as JSON on /config. Leave as 0 to disable the debug endpoint.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -83,6 +170,168 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&optInAnnotation, "opt-in-annotation", "",
+		"If set, only LoadBalancer services carrying this annotation are reconciled. Services naming another "+
+			"load balancer provider in "+controller.ProviderAnnotation+" are always skipped.")
+	flag.BoolVar(&requireOptIn, "require-opt-in", false,
+		"Shorthand for --opt-in-annotation="+controller.ProviderAnnotation+": only services annotated "+
+			controller.ProviderAnnotation+"=tinylb are reconciled, leaving the rest to another load balancer provider.")
+	flag.StringVar(&baseDomains, "base-domains", controller.DefaultBaseDomain,
+		"Comma-separated list of wildcard domains that route hosts are spread across.")
+	flag.IntVar(&routerShards, "router-shards", 0,
+		"Number of router shards to spread routes across via a router=shard-N label. 0 disables sharding.")
+	flag.StringVar(&managementPorts, "management-ports", controller.FormatPortList(controller.DefaultManagementPorts),
+		"Comma-separated list of management/status ports to avoid when selecting a service port.")
+	flag.StringVar(&preferredPorts, "preferred-ports", "",
+		"Comma-separated list of service ports selected first, in order, e.g. 9443,443. Replaces the standard "+
+			"443/8443 and 80/8080 priorities; port name hints still apply when none of them is exposed.")
+	flag.StringVar(&portNamePriority, "port-name-priority", strings.Join(controller.DefaultPortNamePriority, ","),
+		"Comma-separated list of port name hints tried in order when no preferred port is exposed, e.g. "+
+			"https,http,web,grpc. A port matches a hint when its name contains it, case-insensitively.")
+	flag.StringVar(&conditionMessages, "condition-messages", "",
+		"JSON object of Gateway condition message templates keyed by message (e.g. "+
+			"'{\"ServiceNotFound\":\"Waiting for service {{.Service}}\"}'). Unset messages keep their defaults.")
+	flag.StringVar(&otelEndpoint, "otel-endpoint", "",
+		"OTLP/gRPC collector URL (e.g. http://otel-collector:4317) to export reconcile traces to. "+
+			"Leave empty to disable tracing.")
+	flag.StringVar(&hostSuffix, "host-suffix", "",
+		"Environment tag inserted before the base domain, e.g. -dev gives {service}-{namespace}-dev.{base-domain}.")
+	flag.BoolVar(&honorExternalDNSHostname, "honor-external-dns-hostname", false,
+		"If set, the external-dns.alpha.kubernetes.io/hostname service annotation is used as the Route host.")
+	flag.StringVar(&hostStrategy, "host-strategy", controller.HostStrategyTemplate,
+		"How Route hosts are generated unless a service's tinylb.io/host-strategy annotation picks another: "+
+			"template ({service}-{namespace}.{domain}), annotation (external-dns hostname, else template) "+
+			"or subdomain ({service}.{namespace}.{domain}).")
+	flag.StringVar(&ingressMergeStrategy, "ingress-merge-strategy", string(controller.IngressMergeSkipIfPresent),
+		"How the Route host is written relative to ingress entries set by another provider: "+
+			"skip-if-present (leave such services alone), append or replace.")
+	flag.BoolVar(&preserveNodeIngress, "preserve-node-ingress", false,
+		"If set, services whose ingress already advertises a node IP (bare-metal setups) are left alone "+
+			"unless annotated tinylb.io/force=true.")
+	flag.BoolVar(&companionServices, "companion-services", false,
+		"If set, each Route targets a ClusterIP companion service ({service}-tinylb) that TinyLB keeps in sync with "+
+			"the LoadBalancer service's selector and ports, for meshes where the LoadBalancer service cannot be targeted.")
+	flag.IntVar(&namespaceFailureThreshold, "namespace-failure-threshold", controller.DefaultNamespaceFailureThreshold,
+		"Consecutive Route creation failures in a namespace before its services are backed off.")
+	flag.DurationVar(&namespaceFailureBackoff, "namespace-failure-backoff", controller.DefaultNamespaceFailureBackoff,
+		"How long a namespace is backed off after repeated Route creation failures.")
+	flag.BoolVar(&eagerRouteCreation, "eager-route-creation", false,
+		"If set, Routes are created right away and answer 503 until the service has ready endpoints.")
+	flag.BoolVar(&singleGatewayPerClass, "single-gateway-per-class", false,
+		"If set, only the oldest Gateway of each class in a namespace is programmed; "+
+			"the others are marked Accepted=False with reason MultipleGateways.")
+	flag.StringVar(&hostRegistry, "host-registry", "",
+		"ConfigMap (namespace/name) persisting the Route host assigned to each service; entries of deleted services are "+
+			"pruned. Leave empty to disable.")
+	flag.StringVar(&gatewaySummary, "gateway-summary", "",
+		"ConfigMap (namespace/name) listing every programmed Gateway and its addresses, for tooling that cannot watch "+
+			"Gateways. Leave empty to disable.")
+	flag.DurationVar(&drainGracePeriod, "drain-grace-period", controller.DefaultDrainGracePeriod,
+		"How long a service annotated tinylb.io/drain=true keeps advertising its host before its ingress is cleared.")
+	flag.StringVar(&fieldManager, "field-manager", controller.DefaultFieldManager,
+		"Field manager name recorded on every write, so kubectl attributes TinyLB's fields and conflicts to it.")
+	flag.StringVar(&auditLog, "audit-log", "",
+		"File every Route create, update, patch and delete and every status write is appended to as JSON lines, "+
+			"with its time and field manager. Use - for stdout. Leave empty to disable.")
+	flag.BoolVar(&verifyDNS, "verify-dns", false,
+		"If set, advertised Route hosts are looked up in DNS and services whose host does not resolve get a Warning event.")
+	flag.StringVar(&gatewayClasses, "gateway-classes", strings.Join(controller.DefaultGatewayClasses, ","),
+		"Comma-separated list of GatewayClass names whose Gateways TinyLB programs.")
+	flag.StringVar(&controllerNames, "controller-names", "",
+		"Comma-separated list of GatewayClass controllerNames; Gateways of classes with one of them are programmed "+
+			"in addition to those listed in --gateway-classes.")
+	flag.BoolVar(&gatewayControllerPerClass, "gateway-controller-per-class", false,
+		"If set, each Gateway class gets its own controller ({gateway-controller-name}-{class}) instead of one shared controller.")
+	flag.StringVar(&gatewayControllerName, "gateway-controller-name", controller.DefaultGatewayControllerName,
+		"Name of the Gateway controller, recorded with the Gateway's class in the tinylb.io/reconciled-by claim. "+
+			"TinyLB installs sharing a cluster need distinct names, or each releases Gateways the other claimed.")
+	flag.BoolVar(&reportBackendReady, "report-backend-ready", false,
+		"If set, Gateways get a {domain-prefix}/BackendReady condition reflecting whether their service has ready endpoints.")
+	flag.StringVar(&domainPrefix, "domain-prefix", controller.DefaultDomainPrefix,
+		"DNS subdomain qualifying the custom condition types TinyLB sets on Gateways, e.g. {domain-prefix}/BackendReady.")
+	flag.BoolVar(&gatewayRequireEndpoints, "gateway-require-endpoints", false,
+		"If set, Gateways are only reported Programmed while their service has at least one ready endpoint.")
+	flag.BoolVar(&gatewayRequireRouteAdmission, "gateway-require-route-admission", false,
+		"If set, Gateways are only reported Programmed once a router has admitted their service's Route; a rejected "+
+			"Route makes the Gateway Programmed=False with reason Invalid and the router's rejection reason.")
+	flag.BoolVar(&reportListenerStatus, "report-listener-status", false,
+		"If set, TinyLB writes the status of each Gateway listener, naming the service port and target port serving it. "+
+			"Leave unset when the GatewayClass's own controller reports listener status.")
+	flag.BoolVar(&countAttachedRoutes, "count-attached-routes", false,
+		"If set with --report-listener-status, each listener's attachedRoutes counts the HTTPRoutes its allowedRoutes "+
+			"(Same, All or Selector namespaces) admits. Needs the HTTPRoute CRD.")
+	flag.BoolVar(&cleanupForeignStatus, "cleanup-foreign-status", false,
+		"If set, the first reconcile of each Gateway removes addresses and custom conditions outside --domain-prefix "+
+			"left by a previous controller. Only enable while taking over Gateways from another implementation.")
+	flag.BoolVar(&useServiceCA, "use-service-ca", false,
+		"If set, reencrypt listener Routes of Gateways without a tinylb.io/destination-ca annotation verify the Gateway "+
+			"with the OpenShift service CA, injected into a tinylb-service-ca ConfigMap in the Gateway's namespace.")
+	flag.BoolVar(&mirrorTLSSecrets, "mirror-tls-secrets", false,
+		"If set, reencrypt listener Routes serve the certificate Secret of their Terminate listener. With --route-namespace, "+
+			"the Secret is copied next to the Route, and the copy deleted with the listener or Gateway.")
+	flag.StringVar(&routeNamespace, "route-namespace", "",
+		"Namespace Gateway listener Routes are created in, instead of the Gateway's namespace. Service Routes stay next "+
+			"to their service. The namespace must exist; see README for the RBAC and router setup it needs.")
+	flag.StringVar(&defaultGatewayHostTemplate, "default-gateway-host-template", "",
+		"Host advertised by Gateways whose listeners name no hostname and whose service has none, built from "+
+			"{gateway} and {namespace}, e.g. {gateway}-{namespace}.apps.example.com. Leave empty to disable.")
+	flag.BoolVar(&gatewayExposureAnnotations, "gateway-exposure-annotations", false,
+		"If set, Gateways are annotated with the service port (tinylb.io/exposed-port) and TLS termination "+
+			"(tinylb.io/exposed-termination) of their service's Route.")
+	flag.StringVar(&programmedRequires, "programmed-requires", string(controller.ProgrammedRequiresNone),
+		"Listeners that must be programmed before a Gateway is reported Programmed=True: none (listeners are not "+
+			"considered), any (at least one) or all. Gateways falling short get reason ListenersNotReady.")
+	flag.DurationVar(&gatewayProgrammedTimeout, "gateway-programmed-timeout", 0,
+		"How long a Gateway's service may go without an external IP before the Gateway is reported Programmed=False "+
+			"with reason Timeout and a Warning event. 0 keeps it Pending indefinitely.")
+	flag.IntVar(&serviceNotFoundMaxAttempts, "service-not-found-max-attempts", controller.DefaultServiceNotFoundMaxAttempts,
+		"Reconciles of a Gateway without a backing service before TinyLB stops retrying until the Gateway changes. 0 retries forever.")
+	flag.StringVar(&mirrorServiceLabels, "mirror-service-labels", "",
+		"Comma-separated service label keys copied onto the service's Route. Entries ending in / select every label with that "+
+			"prefix, e.g. app.kubernetes.io/. TinyLB and Kubernetes system labels are never copied.")
+	flag.IntVar(&maxRoutesPerNamespace, "max-routes-per-namespace", 0,
+		"Maximum number of TinyLB Routes per namespace. Services beyond it get a RouteQuotaExceeded event and no Route. 0 means unlimited.")
+	flag.StringVar(&gatewayAPIVersionFlag, "gateway-api-version", "auto",
+		"Gateway API version to reconcile Gateways through: v1, v1beta1, or auto to use v1 when the cluster serves it.")
+	flag.IntVar(&defaultRouteWeight, "default-route-weight", controller.DefaultRouteWeightUnset,
+		fmt.Sprintf("Backend weight (0-256) set on every Route unless the service's tinylb.io/route-weight annotation "+
+			"overrides it. %d leaves the router default.", controller.DefaultRouteWeightUnset))
+	flag.BoolVar(&warnPlainHTTPPassthrough, "warn-plain-http-passthrough", true,
+		"If set, services whose selected port looks like plain HTTP (80, 8080 or appProtocol http) get a "+
+			"PlainHTTPPassthrough Warning event, since their passthrough Route cannot serve it.")
+	flag.BoolVar(&zeroDowntimeHostChange, "zero-downtime-host-change", false,
+		"If set, a service whose generated host changes (e.g. after --base-domains is reconfigured) is moved to it: "+
+			"a temporary Route serves the new host until a router admits it, the service status switches over, and "+
+			"only then does the service's Route leave the old host. Otherwise existing Routes keep their host.")
+	flag.BoolVar(&routePortByName, "route-port-by-name", false,
+		"If set, Routes reference their service port by name whenever it has one, so renumbering the service's ports "+
+			"needs no Route update. Unnamed ports are still referenced by number.")
+	flag.BoolVar(&validatePorts, "validate-port-endpoints", false,
+		"If set, port selection skips candidate ports without a ready endpoint in the service's EndpointSlices, "+
+			"falling back to the top candidate with a NoReadyPort warning when none has one.")
+	flag.BoolVar(&manageDNS, "manage-dns", false,
+		"If set, each admitted Route's host is published through an external-dns DNSEndpoint pointing at the router's "+
+			"canonical hostname. Ignored unless the externaldns.k8s.io DNSEndpoint CRD is installed.")
+	flag.BoolVar(&routeBindings, "route-bindings", false,
+		"If set, each LoadBalancer service gets a RouteBinding recording its Route and whether it was created and admitted. "+
+			"Requires the RouteBinding CRD.")
+	flag.BoolVar(&ignoreStatusUpdates, "ignore-status-updates", false,
+		"If set, Service and Gateway updates that only change status do not trigger a reconcile. "+
+			"Status written by other controllers is then only picked up on the next resync (see --sync-period).")
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Hour,
+		"How often every watched object is reconciled again regardless of changes.")
+	flag.BoolVar(&recordTargetPort, "record-target-port", false,
+		"If set, each service's tinylb.io/route-target-port annotation records the target port of its Route, "+
+			"and an event reports it whenever it changes.")
+	flag.DurationVar(&serviceDebounce, "service-debounce", 0,
+		"Delay before a Service is reconciled after a change, so rapid successive updates (e.g. during a rollout) "+
+			"coalesce into one reconcile. 0 reconciles immediately.")
+	flag.IntVar(&serviceConcurrency, "service-concurrency", 1,
+		"Number of Services reconciled in parallel. Hosts are claimed in memory, so concurrent reconciles never "+
+			"assign one host to two services.")
+	flag.Float64Var(&routeCreateQPS, "route-create-qps", 0,
+		"Maximum Routes created per second across all Services, so a mass onboarding reprograms the router "+
+			"gradually. Services over the budget are requeued until it refills. 0 disables the limit.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -91,6 +340,9 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	setupLog.Info("Starting TinyLB", "version", version, "commit", commit)
+	controller.SetBuildInfo(version, commit)
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -187,6 +439,7 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "cf6d368e.tinylb.io",
+		Cache:                  cache.Options{SyncPeriod: &syncPeriod, ByObject: controller.CacheByObject()},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -204,24 +457,266 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := (&controller.ServiceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	if errs := validation.IsDNS1123Subdomain(domainPrefix); len(errs) > 0 {
+		setupLog.Error(fmt.Errorf("%s", strings.Join(errs, "; ")), "invalid --domain-prefix")
+		os.Exit(1)
+	}
+	if routeNamespace != "" {
+		if errs := validation.IsDNS1123Label(routeNamespace); len(errs) > 0 {
+			setupLog.Error(fmt.Errorf("%s", strings.Join(errs, "; ")), "invalid --route-namespace")
+			os.Exit(1)
+		}
+	}
+
+	var routeWeight *int32
+	if defaultRouteWeight != controller.DefaultRouteWeightUnset {
+		if err := controller.ValidateRouteWeight(defaultRouteWeight); err != nil {
+			setupLog.Error(err, "invalid --default-route-weight")
+			os.Exit(1)
+		}
+		routeWeight = ptr.To(int32(defaultRouteWeight))
+	}
+
+	managementPortList, err := controller.ParsePortList(managementPorts)
+	if err != nil {
+		setupLog.Error(err, "invalid --management-ports")
+		os.Exit(1)
+	}
+
+	preferredPortList, err := controller.ParsePortList(preferredPorts)
+	if err != nil {
+		setupLog.Error(err, "invalid --preferred-ports")
+		os.Exit(1)
+	}
+
+	if requireOptIn {
+		if optInAnnotation != "" && optInAnnotation != controller.ProviderAnnotation {
+			setupLog.Error(fmt.Errorf("conflicts with --opt-in-annotation=%s", optInAnnotation), "invalid --require-opt-in")
+			os.Exit(1)
+		}
+		optInAnnotation = controller.ProviderAnnotation
+	}
+
+	mergeStrategy, err := controller.ParseIngressMergeStrategy(ingressMergeStrategy)
+	if err != nil {
+		setupLog.Error(err, "invalid --ingress-merge-strategy")
+		os.Exit(1)
+	}
+
+	programmedPolicy, err := controller.ParseProgrammedPolicy(programmedRequires)
+	if err != nil {
+		setupLog.Error(err, "invalid --programmed-requires")
+		os.Exit(1)
+	}
+
+	hostRegistryKey, err := controller.ParseHostRegistry(hostRegistry)
+	if err != nil {
+		setupLog.Error(err, "invalid --host-registry")
+		os.Exit(1)
+	}
+	gatewaySummaryKey, err := controller.ParseGatewaySummary(gatewaySummary)
+	if err != nil {
+		setupLog.Error(err, "invalid --gateway-summary")
+		os.Exit(1)
+	}
+
+	if err := controller.ValidateHostSuffix(hostSuffix); err != nil {
+		setupLog.Error(err, "invalid --host-suffix")
+		os.Exit(1)
+	}
+	if routeCreateQPS < 0 {
+		setupLog.Error(fmt.Errorf("%v is negative", routeCreateQPS), "invalid --route-create-qps")
+		os.Exit(1)
+	}
+	if err := controller.ValidateGatewayHostTemplate(defaultGatewayHostTemplate); err != nil {
+		setupLog.Error(err, "invalid --default-gateway-host-template")
+		os.Exit(1)
+	}
+	if err := controller.ValidateHostStrategy(hostStrategy); err != nil {
+		setupLog.Error(err, "invalid --host-strategy")
+		os.Exit(1)
+	}
+
+	// DNS records are only managed where external-dns reads DNSEndpoints
+	if manageDNS {
+		if _, err := mgr.GetRESTMapper().RESTMapping(controller.DNSEndpointGVK.GroupKind(), controller.DNSEndpointGVK.Version); err != nil {
+			setupLog.Error(err, "DNSEndpoint CRD is not available, not managing DNS records")
+			manageDNS = false
+		}
+	}
+
+	// Export reconcile traces when a collector is configured
+	reconcilerClient := mgr.GetClient()
+	var tracerProvider *sdktrace.TracerProvider
+	if otelEndpoint != "" {
+		exporter, err := otlptracegrpc.New(context.Background(), otlptracegrpc.WithEndpointURL(otelEndpoint))
+		if err != nil {
+			setupLog.Error(err, "unable to create OpenTelemetry trace exporter")
+			os.Exit(1)
+		}
+		tracerProvider = sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "tinylb"))),
+		)
+		otel.SetTracerProvider(tracerProvider)
+		reconcilerClient = controller.NewTracingClient(reconcilerClient)
+		setupLog.Info("Exporting reconcile traces", "otel-endpoint", otelEndpoint)
+	}
+
+	// Attribute every write, status included, to TinyLB's field manager
+	reconcilerClient = client.WithFieldOwner(reconcilerClient, fieldManager)
+
+	// Record Route and status writes for compliance, separately from the controller logs
+	var auditSink controller.AuditSink = controller.NopAuditSink{}
+	var auditFile *os.File
+	switch auditLog {
+	case "":
+	case "-":
+		auditSink = controller.NewJSONAuditSink(os.Stdout)
+	default:
+		auditFile, err = os.OpenFile(auditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			setupLog.Error(err, "unable to open audit log", "audit-log", auditLog)
+			os.Exit(1)
+		}
+		auditSink = controller.NewJSONAuditSink(auditFile)
+	}
+	reconcilerClient = controller.NewAuditClient(reconcilerClient, auditSink, fieldManager)
+
+	serviceReconciler := &controller.ServiceReconciler{
+		Client:           reconcilerClient,
+		Scheme:           mgr.GetScheme(),
+		Recorder:         mgr.GetEventRecorderFor("tinylb"),
+		OptInAnnotation:  optInAnnotation,
+		BaseDomains:      splitList(baseDomains),
+		RouterShards:     routerShards,
+		ManagementPorts:  managementPortList,
+		PreferredPorts:   preferredPortList,
+		PortNamePriority: splitList(portNamePriority),
+		HostSuffix:       hostSuffix,
+
+		HonorExternalDNSHostname: honorExternalDNSHostname,
+		DefaultHostStrategy:      hostStrategy,
+		IngressMergeStrategy:     mergeStrategy,
+		PreserveNodeIngress:      preserveNodeIngress,
+		CompanionServices:        companionServices,
+
+		NamespaceFailureThreshold: namespaceFailureThreshold,
+		NamespaceFailureBackoff:   namespaceFailureBackoff,
+
+		EagerRouteCreation: eagerRouteCreation,
+		HostRegistry:       hostRegistryKey,
+		APIReader:          mgr.GetAPIReader(),
+		DrainGracePeriod:   drainGracePeriod,
+		VerifyDNS:          verifyDNS,
+
+		MirrorServiceLabels:   splitList(mirrorServiceLabels),
+		MaxRoutesPerNamespace: maxRoutesPerNamespace,
+		IgnoreStatusUpdates:   ignoreStatusUpdates,
+		RouteBindings:         routeBindings,
+		DefaultRouteWeight:    routeWeight,
+
+		Debounce:         serviceDebounce,
+		RecordTargetPort: recordTargetPort,
+		ManageDNS:        manageDNS,
+		RoutePortByName:  routePortByName,
+		ValidatePorts:    validatePorts,
+
+		WarnPlainHTTPPassthrough: warnPlainHTTPPassthrough,
+		ZeroDowntimeHostChange:   zeroDowntimeHostChange,
+
+		MaxConcurrentReconciles: serviceConcurrency,
+		RouteCreateQPS:          routeCreateQPS,
+	}
+	if err := serviceReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Service")
 		os.Exit(1)
 	}
 
-	// Add Gateway controller
-	if err := (&controller.GatewayReconciler{
-		Client:                  mgr.GetClient(),
-		Scheme:                  mgr.GetScheme(),
-		SupportedGatewayClasses: []string{"istio"}, // configurable
-		RouteNamespace:          "",                // same namespace as gateway
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+	messageOverrides := map[string]string{}
+	if conditionMessages != "" {
+		if err := json.Unmarshal([]byte(conditionMessages), &messageOverrides); err != nil {
+			setupLog.Error(err, "invalid --condition-messages")
+			os.Exit(1)
+		}
+	}
+	messages, err := controller.NewConditionMessages(messageOverrides)
+	if err != nil {
+		setupLog.Error(err, "invalid --condition-messages")
+		os.Exit(1)
+	}
+
+	gatewayAPIVersion, err := resolveGatewayAPIVersion(mgr.GetRESTMapper(), gatewayAPIVersionFlag)
+	if err != nil {
+		setupLog.Error(err, "invalid --gateway-api-version")
 		os.Exit(1)
 	}
+	gatewayClient := reconcilerClient
+	if gatewayAPIVersion == controller.GatewayAPIVersionV1beta1 {
+		gatewayClient = controller.NewGatewayV1beta1Client(reconcilerClient)
+	}
+	setupLog.Info("Reconciling Gateways", "apiVersion", gatewayv1.GroupName+"/"+gatewayAPIVersion)
+
+	// Add Gateway controllers: one shared by all classes, or one per class
+	if gatewayControllerPerClass && controllerNames != "" {
+		setupLog.Error(fmt.Errorf("classes matched by controllerName cannot be split per class"),
+			"--controller-names cannot be combined with --gateway-controller-per-class")
+		os.Exit(1)
+	}
+	if gatewayControllerName == "" || strings.Contains(gatewayControllerName, "/") {
+		setupLog.Error(fmt.Errorf("controller name %q must be non-empty and contain no /", gatewayControllerName),
+			"invalid --gateway-controller-name")
+		os.Exit(1)
+	}
+	gatewayClassGroups := [][]string{splitList(gatewayClasses)}
+	if gatewayControllerPerClass {
+		gatewayClassGroups = nil
+		for _, class := range splitList(gatewayClasses) {
+			gatewayClassGroups = append(gatewayClassGroups, []string{class})
+		}
+	}
+	var gatewayReconcilers []*controller.GatewayReconciler
+	for _, classes := range gatewayClassGroups {
+		controllerName := gatewayControllerName
+		if gatewayControllerPerClass {
+			controllerName = gatewayControllerName + "-" + classes[0]
+		}
+		gatewayReconciler := &controller.GatewayReconciler{
+			Client:                  gatewayClient,
+			Scheme:                  mgr.GetScheme(),
+			Recorder:                mgr.GetEventRecorderFor("tinylb"),
+			ControllerName:          controllerName,
+			SupportedGatewayClasses: classes,
+			ControllerNames:         splitList(controllerNames),
+			RouteNamespace:          routeNamespace,
+			Messages:                messages,
+			SingleGatewayPerClass:   singleGatewayPerClass,
+			ReportBackendReady:      reportBackendReady,
+			RequireEndpoints:        gatewayRequireEndpoints,
+			RequireRouteAdmission:   gatewayRequireRouteAdmission,
+			ReportListenerStatus:    reportListenerStatus,
+			CountAttachedRoutes:     countAttachedRoutes,
+			DomainPrefix:            domainPrefix,
+
+			GatewayAPIVersion:          gatewayAPIVersion,
+			IgnoreStatusUpdates:        ignoreStatusUpdates,
+			ServiceNotFoundMaxAttempts: serviceNotFoundMaxAttempts,
+			CleanupForeignStatus:       cleanupForeignStatus,
+			UseServiceCA:               useServiceCA,
+			ProgrammedTimeout:          gatewayProgrammedTimeout,
+			GatewaySummary:             gatewaySummaryKey,
+			MirrorTLSSecrets:           mirrorTLSSecrets,
+			APIReader:                  mgr.GetAPIReader(),
+			DefaultGatewayHostTemplate: defaultGatewayHostTemplate,
+			ExposureAnnotations:        gatewayExposureAnnotations,
+			ProgrammedRequires:         programmedPolicy,
+		}
+		if err := gatewayReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Gateway", "classes", classes)
+			os.Exit(1)
+		}
+		gatewayReconcilers = append(gatewayReconcilers, gatewayReconciler)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -240,6 +735,30 @@ func main() {
 		}
 	}
 
+	if debugAddr != "0" && debugAddr != "" {
+		debugMux := http.NewServeMux()
+		managerConfig := controller.ManagerConfig{
+			MetricsBindAddress:        metricsAddr,
+			HealthProbeBindAddress:    probeAddr,
+			DebugBindAddress:          debugAddr,
+			LeaderElection:            enableLeaderElection,
+			SecureMetrics:             secureMetrics,
+			EnableHTTP2:               enableHTTP2,
+			SyncPeriod:                syncPeriod.String(),
+			FieldManager:              fieldManager,
+			AuditLog:                  auditLog,
+			Tracing:                   otelEndpoint != "",
+			GatewayControllerPerClass: gatewayControllerPerClass,
+		}
+		debugMux.Handle("/config", controller.ConfigHandler(
+			controller.NewEffectiveConfig(managerConfig, serviceReconciler, gatewayReconcilers...)))
+		setupLog.Info("Serving debug endpoints", "address", debugAddr)
+		if err := mgr.Add(&controller.DebugServer{Addr: debugAddr, Handler: debugMux}); err != nil {
+			setupLog.Error(err, "unable to add debug server to manager")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -254,4 +773,48 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if tracerProvider != nil {
+		if err := tracerProvider.Shutdown(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush reconcile traces")
+		}
+	}
+	if auditFile != nil {
+		if err := auditFile.Close(); err != nil {
+			setupLog.Error(err, "unable to close audit log")
+		}
+	}
+}
+
+// resolveGatewayAPIVersion returns the Gateway API version to reconcile Gateways through. In auto
+// mode v1 is preferred, falling back to v1beta1 on clusters with Gateway API releases before v1.0.
+func resolveGatewayAPIVersion(mapper meta.RESTMapper, requested string) (string, error) {
+	switch requested {
+	case controller.GatewayAPIVersionV1, controller.GatewayAPIVersionV1beta1:
+		return requested, nil
+	case "auto":
+	default:
+		return "", fmt.Errorf("unknown Gateway API version %q: expected v1, v1beta1 or auto", requested)
+	}
+
+	gatewayKind := gatewayv1.SchemeGroupVersion.WithKind("Gateway").GroupKind()
+	if _, err := mapper.RESTMapping(gatewayKind, controller.GatewayAPIVersionV1); err == nil || !meta.IsNoMatchError(err) {
+		// Errors other than a missing version are left for the controller to report
+		return controller.GatewayAPIVersionV1, nil
+	}
+	if _, err := mapper.RESTMapping(gatewayKind, controller.GatewayAPIVersionV1beta1); err == nil {
+		return controller.GatewayAPIVersionV1beta1, nil
+	}
+	return controller.GatewayAPIVersionV1, nil
+}
+
+// splitList parses a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AuditEntry records one write TinyLB made to the cluster
+type AuditEntry struct {
+	Time         time.Time `json:"time"`
+	FieldManager string    `json:"fieldManager"`
+	Action       string    `json:"action"` // create, update, patch, delete, update-status or patch-status
+	Kind         string    `json:"kind"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Name         string    `json:"name"`
+	Error        string    `json:"error,omitempty"` // set when the API server rejected the write
+}
+
+// AuditSink receives audit entries; implementations must be safe for concurrent use
+type AuditSink interface {
+	Record(entry AuditEntry)
+}
+
+// NopAuditSink discards every entry; it is the sink used when no audit log is configured
+type NopAuditSink struct{}
+
+func (NopAuditSink) Record(AuditEntry) {}
+
+// JSONAuditSink writes each entry to w as one JSON object per line
+type JSONAuditSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewJSONAuditSink returns a sink writing JSON lines to w
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{encoder: json.NewEncoder(w)}
+}
+
+func (s *JSONAuditSink) Record(entry AuditEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// An audit write failing must not fail the reconcile that made the change
+	_ = s.encoder.Encode(entry)
+}
+
+// DefaultFieldManager is the field manager TinyLB's writes are attributed to unless configured otherwise
+const DefaultFieldManager = "tinylb"
+
+// auditClient wraps a client so every Route write and every status write is recorded in a sink
+// Unlike the reconcilers' logs, the audit log only carries mutations, attributed to fieldManager
+type auditClient struct {
+	client.Client
+	sink         AuditSink
+	fieldManager string
+}
+
+// NewAuditClient returns c recording its Route and status writes in sink (nil = NopAuditSink)
+func NewAuditClient(c client.Client, sink AuditSink, fieldManager string) client.Client {
+	if sink == nil {
+		sink = NopAuditSink{}
+	}
+	return &auditClient{Client: c, sink: sink, fieldManager: fieldManager}
+}
+
+// record adds an entry for action on obj
+func (c *auditClient) record(action string, obj client.Object, err error) {
+	entry := AuditEntry{
+		Time:         time.Now().UTC(),
+		FieldManager: c.fieldManager,
+		Action:       action,
+		Kind:         reflect.Indirect(reflect.ValueOf(obj)).Type().Name(),
+		Namespace:    obj.GetNamespace(),
+		Name:         obj.GetName(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	c.sink.Record(entry)
+}
+
+// audited reports whether writes to obj itself, rather than its status, are recorded
+func audited(obj client.Object) bool {
+	_, ok := obj.(*routev1.Route)
+	return ok
+}
+
+func (c *auditClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	if audited(obj) {
+		c.record("create", obj, err)
+	}
+	return err
+}
+
+func (c *auditClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	if audited(obj) {
+		c.record("update", obj, err)
+	}
+	return err
+}
+
+func (c *auditClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	if audited(obj) {
+		c.record("patch", obj, err)
+	}
+	return err
+}
+
+func (c *auditClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	if audited(obj) {
+		c.record("delete", obj, err)
+	}
+	return err
+}
+
+func (c *auditClient) Status() client.SubResourceWriter {
+	return &auditStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+// auditStatusWriter records status subresource writes of every kind
+type auditStatusWriter struct {
+	client.SubResourceWriter
+	client *auditClient
+}
+
+func (w *auditStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	err := w.SubResourceWriter.Update(ctx, obj, opts...)
+	w.client.record("update-status", obj, err)
+	return err
+}
+
+func (w *auditStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	err := w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+	w.client.record("patch-status", obj, err)
+	return err
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// serviceNotFoundInitialDelay is the first requeue delay for a Gateway whose service is missing
+	serviceNotFoundInitialDelay = 30 * time.Second
+
+	// serviceNotFoundMaxDelay caps the requeue delay for a Gateway whose service is missing
+	serviceNotFoundMaxDelay = 5 * time.Minute
+
+	// DefaultServiceNotFoundMaxAttempts is how many reconciles a Gateway without a backing service
+	// gets before TinyLB stops retrying until the Gateway changes, unless configured otherwise
+	DefaultServiceNotFoundMaxAttempts = 10
+)
+
+// serviceNotFoundBackoff counts consecutive reconciles of a Gateway that found no backing service,
+// so the requeue delay can grow instead of polling every 30s for a service that may never come.
+// The count restarts when the Gateway's generation changes.
+type serviceNotFoundBackoff struct {
+	mu       sync.Mutex
+	attempts map[types.NamespacedName]serviceNotFoundAttempts
+}
+
+type serviceNotFoundAttempts struct {
+	generation int64
+	count      int
+}
+
+// next records another miss for the Gateway and returns the number of consecutive misses
+func (b *serviceNotFoundBackoff) next(key types.NamespacedName, generation int64) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.attempts == nil {
+		b.attempts = map[types.NamespacedName]serviceNotFoundAttempts{}
+	}
+	attempts := b.attempts[key]
+	if attempts.generation != generation {
+		attempts = serviceNotFoundAttempts{generation: generation}
+	}
+	attempts.count++
+	b.attempts[key] = attempts
+	return attempts.count
+}
+
+// reset forgets the misses of a Gateway once its service exists
+func (b *serviceNotFoundBackoff) reset(key types.NamespacedName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.attempts, key)
+}
+
+// serviceNotFoundDelay returns the requeue delay after the given number of misses:
+// 30s, 1m, 2m, 4m, then 5m
+func serviceNotFoundDelay(attempt int) time.Duration {
+	delay := serviceNotFoundInitialDelay
+	for i := 1; i < attempt && delay < serviceNotFoundMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, serviceNotFoundMaxDelay)
+}
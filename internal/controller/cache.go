@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CacheByObject scopes the manager's cache for kinds TinyLB only needs a few objects of.
+// Of Secrets, only the TLS mirrors TinyLB labels as its own are cached and watched; the
+// listener Secrets they copy are read directly through GatewayReconciler.APIReader. Of
+// ConfigMaps, only TinyLB's own (the host registry, the Gateway summary and the service CA
+// bundles) are cached; the destination CA ConfigMaps users name are read directly too.
+func CacheByObject() map[client.Object]cache.ByObject {
+	managed := labels.SelectorFromSet(labels.Set{managedLabel: "true"})
+	return map[client.Object]cache.ByObject{
+		&corev1.Secret{}:    {Label: managed},
+		&corev1.ConfigMap{}: {Label: managed},
+	}
+}
+
+// getManagedConfigMap reads one of TinyLB's own ConfigMaps. Only labelled ConfigMaps are cached,
+// so when the cache does not have it, it is looked up through reader; one created before TinyLB
+// labelled its ConfigMaps is labelled then, after which the cache serves it.
+func getManagedConfigMap(ctx context.Context, c client.Client, reader client.Reader, key types.NamespacedName, configMap *corev1.ConfigMap) error {
+	err := c.Get(ctx, key, configMap)
+	if !errors.IsNotFound(err) || reader == nil {
+		return err
+	}
+	if err := reader.Get(ctx, key, configMap); err != nil {
+		return err
+	}
+	if configMap.Labels[managedLabel] == "true" {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	metav1.SetMetaDataLabel(&configMap.ObjectMeta, managedLabel, "true")
+	log.FromContext(ctx).Info("Labelling ConfigMap as TinyLB's own", "configMap", key.String())
+	return c.Update(ctx, configMap)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultNamespaceFailureThreshold is the number of consecutive Route creation failures in a
+	// namespace after which its services are backed off
+	DefaultNamespaceFailureThreshold = 5
+
+	// DefaultNamespaceFailureBackoff is how long a namespace is backed off once its breaker opens
+	DefaultNamespaceFailureBackoff = 5 * time.Minute
+)
+
+// namespaceCircuitBreaker stops retrying Route creation in a namespace that keeps failing (e.g.
+// because its quota is exhausted), so one broken namespace does not burn API calls for every
+// service in it
+type namespaceCircuitBreaker struct {
+	mu        sync.Mutex
+	failures  map[string]int
+	openUntil map[string]time.Time
+}
+
+// remaining returns how long namespace is still backed off, or zero when reconciles may proceed
+func (b *namespaceCircuitBreaker) remaining(namespace string, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until, ok := b.openUntil[namespace]; ok && now.Before(until) {
+		return until.Sub(now)
+	}
+	return 0
+}
+
+// recordFailure counts a failed Route creation in namespace and opens the breaker for backoff
+// once threshold consecutive failures are reached. It reports whether this failure is the one
+// that first opened the breaker, so the caller can warn once rather than on every retry.
+func (b *namespaceCircuitBreaker) recordFailure(namespace string, threshold int, backoff time.Duration, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures == nil {
+		b.failures = map[string]int{}
+		b.openUntil = map[string]time.Time{}
+	}
+	b.failures[namespace]++
+	if b.failures[namespace] < threshold {
+		return false
+	}
+	b.openUntil[namespace] = now.Add(backoff)
+	return b.failures[namespace] == threshold
+}
+
+// recordSuccess closes the breaker for namespace and forgets its failures
+func (b *namespaceCircuitBreaker) recordSuccess(namespace string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.failures, namespace)
+	delete(b.openUntil, namespace)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// companionServiceSuffix is appended to a service's name to name its companion service
+const companionServiceSuffix = "-tinylb"
+
+// companionServiceName returns the name of the ClusterIP service a service's Route targets with
+// CompanionServices, truncated with a hash like host labels when the service name is long
+func companionServiceName(service *corev1.Service) string {
+	return hostLabel(service.Name, companionServiceSuffix)
+}
+
+// routeTargetsService reports whether route sends its traffic to service, directly or through
+// the service's companion
+func routeTargetsService(route *routev1.Route, service *corev1.Service) bool {
+	return route.Namespace == service.Namespace &&
+		(route.Spec.To.Name == service.Name || route.Spec.To.Name == companionServiceName(service))
+}
+
+// companionPorts copies a service's ports without their node ports, which a ClusterIP service cannot have
+func companionPorts(service *corev1.Service) []corev1.ServicePort {
+	ports := make([]corev1.ServicePort, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		port.NodePort = 0
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// ownsCompanion reports whether companion was created by TinyLB for service
+func ownsCompanion(companion, service *corev1.Service) bool {
+	return companion.Labels[managedLabel] == "true" && companion.Labels[serviceUIDLabel] == string(service.UID)
+}
+
+// syncCompanionService creates or updates the ClusterIP service mirroring a LoadBalancer service's
+// selector and ports, for meshes where the LoadBalancer service cannot be the Route target. It
+// returns the companion's name for the Route to target, or ErrCompanionConflict when a service
+// TinyLB did not create for this one already has the name.
+func (r *ServiceReconciler) syncCompanionService(ctx context.Context, service *corev1.Service) (string, error) {
+	logger := log.FromContext(ctx)
+	name := companionServiceName(service)
+
+	var companion corev1.Service
+	err := r.Get(ctx, types.NamespacedName{Namespace: service.Namespace, Name: name}, &companion)
+	if errors.IsNotFound(err) {
+		companion = corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: service.Namespace,
+				Labels: map[string]string{
+					managedLabel:    "true",
+					serviceLabel:    service.Name,
+					serviceUIDLabel: string(service.UID),
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeClusterIP,
+				Selector: service.Spec.Selector,
+				Ports:    companionPorts(service),
+			},
+		}
+		if err := controllerutil.SetOwnerReference(service, &companion, r.Scheme); err != nil {
+			return "", err
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := r.Create(ctx, &companion); err != nil {
+			return "", err
+		}
+		logger.Info("Created companion service", "service", service.Name, "companion", name)
+		return name, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !ownsCompanion(&companion, service) {
+		return "", fmt.Errorf("%w: service %s/%s is not TinyLB's companion of %s", ErrCompanionConflict, companion.Namespace, name, service.Name)
+	}
+
+	ports := companionPorts(service)
+	if equality.Semantic.DeepEqual(companion.Spec.Selector, service.Spec.Selector) && equality.Semantic.DeepEqual(companion.Spec.Ports, ports) {
+		return name, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	companion.Spec.Selector = service.Spec.Selector
+	companion.Spec.Ports = ports
+	if err := r.Update(ctx, &companion); err != nil {
+		return "", err
+	}
+	logger.Info("Updated companion service", "service", service.Name, "companion", name)
+	return name, nil
+}
+
+// deleteCompanionService removes the companion TinyLB created for service, once companions are disabled
+func (r *ServiceReconciler) deleteCompanionService(ctx context.Context, service *corev1.Service) error {
+	var companion corev1.Service
+	err := r.Get(ctx, types.NamespacedName{Namespace: service.Namespace, Name: companionServiceName(service)}, &companion)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !ownsCompanion(&companion, service) {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := r.Delete(ctx, &companion); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	log.FromContext(ctx).Info("Deleted companion service", "service", service.Name, "companion", companion.Name)
+	return nil
+}
+
+// syncRouteTarget points an existing Route at name when companion services are turned on or off
+func (r *ServiceReconciler) syncRouteTarget(ctx context.Context, route *routev1.Route, name string) error {
+	if route.Spec.To.Name == name {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(route.DeepCopy())
+	route.Spec.To.Name = name
+	log.FromContext(ctx).Info("Updating Route target", "route", route.Name, "service", name)
+	return r.Patch(ctx, route, patch)
+}
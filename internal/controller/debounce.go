@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// debounceQueue delays every event-driven Add by a fixed interval. The delaying queue keeps a
+// single entry per object, so a burst of events for the same object (e.g. a Service flapping
+// during a rollout) coalesces into one reconcile. Requeues and rate-limited retries are unaffected.
+type debounceQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	delay time.Duration
+}
+
+// Add schedules item once the debounce interval has passed
+func (q debounceQueue) Add(item reconcile.Request) {
+	q.AddAfter(item, q.delay)
+}
+
+// debounceOptions returns controller options whose queue debounces events by delay
+func debounceOptions(delay time.Duration) controller.Options {
+	return controller.Options{
+		NewQueue: func(controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+			return newDebounceQueue(controllerName, rateLimiter, delay)
+		},
+	}
+}
+
+// newDebounceQueue builds the controller's default rate limited queue, wrapped to debounce by delay
+func newDebounceQueue(name string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request], delay time.Duration) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return debounceQueue{
+		TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter,
+			workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{Name: name}),
+		delay: delay,
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EffectiveConfig is the configuration the running controller resolved from its flags. Every
+// setting is listed explicitly, so nothing reaches /config unless it is added here.
+type EffectiveConfig struct {
+	Manager  ManagerConfig   `json:"manager"`
+	Service  ServiceConfig   `json:"service"`
+	Gateways []GatewayConfig `json:"gateways"`
+}
+
+// ManagerConfig is the configuration of the manager running the reconcilers
+type ManagerConfig struct {
+	MetricsBindAddress        string `json:"metricsBindAddress"`
+	HealthProbeBindAddress    string `json:"healthProbeBindAddress"`
+	DebugBindAddress          string `json:"debugBindAddress"`
+	LeaderElection            bool   `json:"leaderElection"`
+	SecureMetrics             bool   `json:"secureMetrics"`
+	EnableHTTP2               bool   `json:"enableHTTP2"`
+	SyncPeriod                string `json:"syncPeriod"`
+	FieldManager              string `json:"fieldManager"`
+	AuditLog                  string `json:"auditLog"`
+	Tracing                   bool   `json:"tracing"` // whether reconcile traces are exported; the collector URL may carry credentials
+	GatewayControllerPerClass bool   `json:"gatewayControllerPerClass"`
+}
+
+// ServiceConfig is the configuration of the Service reconciler
+type ServiceConfig struct {
+	OptInAnnotation           string   `json:"optInAnnotation"`
+	BaseDomains               []string `json:"baseDomains"`
+	RouterShards              int      `json:"routerShards"`
+	ManagementPorts           []int32  `json:"managementPorts"`
+	PreferredPorts            []int32  `json:"preferredPorts"`
+	PortNamePriority          []string `json:"portNamePriority"`
+	HostSuffix                string   `json:"hostSuffix"`
+	HonorExternalDNSHostname  bool     `json:"honorExternalDNSHostname"`
+	DefaultHostStrategy       string   `json:"defaultHostStrategy"`
+	IngressMergeStrategy      string   `json:"ingressMergeStrategy"`
+	PreserveNodeIngress       bool     `json:"preserveNodeIngress"`
+	CompanionServices         bool     `json:"companionServices"`
+	NamespaceFailureThreshold int      `json:"namespaceFailureThreshold"`
+	NamespaceFailureBackoff   string   `json:"namespaceFailureBackoff"`
+	EagerRouteCreation        bool     `json:"eagerRouteCreation"`
+	HostRegistry              string   `json:"hostRegistry"`
+	DrainGracePeriod          string   `json:"drainGracePeriod"`
+	VerifyDNS                 bool     `json:"verifyDNS"`
+	MirrorServiceLabels       []string `json:"mirrorServiceLabels"`
+	MaxRoutesPerNamespace     int      `json:"maxRoutesPerNamespace"`
+	IgnoreStatusUpdates       bool     `json:"ignoreStatusUpdates"`
+	RouteBindings             bool     `json:"routeBindings"`
+	DefaultRouteWeight        *int32   `json:"defaultRouteWeight"`
+	Debounce                  string   `json:"debounce"`
+	RecordTargetPort          bool     `json:"recordTargetPort"`
+	ManageDNS                 bool     `json:"manageDNS"`
+	RoutePortByName           bool     `json:"routePortByName"`
+	ValidatePorts             bool     `json:"validatePorts"`
+	WarnPlainHTTPPassthrough  bool     `json:"warnPlainHTTPPassthrough"`
+	ZeroDowntimeHostChange    bool     `json:"zeroDowntimeHostChange"`
+	MaxConcurrentReconciles   int      `json:"maxConcurrentReconciles"`
+	RouteCreateQPS            float64  `json:"routeCreateQPS"`
+}
+
+// GatewayConfig is the configuration of one Gateway reconciler
+type GatewayConfig struct {
+	ControllerName             string            `json:"controllerName"`
+	SupportedGatewayClasses    []string          `json:"supportedGatewayClasses"`
+	ControllerNames            []string          `json:"controllerNames"`
+	RouteNamespace             string            `json:"routeNamespace"`
+	Messages                   map[string]string `json:"messages"`
+	SingleGatewayPerClass      bool              `json:"singleGatewayPerClass"`
+	ReportBackendReady         bool              `json:"reportBackendReady"`
+	RequireEndpoints           bool              `json:"requireEndpoints"`
+	RequireRouteAdmission      bool              `json:"requireRouteAdmission"`
+	ReportListenerStatus       bool              `json:"reportListenerStatus"`
+	CountAttachedRoutes        bool              `json:"countAttachedRoutes"`
+	DomainPrefix               string            `json:"domainPrefix"`
+	GatewayAPIVersion          string            `json:"gatewayAPIVersion"`
+	IgnoreStatusUpdates        bool              `json:"ignoreStatusUpdates"`
+	ServiceNotFoundMaxAttempts int               `json:"serviceNotFoundMaxAttempts"`
+	CleanupForeignStatus       bool              `json:"cleanupForeignStatus"`
+	UseServiceCA               bool              `json:"useServiceCA"`
+	ProgrammedTimeout          string            `json:"programmedTimeout"`
+	GatewaySummary             string            `json:"gatewaySummary"`
+	MirrorTLSSecrets           bool              `json:"mirrorTLSSecrets"`
+	DefaultGatewayHostTemplate string            `json:"defaultGatewayHostTemplate"`
+	ExposureAnnotations        bool              `json:"exposureAnnotations"`
+	ProgrammedRequires         string            `json:"programmedRequires"`
+}
+
+// NewEffectiveConfig captures the manager configuration and that of the given reconcilers.
+// Durations and object keys are shown the way they are written on the command line.
+func NewEffectiveConfig(manager ManagerConfig, service *ServiceReconciler, gateways ...*GatewayReconciler) EffectiveConfig {
+	config := EffectiveConfig{
+		Manager: manager,
+		Service: ServiceConfig{
+			OptInAnnotation:           service.OptInAnnotation,
+			BaseDomains:               service.BaseDomains,
+			RouterShards:              service.RouterShards,
+			ManagementPorts:           service.ManagementPorts,
+			PreferredPorts:            service.PreferredPorts,
+			PortNamePriority:          service.PortNamePriority,
+			HostSuffix:                service.HostSuffix,
+			HonorExternalDNSHostname:  service.HonorExternalDNSHostname,
+			DefaultHostStrategy:       service.DefaultHostStrategy,
+			IngressMergeStrategy:      string(service.IngressMergeStrategy),
+			PreserveNodeIngress:       service.PreserveNodeIngress,
+			CompanionServices:         service.CompanionServices,
+			NamespaceFailureThreshold: service.NamespaceFailureThreshold,
+			NamespaceFailureBackoff:   service.NamespaceFailureBackoff.String(),
+			EagerRouteCreation:        service.EagerRouteCreation,
+			HostRegistry:              configObjectKey(service.HostRegistry),
+			DrainGracePeriod:          service.DrainGracePeriod.String(),
+			VerifyDNS:                 service.VerifyDNS,
+			MirrorServiceLabels:       service.MirrorServiceLabels,
+			MaxRoutesPerNamespace:     service.MaxRoutesPerNamespace,
+			IgnoreStatusUpdates:       service.IgnoreStatusUpdates,
+			RouteBindings:             service.RouteBindings,
+			DefaultRouteWeight:        service.DefaultRouteWeight,
+			Debounce:                  service.Debounce.String(),
+			RecordTargetPort:          service.RecordTargetPort,
+			ManageDNS:                 service.ManageDNS,
+			RoutePortByName:           service.RoutePortByName,
+			ValidatePorts:             service.ValidatePorts,
+			WarnPlainHTTPPassthrough:  service.WarnPlainHTTPPassthrough,
+			ZeroDowntimeHostChange:    service.ZeroDowntimeHostChange,
+			MaxConcurrentReconciles:   service.MaxConcurrentReconciles,
+			RouteCreateQPS:            service.RouteCreateQPS,
+		},
+		Gateways: []GatewayConfig{},
+	}
+	for _, gateway := range gateways {
+		config.Gateways = append(config.Gateways, GatewayConfig{
+			ControllerName:             gateway.name(),
+			SupportedGatewayClasses:    gateway.SupportedGatewayClasses,
+			ControllerNames:            gateway.ControllerNames,
+			RouteNamespace:             gateway.RouteNamespace,
+			Messages:                   gateway.Messages.configValue(),
+			SingleGatewayPerClass:      gateway.SingleGatewayPerClass,
+			ReportBackendReady:         gateway.ReportBackendReady,
+			RequireEndpoints:           gateway.RequireEndpoints,
+			RequireRouteAdmission:      gateway.RequireRouteAdmission,
+			ReportListenerStatus:       gateway.ReportListenerStatus,
+			CountAttachedRoutes:        gateway.CountAttachedRoutes,
+			DomainPrefix:               gateway.domainPrefix(),
+			GatewayAPIVersion:          gateway.GatewayAPIVersion,
+			IgnoreStatusUpdates:        gateway.IgnoreStatusUpdates,
+			ServiceNotFoundMaxAttempts: gateway.ServiceNotFoundMaxAttempts,
+			CleanupForeignStatus:       gateway.CleanupForeignStatus,
+			UseServiceCA:               gateway.UseServiceCA,
+			ProgrammedTimeout:          gateway.ProgrammedTimeout.String(),
+			GatewaySummary:             configObjectKey(gateway.GatewaySummary),
+			MirrorTLSSecrets:           gateway.MirrorTLSSecrets,
+			DefaultGatewayHostTemplate: gateway.DefaultGatewayHostTemplate,
+			ExposureAnnotations:        gateway.ExposureAnnotations,
+			ProgrammedRequires:         string(gateway.ProgrammedRequires),
+		})
+	}
+	return config
+}
+
+// configObjectKey shows an optional object key as namespace/name, or empty when unset
+func configObjectKey(key types.NamespacedName) string {
+	if key.Name == "" {
+		return ""
+	}
+	return key.String()
+}
+
+// ConfigHandler serves the effective configuration as JSON. None of it is secret: only the
+// settings listed in EffectiveConfig are published.
+func ConfigHandler(config EffectiveConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			http.Error(w, "encoding configuration: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(append(body, '\n'))
+	})
+}
+
+// DebugServer serves TinyLB's debug endpoints. It runs on every replica, leader or not, so each
+// one can be inspected.
+type DebugServer struct {
+	Addr    string
+	Handler http.Handler
+}
+
+// Start implements manager.Runnable
+func (s *DebugServer) Start(ctx context.Context) error {
+	server := &http.Server{Addr: s.Addr, Handler: s.Handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (s *DebugServer) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// dnsUnresolvableAnnotation records an advertised host that did not resolve when last checked
+	dnsUnresolvableAnnotation = "tinylb.io/dns-unresolvable"
+
+	// dnsLookupTimeout bounds each host lookup so a slow resolver cannot stall the reconcile
+	dnsLookupTimeout = 2 * time.Second
+
+	// dnsRecheckInterval is how often an unresolvable host is looked up again
+	dnsRecheckInterval = 5 * time.Minute
+)
+
+// HostResolver looks up the addresses of a host; *net.Resolver satisfies it
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// verifyDNS checks that an advertised host resolves. A host that does not usually means
+// external-dns (or a wildcard record) is not wired up for the base domain, which otherwise
+// looks like a healthy, admitted Route.
+func (r *ServiceReconciler) verifyDNS(ctx context.Context, service *corev1.Service, host string) (ctrl.Result, error) {
+	resolver := r.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+	_, lookupErr := resolver.LookupHost(lookupCtx, host)
+	cancel()
+
+	// Only touch the service when the outcome changed, so the Warning is emitted once per host
+	recorded := service.Annotations[dnsUnresolvableAnnotation]
+	switch {
+	case lookupErr == nil && recorded == "":
+		return ctrl.Result{}, nil
+	case lookupErr != nil && recorded == host:
+		return ctrl.Result{RequeueAfter: dnsRecheckInterval}, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return ctrl.Result{}, err
+	}
+	patch := client.MergeFrom(service.DeepCopy())
+	if lookupErr != nil {
+		if service.Annotations == nil {
+			service.Annotations = map[string]string{}
+		}
+		service.Annotations[dnsUnresolvableAnnotation] = host
+	} else {
+		delete(service.Annotations, dnsUnresolvableAnnotation)
+	}
+	if err := r.Patch(ctx, service, patch); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if lookupErr != nil {
+		log.FromContext(ctx).Info("Advertised host does not resolve", "service", service.Name, "host", host, "error", lookupErr.Error())
+		r.Recorder.Eventf(service, corev1.EventTypeWarning, "HostNotResolvable",
+			"Host %s does not resolve; check the DNS records for its base domain: %v", host, lookupErr)
+		return ctrl.Result{RequeueAfter: dnsRecheckInterval}, nil
+	}
+	log.FromContext(ctx).Info("Advertised host resolves again", "service", service.Name, "host", host)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DNSEndpointGVK is external-dns's DNSEndpoint, read by external-dns's CRD source. TinyLB handles
+// it as unstructured so external-dns is not a build dependency.
+var DNSEndpointGVK = schema.GroupVersionKind{Group: "externaldns.k8s.io", Version: "v1alpha1", Kind: "DNSEndpoint"}
+
+// newDNSEndpoint returns an empty DNSEndpoint
+func newDNSEndpoint() *unstructured.Unstructured {
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(DNSEndpointGVK)
+	return endpoint
+}
+
+// routerCanonicalHostname returns the canonical hostname of the first router that admitted route
+func routerCanonicalHostname(route *routev1.Route) string {
+	for _, ingress := range route.Status.Ingress {
+		if ingress.RouterCanonicalHostname == "" {
+			continue
+		}
+		for _, condition := range ingress.Conditions {
+			if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionTrue {
+				return ingress.RouterCanonicalHostname
+			}
+		}
+	}
+	return ""
+}
+
+// dnsEndpoints returns the DNSEndpoint spec.endpoints pointing host at the router's canonical hostname
+func dnsEndpoints(host, canonical string) []any {
+	return []any{map[string]any{
+		"dnsName":    host,
+		"recordType": "CNAME",
+		"targets":    []any{canonical},
+	}}
+}
+
+// syncDNSEndpoint maintains a DNSEndpoint pointing route's host at the canonical hostname of the
+// router that admitted it, so external-dns publishes the record. It shares the Route's name and is
+// owned by the service, so it goes away with it. Nothing is written until a router admits the Route;
+// the Route's status update then triggers another reconcile.
+func (r *ServiceReconciler) syncDNSEndpoint(ctx context.Context, service *corev1.Service, route *routev1.Route) error {
+	canonical := routerCanonicalHostname(route)
+	if canonical == "" || route.Spec.Host == "" {
+		return nil
+	}
+	desired := dnsEndpoints(route.Spec.Host, canonical)
+
+	endpoint := newDNSEndpoint()
+	err := r.Get(ctx, client.ObjectKey{Namespace: service.Namespace, Name: RouteName(service)}, endpoint)
+	if errors.IsNotFound(err) {
+		r.warnings.resolve(service, "DNSEndpointConflict")
+		endpoint = newDNSEndpoint()
+		endpoint.SetName(RouteName(service))
+		endpoint.SetNamespace(service.Namespace)
+		endpoint.SetLabels(map[string]string{
+			managedLabel:    "true",
+			serviceLabel:    service.Name,
+			serviceUIDLabel: string(service.UID),
+		})
+		if err := unstructured.SetNestedSlice(endpoint.Object, desired, "spec", "endpoints"); err != nil {
+			return err
+		}
+		if err := controllerutil.SetOwnerReference(service, endpoint, r.Scheme); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, endpoint); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Created DNSEndpoint", "dnsEndpoint", endpoint.GetName(), "host", route.Spec.Host, "target", canonical)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Never take over a DNSEndpoint someone else created under the same name
+	if labels := endpoint.GetLabels(); labels[managedLabel] != "true" || labels[serviceUIDLabel] != string(service.UID) {
+		r.warnings.warnf(r.Recorder, service, "DNSEndpointConflict", "DNSEndpointConflict",
+			"DNSEndpoint %s is not managed by TinyLB; not publishing %s", endpoint.GetName(), route.Spec.Host)
+		return nil
+	}
+	r.warnings.resolve(service, "DNSEndpointConflict")
+
+	current, _, err := unstructured.NestedSlice(endpoint.Object, "spec", "endpoints")
+	if err != nil {
+		return fmt.Errorf("reading DNSEndpoint %s: %w", endpoint.GetName(), err)
+	}
+	if equality.Semantic.DeepEqual(current, desired) {
+		return nil
+	}
+	if err := unstructured.SetNestedSlice(endpoint.Object, desired, "spec", "endpoints"); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := r.Update(ctx, endpoint); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Updated DNSEndpoint", "dnsEndpoint", endpoint.GetName(), "host", route.Spec.Host, "target", canonical)
+	return nil
+}
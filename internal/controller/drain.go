@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// drainAnnotation stops TinyLB advertising a service's host after the drain grace period,
+	// while keeping its Route, so DNS TTLs can expire before the Route is moved or removed
+	drainAnnotation = "tinylb.io/drain"
+
+	// drainStartedAnnotation records when TinyLB first saw the drain request
+	drainStartedAnnotation = "tinylb.io/drain-started"
+
+	// DefaultDrainGracePeriod is how long a draining service keeps advertising its host
+	DefaultDrainGracePeriod = 5 * time.Minute
+)
+
+// drain advances the drain sequence of a service whose Route serves hostname: the drain start is
+// recorded first, and once the grace period has passed TinyLB's ingress entry is cleared. The
+// Gateway backed by the service then loses its address on its next reconcile.
+func (r *ServiceReconciler) drain(ctx context.Context, service *corev1.Service, hostname string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	grace := r.DrainGracePeriod
+	if grace <= 0 {
+		grace = DefaultDrainGracePeriod
+	}
+
+	started, err := time.Parse(time.RFC3339, service.Annotations[drainStartedAnnotation])
+	if err != nil {
+		if err := ctx.Err(); err != nil {
+			return ctrl.Result{}, err
+		}
+		patch := client.MergeFrom(service.DeepCopy())
+		service.Annotations[drainStartedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		if err := r.Patch(ctx, service, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Draining service", "service", service.Name, "gracePeriod", grace)
+		return ctrl.Result{RequeueAfter: grace}, nil
+	}
+
+	if remaining := time.Until(started.Add(grace)); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	ingress := slices.DeleteFunc(slices.Clone(service.Status.LoadBalancer.Ingress), func(entry corev1.LoadBalancerIngress) bool {
+		return entry.Hostname == hostname
+	})
+	if equality.Semantic.DeepEqual(ingress, service.Status.LoadBalancer.Ingress) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return ctrl.Result{}, err
+	}
+	serviceCopy := service.DeepCopy()
+	serviceCopy.Status.LoadBalancer.Ingress = ingress
+	if err := r.Status().Update(ctx, serviceCopy); err != nil {
+		logger.Error(err, "Unable to update Service status")
+		return r.statusForbidden.handle(ctx, r.Recorder, service, "services/status", err)
+	}
+	logger.Info("Drain grace period elapsed, stopped advertising host", "service", service.Name, "hostname", hostname)
+	r.Recorder.Eventf(service, corev1.EventTypeNormal, "Drained",
+		"Stopped advertising %s; the Route is kept until %s is removed", hostname, drainAnnotation)
+	return ctrl.Result{}, nil
+}
+
+// clearDrain forgets the drain start once a service is no longer draining
+func (r *ServiceReconciler) clearDrain(ctx context.Context, service *corev1.Service) error {
+	if _, ok := service.Annotations[drainStartedAnnotation]; !ok {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(service.DeepCopy())
+	delete(service.Annotations, drainStartedAnnotation)
+	return r.Patch(ctx, service, patch)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// serviceHasReadyEndpoints reports whether any EndpointSlice of service lists a ready endpoint
+func serviceHasReadyEndpoints(ctx context.Context, c client.Client, service *corev1.Service) (bool, error) {
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := c.List(ctx, &endpointSlices, client.InNamespace(service.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: service.Name}); err != nil {
+		return false, err
+	}
+	for _, slice := range endpointSlices.Items {
+		for _, endpoint := range slice.Endpoints {
+			// A nil ready condition means unknown, which consumers are told to treat as ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// readyPortNames returns the names of the service ports that have a ready endpoint in the
+// service's EndpointSlices. Slices name their ports after the service ports (unnamed ports as
+// ""), and only list a port when the backing pods expose its target port.
+func readyPortNames(ctx context.Context, c client.Client, service *corev1.Service) (map[string]bool, error) {
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := c.List(ctx, &endpointSlices, client.InNamespace(service.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: service.Name}); err != nil {
+		return nil, err
+	}
+	ready := map[string]bool{}
+	for _, slice := range endpointSlices.Items {
+		if !slices.ContainsFunc(slice.Endpoints, func(endpoint discoveryv1.Endpoint) bool {
+			return endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+		}) {
+			continue
+		}
+		for _, port := range slice.Ports {
+			if port.Port != nil {
+				ready[ptr.Deref(port.Name, "")] = true
+			}
+		}
+	}
+	return ready, nil
+}
+
+// selectRoutePort selects the Route's service port. With ValidatePorts, the candidates are tried
+// in selectHTTPPort's order and the first with a ready endpoint wins; when none has one, the top
+// candidate is kept and a Warning is emitted once, since the pods may simply not be up yet.
+func (r *ServiceReconciler) selectRoutePort(ctx context.Context, service *corev1.Service) (*corev1.ServicePort, error) {
+	candidates := rankHTTPPorts(service.Spec.Ports, r.PreferredPorts, r.PortNamePriority, r.skippedPorts(service))
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	if !r.ValidatePorts {
+		return &candidates[0], nil
+	}
+
+	ready, err := readyPortNames(ctx, r.Client, service)
+	if err != nil {
+		return nil, err
+	}
+	for i, port := range candidates {
+		if ready[port.Name] {
+			if i > 0 {
+				log.FromContext(ctx).Info("Preferred port has no ready endpoints, using the next candidate",
+					"service", service.Name, "skipped", candidates[0].Port, "port", port.Port)
+			}
+			r.warnings.resolve(service, "NoReadyPort")
+			return &candidates[i], nil
+		}
+	}
+	r.warnings.warnf(r.Recorder, service, "NoReadyPort", "NoReadyPort",
+		"None of the candidate ports has a ready endpoint; routing to port %d until one does", candidates[0].Port)
+	return &candidates[0], nil
+}
+
+// syncRoutePort points an existing Route at the selected service port, so a port reselected once
+// its endpoints become ready takes effect without recreating the Route
+func (r *ServiceReconciler) syncRoutePort(ctx context.Context, route *routev1.Route, port *routev1.RoutePort) error {
+	if port == nil || (route.Spec.Port != nil && route.Spec.Port.TargetPort == port.TargetPort) {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(route.DeepCopy())
+	route.Spec.Port = port.DeepCopy()
+	log.FromContext(ctx).Info("Updating Route target port", "route", route.Name, "port", port.TargetPort.String())
+	return r.Patch(ctx, route, patch)
+}
+
+// mapEndpointSliceToService enqueues the service an EndpointSlice belongs to
+func mapEndpointSliceToService(_ context.Context, obj client.Object) []reconcile.Request {
+	serviceName := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if serviceName == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: serviceName}}}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Errors reconcile failures are wrapped in, so callers and tests can tell them apart with errors.Is
+var (
+	// ErrRouteCRDMissing means the route.openshift.io API is not served, i.e. the cluster is not OpenShift
+	ErrRouteCRDMissing = errors.New("route.openshift.io/v1 Route API is not available")
+
+	// ErrServiceNotFound means the LoadBalancer service backing a Gateway does not exist
+	ErrServiceNotFound = errors.New("backing LoadBalancer service not found")
+
+	// ErrHostConflict means the requested Route host is already used by another service's Route
+	ErrHostConflict = errors.New("route host is already in use")
+
+	// ErrCompanionConflict means a service not created by TinyLB already has a companion service's name
+	ErrCompanionConflict = errors.New("companion service name is taken")
+)
+
+// classifyError wraps errors from the API server in the matching typed error, leaving others as they are
+func classifyError(err error) error {
+	if err == nil || errors.Is(err, ErrRouteCRDMissing) {
+		return err
+	}
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("%w: %w", ErrRouteCRDMissing, err)
+	}
+	return err
+}
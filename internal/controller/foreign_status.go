@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// foreignCondition reports whether a condition was set by another controller. Conditions carry
+// no owner, so only domain-qualified types outside TinyLB's prefix are identifiable as foreign;
+// the standard Gateway API conditions are kept and simply overwritten by TinyLB.
+func (r *GatewayReconciler) foreignCondition(condition metav1.Condition) bool {
+	domain, _, qualified := strings.Cut(condition.Type, "/")
+	return qualified && domain != r.domainPrefix()
+}
+
+// cleanupForeignStatus removes the custom conditions and addresses a previous controller left on
+// a Gateway the first time TinyLB reconciles it, so consumers do not act on stale status. TinyLB
+// writes its own addresses again once the Gateway is programmed. Gateways this reconciler already
+// claimed were adopted before, so the cleanup survives restarts without running twice; it runs
+// ahead of claimGateway, which records the adoption.
+func (r *GatewayReconciler) cleanupForeignStatus(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	if !r.CleanupForeignStatus || claimedBy(gateway, r.name()) {
+		return nil
+	}
+
+	var removed []string
+	conditions := slices.DeleteFunc(slices.Clone(gateway.Status.Conditions), func(condition metav1.Condition) bool {
+		if r.foreignCondition(condition) {
+			removed = append(removed, condition.Type)
+			return true
+		}
+		return false
+	})
+
+	if len(removed) > 0 || len(gateway.Status.Addresses) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		gateway.Status.Conditions = conditions
+		gateway.Status.Addresses = []gatewayv1.GatewayStatusAddress{}
+		if err := r.Status().Update(ctx, gateway); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Removed status left by a previous controller", "gateway", gateway.Name, "conditions", removed)
+		if len(removed) > 0 {
+			r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "ForeignStatusRemoved",
+				"Removed conditions set by a previous controller: %s", strings.Join(removed, ", "))
+		}
+	}
+
+	return nil
+}
+
+// dedupeConditions collapses conditions whose types differ only in case, such as a foreign
+// "programmed" next to TinyLB's "Programmed", which meta.SetStatusCondition treats as distinct and
+// would otherwise keep side by side forever. Of each group the condition of type current (the one
+// being written) is kept, else the most recently transitioned; the group keeps its first position.
+func dedupeConditions(conditions []metav1.Condition, current string) ([]metav1.Condition, []string) {
+	kept := map[string]int{}
+	var deduped []metav1.Condition
+	var removed []string
+	for _, condition := range conditions {
+		canonical := strings.ToLower(condition.Type)
+		i, seen := kept[canonical]
+		if !seen {
+			kept[canonical] = len(deduped)
+			deduped = append(deduped, condition)
+			continue
+		}
+		if deduped[i].Type != current && (condition.Type == current || !condition.LastTransitionTime.Before(&deduped[i].LastTransitionTime)) {
+			removed = append(removed, deduped[i].Type)
+			deduped[i] = condition
+			continue
+		}
+		removed = append(removed, condition.Type)
+	}
+	return deduped, removed
+}
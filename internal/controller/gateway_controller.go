@@ -17,9 +17,14 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"context"
+	stderrors "errors"
 	"fmt"
+	"net"
+	"net/netip"
 	"slices"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -27,84 +32,313 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	routev1 "github.com/openshift/api/route/v1"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
+// assumeProgrammedAnnotation lets a Gateway skip the Route existence check, for migrations where
+// the Route is managed outside TinyLB. This bypasses validation: the Gateway is reported as
+// programmed as soon as its LoadBalancer service has an ingress address, whether or not anything
+// actually routes traffic to it.
+const assumeProgrammedAnnotation = "tinylb.io/assume-programmed"
+
+// gatewayReasonMultipleGateways marks Gateways left inactive because an older Gateway of the same
+// class in the namespace is the active one
+const gatewayReasonMultipleGateways gatewayv1.GatewayConditionReason = "MultipleGateways"
+
+// gatewayReasonServiceNotFound marks Gateways TinyLB stopped retrying because their backing
+// service stayed missing; the Gateway is reconciled again when it or the service changes
+const gatewayReasonServiceNotFound gatewayv1.GatewayConditionReason = "ServiceNotFound"
+
+// DefaultDomainPrefix qualifies TinyLB's custom Gateway condition types unless configured otherwise
+const DefaultDomainPrefix = "tinylb.io"
+
+// DefaultGatewayControllerName names the Gateway reconciler unless configured otherwise
+const DefaultGatewayControllerName = "gateway"
+
+// DefaultGatewayClasses are the GatewayClass names whose Gateways TinyLB programs unless configured otherwise
+var DefaultGatewayClasses = []string{"istio"}
+
+// Condition reporting whether the Gateway's LoadBalancer service has ready endpoints, so "routed
+// but no backend" can be told apart from "fully working". The name is qualified with the
+// reconciler's domain prefix, see conditionType
+const (
+	gatewayConditionBackendReady = "BackendReady"
+
+	gatewayReasonEndpointsReady   gatewayv1.GatewayConditionReason = "EndpointsReady"
+	gatewayReasonNoReadyEndpoints gatewayv1.GatewayConditionReason = "NoReadyEndpoints"
+)
+
 // GatewayReconciler reconciles a Gateway object
 type GatewayReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 
 	// Configuration
-	SupportedGatewayClasses []string // e.g., ["istio"]
-	RouteNamespace          string   // OpenShift route namespace (empty = same as gateway)
+	ControllerName          string             // controller name claiming Gateways, unique per reconciler and TinyLB install (empty = DefaultGatewayControllerName)
+	SupportedGatewayClasses []string           // e.g., ["istio"]
+	ControllerNames         []string           // GatewayClass controllerNames whose Gateways are also supported, e.g. ["tinylb.io/gateway"]
+	RouteNamespace          string             // namespace of listener Routes (empty = same as gateway)
+	Messages                *ConditionMessages // condition message templates (nil = built-in messages)
+	SingleGatewayPerClass   bool               // only program the oldest Gateway of each class per namespace
+	ReportBackendReady      bool               // set the {prefix}/BackendReady condition from the service's EndpointSlices
+	RequireEndpoints        bool               // only report Programmed=True while the service has a ready endpoint
+	RequireRouteAdmission   bool               // only report Programmed=True once a router has admitted the service's Route
+	ReportListenerStatus    bool               // set listener status from the service port serving each listener
+	CountAttachedRoutes     bool               // with ReportListenerStatus, count the HTTPRoutes each listener's allowedRoutes admits
+	DomainPrefix            string             // domain qualifying TinyLB's own condition types (empty = "tinylb.io")
+
+	GatewayAPIVersion          string               // Gateway API version watched: "v1" (default) or "v1beta1"; v1beta1 needs a NewGatewayV1beta1Client
+	IgnoreStatusUpdates        bool                 // skip reconciles for Gateway updates that only change status
+	ServiceNotFoundMaxAttempts int                  // reconciles without a backing service before giving up until the Gateway changes (0 = never give up)
+	CleanupForeignStatus       bool                 // on first reconcile, drop custom conditions and addresses left by a previous controller
+	UseServiceCA               bool                 // verify reencrypt listener Routes with the OpenShift service CA unless tinylb.io/destination-ca is set
+	ProgrammedTimeout          time.Duration        // report Programmed=False reason Timeout once the service has had no external IP this long (0 = never)
+	GatewaySummary             types.NamespacedName // ConfigMap listing programmed Gateways and their addresses (empty = disabled)
+	MirrorTLSSecrets           bool                 // serve Terminate listener certificates from their reencrypt Routes, copying the Secrets into RouteNamespace
+	APIReader                  client.Reader        // uncached reader for the listener Secrets and CA ConfigMaps the cache leaves out (nil = Client)
+	DefaultGatewayHostTemplate string               // host advertised by Gateways without listener hostnames whose service has none, e.g. "{gateway}-{namespace}.apps.example.com" (empty = none)
+	ExposureAnnotations        bool                 // annotate Gateways with the port and TLS termination of their service's Route
+	ProgrammedRequires         ProgrammedPolicy     // listeners that must be programmed for Programmed=True: none, any or all (empty = none)
+
+	statusForbidden statusForbiddenReporter
+	serviceNotFound serviceNotFoundBackoff
+	warnings        warningTracker
 }
 
-// getLoadBalancerServiceName determines the expected LoadBalancer service name for a Gateway
-// Based on current TinyLB behavior, this follows patterns like: {gateway-name}-{gatewayClassName}
-func (r *GatewayReconciler) getLoadBalancerServiceName(gateway *gatewayv1.Gateway) string {
-	gatewayClassName := string(gateway.Spec.GatewayClassName)
-	return fmt.Sprintf("%s-%s", gateway.Name, gatewayClassName)
+// name returns the controller name the reconciler registers, claims Gateways and reports metrics under
+func (r *GatewayReconciler) name() string {
+	return cmp.Or(r.ControllerName, DefaultGatewayControllerName)
+}
+
+// domainPrefix returns the domain qualifying TinyLB's custom condition types
+func (r *GatewayReconciler) domainPrefix() string {
+	return cmp.Or(r.DomainPrefix, DefaultDomainPrefix)
+}
+
+// conditionType qualifies a TinyLB-specific condition name with the configured domain prefix, so
+// custom conditions never collide with the standard Gateway API ones
+func (r *GatewayReconciler) conditionType(name string) gatewayv1.GatewayConditionType {
+	return gatewayv1.GatewayConditionType(r.domainPrefix() + "/" + name)
 }
 
-// isGatewayClassSupported checks if the gateway class is supported by TinyLB
-func (r *GatewayReconciler) isGatewayClassSupported(gatewayClassName string) bool {
-	return slices.Contains(r.SupportedGatewayClasses, gatewayClassName)
+// gatewayClassFilter only admits Gateways of the given classes, so several reconcilers can each
+// own a class without waking up for the others' Gateways
+func gatewayClassFilter(classes []string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		gateway, ok := asGateway(obj)
+		return ok && slices.Contains(classes, string(gateway.Spec.GatewayClassName))
+	})
+}
+
+// gatewayClassSupported checks if the gateway class is supported by TinyLB: listed by name, or
+// implemented by one of ControllerNames according to the GatewayClass's spec.controllerName
+func (r *GatewayReconciler) gatewayClassSupported(ctx context.Context, gatewayClassName string) (bool, error) {
+	if slices.Contains(r.SupportedGatewayClasses, gatewayClassName) {
+		return true, nil
+	}
+	if len(r.ControllerNames) == 0 {
+		return false, nil
+	}
+
+	var gatewayClass gatewayv1.GatewayClass
+	if err := r.Get(ctx, types.NamespacedName{Name: gatewayClassName}, &gatewayClass); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return slices.Contains(r.ControllerNames, string(gatewayClass.Spec.ControllerName)), nil
+}
+
+// isGatewayClassSupported is gatewayClassSupported for event mapping, where a failed lookup
+// can only be logged
+func (r *GatewayReconciler) isGatewayClassSupported(ctx context.Context, gatewayClassName string) bool {
+	supported, err := r.gatewayClassSupported(ctx, gatewayClassName)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Unable to get GatewayClass", "gatewayClassName", gatewayClassName)
+	}
+	return supported
+}
+
+// ingressAddresses returns the addresses advertised by a service's LoadBalancer ingress: every
+// IP, then every hostname, so a service published both ways advertises both. Each group is sorted
+// (IPv4 before IPv6) so the Gateway status does not churn when the ingress order changes.
+func ingressAddresses(ingress []corev1.LoadBalancerIngress) []string {
+	ips, hostnames := splitIngress(ingress)
+	return append(ips, hostnames...)
+}
+
+// splitIngress returns the distinct, sorted IPs and hostnames of a service's LoadBalancer ingress
+func splitIngress(ingress []corev1.LoadBalancerIngress) (ips, hostnames []string) {
+	var addrs []netip.Addr
+	for _, entry := range ingress {
+		if addr, err := netip.ParseAddr(entry.IP); err == nil && !slices.Contains(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+		if entry.Hostname != "" && !slices.Contains(hostnames, entry.Hostname) {
+			hostnames = append(hostnames, entry.Hostname)
+		}
+	}
+	slices.SortFunc(addrs, netip.Addr.Compare)
+	for _, addr := range addrs {
+		ips = append(ips, addr.String())
+	}
+	slices.Sort(hostnames)
+	return ips, hostnames
+}
+
+// defaultHostnames returns hostnames, or the Gateway's --default-gateway-host-template host when
+// there are none and no listener names a concrete hostname, so such Gateways still advertise a
+// deterministic host next to any IPs
+func (r *GatewayReconciler) defaultHostnames(gateway *gatewayv1.Gateway, hostnames []string) []string {
+	if len(hostnames) > 0 || r.DefaultGatewayHostTemplate == "" {
+		return hostnames
+	}
+	for _, listener := range gateway.Spec.Listeners {
+		if _, ok := listenerRouteHost(listener); ok {
+			return hostnames
+		}
+	}
+	return []string{gatewayHost(r.DefaultGatewayHostTemplate, gateway)}
+}
+
+// gatewayAddressType returns the Gateway address type matching value
+func gatewayAddressType(value string) gatewayv1.AddressType {
+	if net.ParseIP(value) != nil {
+		return gatewayv1.IPAddressType
+	}
+	return gatewayv1.HostnameAddressType
+}
+
+// activeGateway returns the Gateway of gateway's class that is programmed in its namespace when
+// only one Gateway per class is allowed: the oldest, with the name breaking ties
+func (r *GatewayReconciler) activeGateway(ctx context.Context, gateway *gatewayv1.Gateway) (*gatewayv1.Gateway, error) {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.InNamespace(gateway.Namespace)); err != nil {
+		return nil, err
+	}
+
+	active := gateway
+	for i := range gateways.Items {
+		candidate := &gateways.Items[i]
+		if candidate.Spec.GatewayClassName != gateway.Spec.GatewayClassName {
+			continue
+		}
+		if candidate.CreationTimestamp.Before(&active.CreationTimestamp) ||
+			(candidate.CreationTimestamp.Equal(&active.CreationTimestamp) && candidate.Name < active.Name) {
+			active = candidate
+		}
+	}
+	return active, nil
+}
+
+// gatewayService returns the LoadBalancer service backing gateway, or an ErrServiceNotFound error
+func (r *GatewayReconciler) gatewayService(ctx context.Context, gateway *gatewayv1.Gateway) (*corev1.Service, error) {
+	var service corev1.Service
+	key := types.NamespacedName{Name: GatewayServiceName(gateway), Namespace: gateway.Namespace}
+	if err := r.Get(ctx, key, &service); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, key)
+		}
+		return nil, err
+	}
+	return &service, nil
 }
 
 // updateGatewayCondition updates or adds a condition to the Gateway status
 func (r *GatewayReconciler) updateGatewayCondition(ctx context.Context, gateway *gatewayv1.Gateway, conditionType gatewayv1.GatewayConditionType, status metav1.ConditionStatus, reason gatewayv1.GatewayConditionReason, message string) error {
+	// Avoid partial status writes once the reconcile has been cancelled (e.g. during shutdown)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	condition := metav1.Condition{
 		Type:               string(conditionType),
 		Status:             status,
 		Reason:             string(reason),
 		Message:            message,
+		ObservedGeneration: gateway.Generation,
 		LastTransitionTime: metav1.Now(),
 	}
 
 	meta.SetStatusCondition(&gateway.Status.Conditions, condition)
+	conditions, removed := dedupeConditions(gateway.Status.Conditions, condition.Type)
+	if len(removed) > 0 {
+		log.FromContext(ctx).Info("Removing Gateway conditions duplicating another type", "gateway", gateway.Name, "conditions", removed)
+		gateway.Status.Conditions = conditions
+	}
 	return r.Status().Update(ctx, gateway)
 }
 
 // updateGatewayAddresses updates the Gateway status addresses
-func (r *GatewayReconciler) updateGatewayAddresses(ctx context.Context, gateway *gatewayv1.Gateway, hostname string) error {
-	// Only update addresses if there's a hostname
-	if hostname != "" {
-		addressType := gatewayv1.HostnameAddressType
-		gateway.Status.Addresses = []gatewayv1.GatewayStatusAddress{
-			{
-				Type:  &addressType,
-				Value: hostname,
-			},
-		}
-	} else {
-		gateway.Status.Addresses = []gatewayv1.GatewayStatusAddress{}
+func (r *GatewayReconciler) updateGatewayAddresses(ctx context.Context, gateway *gatewayv1.Gateway, addresses []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	gateway.Status.Addresses = []gatewayv1.GatewayStatusAddress{}
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		addressType := gatewayAddressType(address)
+		gateway.Status.Addresses = append(gateway.Status.Addresses, gatewayv1.GatewayStatusAddress{
+			Type:  &addressType,
+			Value: address,
+		})
 	}
 	return r.Status().Update(ctx, gateway)
 }
 
-// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
-// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startSpan(ctx, "GatewayReconciler.Reconcile",
+		attribute.String("k8s.namespace", req.Namespace),
+		attribute.String("k8s.name", req.Name))
+	defer func() { endSpan(span, err) }()
+	defer trackInFlight(r.name())()
+	defer func() { err = classifyError(err) }()
+
 	logger := log.FromContext(ctx)
 
 	// Get the Gateway
 	var gateway gatewayv1.Gateway
 	if err := r.Get(ctx, req.NamespacedName, &gateway); err != nil {
 		if errors.IsNotFound(err) {
-			// Gateway was deleted, nothing to do
-			return ctrl.Result{}, nil
+			// Gateway was deleted, only its summary entry and what lives in the route namespace are left to remove
+			r.warnings.forget(req.NamespacedName)
+			if err := r.deleteCentralListenerRoutes(ctx, req.NamespacedName); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := r.deleteMirroredSecrets(ctx, req.NamespacedName); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, r.updateGatewaySummary(ctx, req.NamespacedName, nil)
 		}
 		logger.Error(err, "Unable to fetch Gateway")
 		return ctrl.Result{}, err
@@ -114,92 +348,270 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	// Check if this is a supported Gateway class
 	gatewayClassName := string(gateway.Spec.GatewayClassName)
-	if !r.isGatewayClassSupported(gatewayClassName) {
+	supported, err := r.gatewayClassSupported(ctx, gatewayClassName)
+	if err != nil {
+		logger.Error(err, "Unable to get GatewayClass", "gatewayClassName", gatewayClassName)
+		return ctrl.Result{}, err
+	}
+	if !supported {
 		logger.Info("Gateway class not supported, skipping", "gatewayClassName", gatewayClassName)
-		return ctrl.Result{}, nil
+		// Gateways TinyLB programmed before their class was dropped would otherwise keep stale status
+		if err := r.releaseGateway(ctx, &gateway); err != nil {
+			logger.Error(err, "Unable to release Gateway")
+			return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
+		}
+		return ctrl.Result{}, r.updateGatewaySummary(ctx, req.NamespacedName, nil)
+	}
+
+	// Drop status a previous controller left behind before TinyLB claims the Gateway and writes its own
+	if err := r.cleanupForeignStatus(ctx, &gateway); err != nil {
+		logger.Error(err, "Unable to remove foreign Gateway status")
+		return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
+	}
+
+	// Remember the Gateway was ours, in case its class stops being supported
+	if err := r.claimGateway(ctx, &gateway); err != nil {
+		logger.Error(err, "Unable to claim Gateway")
+		return ctrl.Result{}, err
+	}
+
+	// Publish whatever status this reconcile leaves behind in the summary
+	defer func() {
+		if err == nil {
+			err = r.updateGatewaySummary(ctx, req.NamespacedName, programmedAddresses(&gateway))
+		}
+	}()
+
+	// Context for condition message templates, filled in as resources are resolved
+	messageData := MessageData{Gateway: gateway.Name, Namespace: gateway.Namespace}
+
+	// Only the oldest Gateway of a class is programmed when configured
+	if r.SingleGatewayPerClass {
+		active, err := r.activeGateway(ctx, &gateway)
+		if err != nil {
+			logger.Error(err, "Unable to list Gateways")
+			return ctrl.Result{}, err
+		}
+		if active.Name != gateway.Name {
+			logger.Info("Another Gateway of this class is active, not programming", "activeGateway", active.Name)
+			messageData.ActiveGateway = active.Name
+			message := r.Messages.render(MessageMultipleGateways, messageData)
+			if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionAccepted, metav1.ConditionFalse, gatewayReasonMultipleGateways, message); err != nil {
+				logger.Error(err, "Unable to update Gateway Accepted condition")
+				return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
+			}
+			if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayReasonMultipleGateways, message); err != nil {
+				logger.Error(err, "Unable to update Gateway Programmed condition")
+				return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
+			}
+			if err := r.updateGatewayAddresses(ctx, &gateway, nil); err != nil {
+				logger.Error(err, "Unable to clear Gateway addresses")
+				return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
+			}
+			return ctrl.Result{}, nil
+		}
 	}
 
 	// Always mark supported Gateway classes as Accepted
-	if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionAccepted, metav1.ConditionTrue, gatewayv1.GatewayReasonAccepted, "Gateway is accepted"); err != nil {
+	if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionAccepted, metav1.ConditionTrue, gatewayv1.GatewayReasonAccepted, r.Messages.render(MessageAccepted, messageData)); err != nil {
 		logger.Error(err, "Unable to update Gateway Accepted condition")
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 	}
 
 	// Find the expected LoadBalancer service name
-	serviceName := r.getLoadBalancerServiceName(&gateway)
+	serviceName := GatewayServiceName(&gateway)
 	logger.Info("Looking for LoadBalancer service", "service", serviceName)
+	messageData.Service = serviceName
 
 	// Get the LoadBalancer service
 	serviceNamespace := gateway.Namespace
-	var service corev1.Service
-	if err := r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: serviceNamespace}, &service); err != nil {
-		if errors.IsNotFound(err) {
-			logger.Info("LoadBalancer service not found, Gateway not programmed", "service", serviceName)
+	service, err := r.gatewayService(ctx, &gateway)
+	if r.ReportListenerStatus && (err == nil || stderrors.Is(err, ErrServiceNotFound)) {
+		// Written along with the Gateway status below
+		gateway.Status.Listeners = listenerStatuses(&gateway, service)
+		if r.CountAttachedRoutes {
+			attached, attachErr := r.attachedRoutes(ctx, &gateway)
+			if attachErr != nil {
+				logger.Error(attachErr, "Unable to count attached HTTPRoutes")
+				return ctrl.Result{}, attachErr
+			}
+			for i := range gateway.Status.Listeners {
+				gateway.Status.Listeners[i].AttachedRoutes = attached[gateway.Status.Listeners[i].Name]
+			}
+		}
+	}
+	if err != nil {
+		if stderrors.Is(err, ErrServiceNotFound) {
+			// Retry less and less often, and give up after too many misses until the Gateway changes
+			attempt := r.serviceNotFound.next(req.NamespacedName, gateway.Generation)
+			terminal := r.ServiceNotFoundMaxAttempts > 0 && attempt > r.ServiceNotFoundMaxAttempts
+			reason, message := gatewayv1.GatewayReasonNoResources, MessageServiceNotFound
+			if terminal {
+				reason, message = gatewayReasonServiceNotFound, MessageServiceNotFoundTerminal
+			}
+
+			logger.Info("LoadBalancer service not found, Gateway not programmed", "service", serviceName, "attempt", attempt, "terminal", terminal)
 			// Service doesn't exist, Gateway is not programmed
-			if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayv1.GatewayReasonNoResources, fmt.Sprintf("LoadBalancer service %s not found", serviceName)); err != nil {
+			if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, reason, r.Messages.render(message, messageData)); err != nil {
 				logger.Error(err, "Unable to update Gateway Programmed condition")
-				return ctrl.Result{RequeueAfter: time.Second * 10}, err
+				return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 			}
 			// Clear addresses
-			if err := r.updateGatewayAddresses(ctx, &gateway, ""); err != nil {
+			if err := r.updateGatewayAddresses(ctx, &gateway, nil); err != nil {
 				logger.Error(err, "Unable to clear Gateway addresses")
-				return ctrl.Result{RequeueAfter: time.Second * 10}, err
+				return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 			}
-			return ctrl.Result{RequeueAfter: time.Second * 30}, nil
+			if terminal {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{RequeueAfter: serviceNotFoundDelay(attempt)}, nil
 		}
 		logger.Error(err, "Unable to fetch LoadBalancer service")
 		return ctrl.Result{}, err
 	}
+	r.serviceNotFound.reset(req.NamespacedName)
 
 	// Check if service is LoadBalancer type
 	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
 		logger.Info("Service is not LoadBalancer type, Gateway not programmed", "service", serviceName, "type", service.Spec.Type)
-		if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayv1.GatewayReasonNoResources, fmt.Sprintf("Service %s is not LoadBalancer type", serviceName)); err != nil {
+		if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayv1.GatewayReasonNoResources, r.Messages.render(MessageServiceNotLoadBalancer, messageData)); err != nil {
 			logger.Error(err, "Unable to update Gateway Programmed condition")
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 		}
 		// Clear addresses
-		if err := r.updateGatewayAddresses(ctx, &gateway, ""); err != nil {
+		if err := r.updateGatewayAddresses(ctx, &gateway, nil); err != nil {
 			logger.Error(err, "Unable to clear Gateway addresses")
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 		}
 		return ctrl.Result{}, nil
 	}
 
-	// Check if service has external IP/hostname (indicating TinyLB processed it)
-	if len(service.Status.LoadBalancer.Ingress) == 0 {
+	// Report whether anything is actually serving behind the service
+	backendReady := true
+	if r.ReportBackendReady || r.RequireEndpoints {
+		if backendReady, err = serviceHasReadyEndpoints(ctx, r.Client, service); err != nil {
+			logger.Error(err, "Unable to list EndpointSlices", "service", serviceName)
+			return ctrl.Result{}, err
+		}
+	}
+	if r.ReportBackendReady {
+		status, reason, message := metav1.ConditionTrue, gatewayReasonEndpointsReady, MessageBackendReady
+		if !backendReady {
+			status, reason, message = metav1.ConditionFalse, gatewayReasonNoReadyEndpoints, MessageBackendNotReady
+		}
+		if err := r.updateGatewayCondition(ctx, &gateway, r.conditionType(gatewayConditionBackendReady), status, reason, r.Messages.render(message, messageData)); err != nil {
+			logger.Error(err, "Unable to update Gateway BackendReady condition")
+			return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
+		}
+	}
+
+	// Check if service has external IP/hostname (indicating TinyLB processed it). Ingress entries
+	// carrying neither are a transient state and count as pending rather than an empty address
+	if len(ingressAddresses(service.Status.LoadBalancer.Ingress)) == 0 {
 		logger.Info("LoadBalancer service has no external IP, Gateway not programmed yet", "service", serviceName)
-		if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayv1.GatewayReasonPending, fmt.Sprintf("LoadBalancer service %s has no external IP", serviceName)); err != nil {
+		requeueAfter := time.Second * 30
+		reason, message := gatewayv1.GatewayReasonPending, MessageServiceNoExternalIP
+
+		// Report Gateways stuck without an external IP distinctly once the timeout expires
+		if r.ProgrammedTimeout > 0 {
+			expired, remaining, err := r.programmedTimedOut(ctx, &gateway)
+			if err != nil {
+				logger.Error(err, "Unable to record when the Gateway became pending")
+				return ctrl.Result{}, err
+			}
+			if expired {
+				messageData.Timeout = r.ProgrammedTimeout.String()
+				reason, message = gatewayReasonTimeout, MessageProgrammedTimeout
+				if !timedOut(&gateway) {
+					r.Recorder.Event(&gateway, corev1.EventTypeWarning, "ProgrammedTimeout", r.Messages.render(message, messageData))
+				}
+			} else {
+				requeueAfter = min(requeueAfter, remaining)
+			}
+		}
+
+		if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, reason, r.Messages.render(message, messageData)); err != nil {
 			logger.Error(err, "Unable to update Gateway Programmed condition")
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 		}
 		// Clear addresses
-		if err := r.updateGatewayAddresses(ctx, &gateway, ""); err != nil {
+		if err := r.updateGatewayAddresses(ctx, &gateway, nil); err != nil {
 			logger.Error(err, "Unable to clear Gateway addresses")
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 		}
-		return ctrl.Result{RequeueAfter: time.Second * 30}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	if err := r.clearPendingSince(ctx, &gateway); err != nil {
+		logger.Error(err, "Unable to clear the Gateway pending record")
+		return ctrl.Result{}, err
+	}
+
+	// Externally managed Routes: trust the service's ingress address without looking for our Route
+	if gateway.Annotations[assumeProgrammedAnnotation] == "true" {
+		if r.RequireEndpoints && !backendReady {
+			return r.waitForEndpoints(ctx, &gateway, messageData)
+		}
+		if unready := unreadyListeners(&gateway, service, r.ProgrammedRequires); len(unready) > 0 {
+			messageData.UnreadyListeners = strings.Join(unready, ", ")
+			return r.waitForListeners(ctx, &gateway, messageData)
+		}
+		ips, hostnames := splitIngress(service.Status.LoadBalancer.Ingress)
+		addresses := append(ips, r.defaultHostnames(&gateway, hostnames)...)
+		logger.Info("Assuming Gateway is programmed, skipping Route check", "service", serviceName, "addresses", addresses)
+		messageData.Hostname = strings.Join(addresses, ",")
+
+		if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionTrue, gatewayv1.GatewayReasonProgrammed, r.Messages.render(MessageAssumedProgrammed, messageData)); err != nil {
+			logger.Error(err, "Unable to update Gateway Programmed condition")
+			return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
+		}
+		if err := r.updateGatewayAddresses(ctx, &gateway, addresses); err != nil {
+			logger.Error(err, "Unable to update Gateway addresses")
+			return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
+		}
+		return ctrl.Result{}, nil
 	}
 
 	// Service has external IP, check if Route exists
-	routeName := fmt.Sprintf("tinylb-%s", serviceName)
+	routeName := routeNameForService(serviceName)
 	routeNamespace := serviceNamespace
 	if r.RouteNamespace != "" {
 		routeNamespace = r.RouteNamespace
 	}
+	messageData.Route = routeName
+
+	// TLS listeners with their own hostname get a Route matching their TLS mode
+	if err := r.reconcileListenerRoutes(ctx, &gateway, service, routeNamespace); err != nil {
+		logger.Error(err, "Unable to reconcile listener Routes")
+		return ctrl.Result{}, err
+	}
+	if r.mirrorsTLSSecrets(&gateway, routeNamespace) || gateway.Annotations[destinationCAAnnotation] != "" {
+		defer func() {
+			if err == nil && (result.RequeueAfter == 0 || result.RequeueAfter > uncachedRecheckInterval) {
+				result.RequeueAfter = uncachedRecheckInterval
+			}
+		}()
+	}
 
+	// The service controller creates the service's Route next to the service; only listener Routes
+	// move to RouteNamespace
 	var route routev1.Route
-	if err := r.Get(ctx, types.NamespacedName{Name: routeName, Namespace: routeNamespace}, &route); err != nil {
+	if err := r.Get(ctx, types.NamespacedName{Name: routeName, Namespace: serviceNamespace}, &route); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("Route not found, Gateway not programmed", "route", routeName)
-			if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayv1.GatewayReasonNoResources, fmt.Sprintf("Route %s not found", routeName)); err != nil {
+			if r.ExposureAnnotations {
+				if err := r.syncExposureAnnotations(ctx, &gateway, nil); err != nil {
+					logger.Error(err, "Unable to clear Gateway exposure annotations")
+					return ctrl.Result{}, err
+				}
+			}
+			if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayv1.GatewayReasonNoResources, r.Messages.render(MessageRouteNotFound, messageData)); err != nil {
 				logger.Error(err, "Unable to update Gateway Programmed condition")
-				return ctrl.Result{RequeueAfter: time.Second * 10}, err
+				return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 			}
 			// Clear addresses
-			if err := r.updateGatewayAddresses(ctx, &gateway, ""); err != nil {
+			if err := r.updateGatewayAddresses(ctx, &gateway, nil); err != nil {
 				logger.Error(err, "Unable to clear Gateway addresses")
-				return ctrl.Result{RequeueAfter: time.Second * 10}, err
+				return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 			}
 			return ctrl.Result{RequeueAfter: time.Second * 30}, nil
 		}
@@ -207,40 +619,270 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
-	// Route exists, Gateway is programmed
-	hostname := service.Status.LoadBalancer.Ingress[0].Hostname
-	if hostname == "" && service.Status.LoadBalancer.Ingress[0].IP != "" {
-		hostname = service.Status.LoadBalancer.Ingress[0].IP
+	if route.Spec.Port != nil {
+		messageData.Port = route.Spec.Port.TargetPort.String()
+	}
+
+	// Show Gateway users how the service is exposed without sending them to the service
+	if r.ExposureAnnotations {
+		if err := r.syncExposureAnnotations(ctx, &gateway, routeExposure(&route)); err != nil {
+			logger.Error(err, "Unable to update Gateway exposure annotations")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The Route is in place, but without ready endpoints there is nothing to serve yet
+	if r.RequireEndpoints && !backendReady {
+		return r.waitForEndpoints(ctx, &gateway, messageData)
 	}
 
-	// Prefer Route hostname if available
+	// The Route exists, but a router that rejected it (e.g. its host is claimed) serves nothing
+	if r.RequireRouteAdmission {
+		if admitted, rejection := routeAdmission(&route); !admitted {
+			return r.waitForAdmission(ctx, &gateway, rejection, messageData)
+		}
+	}
+
+	// The policy may ask for programmed listeners on top of the Route
+	if unready := unreadyListeners(&gateway, service, r.ProgrammedRequires); len(unready) > 0 {
+		messageData.UnreadyListeners = strings.Join(unready, ", ")
+		return r.waitForListeners(ctx, &gateway, messageData)
+	}
+
+	// Route exists, Gateway is programmed
+	ips, hostnames := splitIngress(service.Status.LoadBalancer.Ingress)
+
+	// Prefer Route hostname if available, keeping any IPs the service is also published on
 	if route.Spec.Host != "" {
-		hostname = route.Spec.Host
+		hostnames = []string{route.Spec.Host}
 	}
+	addresses := append(ips, r.defaultHostnames(&gateway, hostnames)...)
+	messageData.Hostname = strings.Join(addresses, ",")
 
-	logger.Info("Gateway is programmed", "service", serviceName, "route", routeName, "hostname", hostname)
+	logger.Info("Gateway is programmed", "service", serviceName, "route", routeName, "addresses", addresses)
 
 	// Update Gateway as programmed
-	if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionTrue, gatewayv1.GatewayReasonProgrammed, "Gateway is programmed"); err != nil {
+	if err := r.updateGatewayCondition(ctx, &gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionTrue, gatewayv1.GatewayReasonProgrammed, r.Messages.render(MessageProgrammed, messageData)); err != nil {
 		logger.Error(err, "Unable to update Gateway Programmed condition")
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 	}
 
 	// Update Gateway addresses
-	if err := r.updateGatewayAddresses(ctx, &gateway, hostname); err != nil {
+	if err := r.updateGatewayAddresses(ctx, &gateway, addresses); err != nil {
 		logger.Error(err, "Unable to update Gateway addresses")
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.statusForbidden.handle(ctx, r.Recorder, &gateway, "gateways/status", err)
 	}
 
-	logger.Info("Successfully updated Gateway status", "gateway", gateway.Name, "hostname", hostname)
+	logger.Info("Successfully updated Gateway status", "gateway", gateway.Name, "addresses", addresses)
+
+	return ctrl.Result{}, nil
+}
+
+// waitForEndpoints reports a Gateway whose service has no ready endpoint as not yet programmed
+// EndpointSlice changes re-enqueue the Gateway, so no requeue is needed
+func (r *GatewayReconciler) waitForEndpoints(ctx context.Context, gateway *gatewayv1.Gateway, messageData MessageData) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("LoadBalancer service has no ready endpoints, Gateway not programmed yet", "service", messageData.Service)
+	if err := r.updateGatewayCondition(ctx, gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayv1.GatewayReasonPending, r.Messages.render(MessageBackendNotReady, messageData)); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to update Gateway Programmed condition")
+		return r.statusForbidden.handle(ctx, r.Recorder, gateway, "gateways/status", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// waitForListeners reports a Gateway without the programmed listeners --programmed-requires asks
+// for as not programmed. Listener status follows the Gateway and its service, whose changes
+// re-enqueue the Gateway, so no requeue is needed
+func (r *GatewayReconciler) waitForListeners(ctx context.Context, gateway *gatewayv1.Gateway, messageData MessageData) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Listeners not programmed, Gateway not programmed", "policy", r.ProgrammedRequires, "listeners", messageData.UnreadyListeners)
+	if err := r.updateGatewayCondition(ctx, gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, gatewayv1.GatewayReasonListenersNotReady, r.Messages.render(MessageListenersNotReady, messageData)); err != nil {
+		logger.Error(err, "Unable to update Gateway Programmed condition")
+		return r.statusForbidden.handle(ctx, r.Recorder, gateway, "gateways/status", err)
+	}
+	if err := r.updateGatewayAddresses(ctx, gateway, nil); err != nil {
+		logger.Error(err, "Unable to clear Gateway addresses")
+		return r.statusForbidden.handle(ctx, r.Recorder, gateway, "gateways/status", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// routeAdmission reports whether a router admitted route and, when one rejected it instead,
+// the router's reason and message
+func routeAdmission(route *routev1.Route) (bool, string) {
+	rejection := ""
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type != routev1.RouteAdmitted {
+				continue
+			}
+			switch condition.Status {
+			case corev1.ConditionTrue:
+				return true, ""
+			case corev1.ConditionFalse:
+				rejection = condition.Reason
+				if condition.Message != "" {
+					rejection += ": " + condition.Message
+				}
+			}
+		}
+	}
+	return false, rejection
+}
 
+// waitForAdmission reports a Gateway whose Route no router admitted as not programmed: Invalid
+// with the router's reason once rejected, Pending until a router reports. Route status changes
+// re-enqueue the Gateway, so no requeue is needed
+func (r *GatewayReconciler) waitForAdmission(ctx context.Context, gateway *gatewayv1.Gateway, rejection string, messageData MessageData) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	reason, message := gatewayv1.GatewayReasonPending, MessageRouteNotAdmitted
+	if rejection != "" {
+		reason, message = gatewayv1.GatewayReasonInvalid, MessageRouteRejected
+		messageData.RouteRejection = rejection
+	}
+	logger.Info("Route not admitted, Gateway not programmed", "route", messageData.Route, "rejection", rejection)
+	if err := r.updateGatewayCondition(ctx, gateway, gatewayv1.GatewayConditionProgrammed, metav1.ConditionFalse, reason, r.Messages.render(message, messageData)); err != nil {
+		logger.Error(err, "Unable to update Gateway Programmed condition")
+		return r.statusForbidden.handle(ctx, r.Recorder, gateway, "gateways/status", err)
+	}
+	if err := r.updateGatewayAddresses(ctx, gateway, nil); err != nil {
+		logger.Error(err, "Unable to clear Gateway addresses")
+		return r.statusForbidden.handle(ctx, r.Recorder, gateway, "gateways/status", err)
+	}
 	return ctrl.Result{}, nil
 }
 
+// mapRouteToGateways enqueues the Gateways whose LoadBalancer service a managed Route fronts,
+// so Route changes such as admission update Gateway status without waiting for the next poll
+func (r *GatewayReconciler) mapRouteToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	route, ok := obj.(*routev1.Route)
+	if !ok || route.Labels[managedLabel] != "true" {
+		return nil
+	}
+	serviceName := route.Labels[serviceLabel]
+	if serviceName == "" {
+		return nil
+	}
+
+	// Routes live next to their Gateway unless a central route namespace is configured
+	namespace := route.Namespace
+	if r.RouteNamespace != "" {
+		namespace = ""
+	}
+	return r.gatewaysForService(ctx, namespace, serviceName)
+}
+
+// mapServiceToGateways enqueues the Gateways a service backs, so a Gateway whose service was
+// missing is programmed as soon as the service appears
+func (r *GatewayReconciler) mapServiceToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.gatewaysForService(ctx, obj.GetNamespace(), obj.GetName())
+}
+
+// mapEndpointSliceToGateways enqueues the Gateways backed by an EndpointSlice's service, keeping
+// the BackendReady condition current as pods come and go
+func (r *GatewayReconciler) mapEndpointSliceToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	serviceName := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if serviceName == "" {
+		return nil
+	}
+	return r.gatewaysForService(ctx, obj.GetNamespace(), serviceName)
+}
+
+// gatewaysForService returns requests for the supported Gateways in namespace (empty = all)
+// whose LoadBalancer service is serviceName
+func (r *GatewayReconciler) gatewaysForService(ctx context.Context, namespace, serviceName string) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to list Gateways for service", "service", serviceName)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gateway := range gateways.Items {
+		if GatewayServiceName(&gateway) == serviceName && r.isGatewayClassSupported(ctx, string(gateway.Spec.GatewayClassName)) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+		}
+	}
+	return requests
+}
+
+// mapGatewayClassToGateways enqueues the Gateways of a GatewayClass, so they are picked up or
+// released when its controllerName changes
+func (r *GatewayReconciler) mapGatewayClassToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to list Gateways for GatewayClass", "gatewayClass", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gateway := range gateways.Items {
+		if string(gateway.Spec.GatewayClassName) == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+		}
+	}
+	return requests
+}
+
+// mapGatewayToClassPeers enqueues the other Gateways of the same class in a Gateway's namespace,
+// so a new Gateway takes over as soon as the active one is deleted
+func (r *GatewayReconciler) mapGatewayToClassPeers(ctx context.Context, obj client.Object) []reconcile.Request {
+	gateway, ok := asGateway(obj)
+	if !ok || !r.isGatewayClassSupported(ctx, string(gateway.Spec.GatewayClassName)) {
+		return nil
+	}
+
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.InNamespace(gateway.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to list Gateways for Gateway", "gateway", gateway.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, peer := range gateways.Items {
+		if peer.Name != gateway.Name && peer.Spec.GatewayClassName == gateway.Spec.GatewayClassName {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&peer)})
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&gatewayv1.Gateway{}).
-		Named("gateway").
-		Complete(r)
+	// Classes matched by controllerName are only known after a GatewayClass lookup, so the class
+	// filter can only be applied at the watch level when matching by name alone
+	var predicates []predicate.Predicate
+	if len(r.ControllerNames) == 0 {
+		predicates = append(predicates, predicate.Or(gatewayClassFilter(r.SupportedGatewayClasses), reconciledByFilter(r.name())))
+	}
+	if r.IgnoreStatusUpdates {
+		predicates = append(predicates, specChangePredicate())
+	}
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(r.gatewayObject(), builder.WithPredicates(predicates...)).
+		Watches(&routev1.Route{}, handler.EnqueueRequestsFromMapFunc(r.mapRouteToGateways)).
+		Watches(&corev1.Service{}, handler.EnqueueRequestsFromMapFunc(r.mapServiceToGateways)).
+		Named(r.name())
+	if r.ReportBackendReady || r.RequireEndpoints {
+		bldr = bldr.Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(r.mapEndpointSliceToGateways))
+	}
+	if len(r.ControllerNames) > 0 {
+		bldr = bldr.Watches(r.gatewayClassObject(), handler.EnqueueRequestsFromMapFunc(r.mapGatewayClassToGateways))
+	}
+	if r.SingleGatewayPerClass {
+		bldr = bldr.Watches(r.gatewayObject(), handler.EnqueueRequestsFromMapFunc(r.mapGatewayToClassPeers))
+	}
+	if r.ReportListenerStatus && r.CountAttachedRoutes {
+		bldr = bldr.Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.mapHTTPRouteToGateways))
+	}
+	if r.MirrorTLSSecrets {
+		bldr = bldr.Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapSecretToGateways))
+	}
+	if r.UseServiceCA {
+		// Only TinyLB's own ConfigMaps are cached; of those, the service CA bundles concern Gateways
+		bldr = bldr.Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapConfigMapToGateways),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetName() == serviceCAConfigMapName
+			})))
+	}
+	return bldr.Complete(r)
 }
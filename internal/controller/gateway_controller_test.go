@@ -0,0 +1,2344 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// newGateway returns a Gateway of the given class with a single HTTPS listener
+func newGateway(name, namespace, className string) *gatewayv1.Gateway {
+	return &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 1},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: gatewayv1.ObjectName(className),
+			Listeners: []gatewayv1.Listener{
+				{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+			},
+		},
+	}
+}
+
+// newGatewayService returns the LoadBalancer service backing gw, advertising hostname in its ingress
+func newGatewayService(gw *gatewayv1.Gateway, hostname string) *corev1.Service {
+	svc := newLoadBalancerService(gw.Name+"-"+string(gw.Spec.GatewayClassName), gw.Namespace)
+	if hostname != "" {
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: hostname}}
+	}
+	return svc
+}
+
+// newManagedRoute returns the TinyLB Route for svc with the given host
+func newManagedRoute(svc *corev1.Service, host string) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tinylb-" + svc.Name,
+			Namespace: svc.Namespace,
+			Labels: map[string]string{
+				"tinylb.io/managed":     "true",
+				"tinylb.io/service":     svc.Name,
+				"tinylb.io/service-uid": string(svc.UID),
+			},
+		},
+		Spec: routev1.RouteSpec{
+			Host: host,
+			To:   routev1.RouteTargetReference{Kind: "Service", Name: svc.Name},
+		},
+	}
+}
+
+// reconcileGateway runs a single GatewayReconciler pass for gw
+func reconcileGateway(ctx context.Context, r *GatewayReconciler, gw *gatewayv1.Gateway) (ctrl.Result, error) {
+	return r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gw)})
+}
+
+// getGateway fetches the current state of gw from the client
+func getGateway(c client.Client, gw *gatewayv1.Gateway) *gatewayv1.Gateway {
+	var current gatewayv1.Gateway
+	Expect(c.Get(context.Background(), client.ObjectKeyFromObject(gw), &current)).To(Succeed())
+	return &current
+}
+
+var _ = Describe("Gateway Controller", func() {
+	Context("When the reconcile context is cancelled", func() {
+		It("should not write Gateway status", func() {
+			gw := newGateway("gw", "default", "istio")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{
+				Client:                  newFakeClient(scheme, gw),
+				Scheme:                  scheme,
+				SupportedGatewayClasses: []string{"istio"},
+			}
+
+			cancelledCtx, cancelFn := context.WithCancel(context.Background())
+			cancelFn()
+
+			_, err := reconcileGateway(cancelledCtx, r, gw)
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(getGateway(r.Client, gw).Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	Context("When the Gateway assumes it is programmed", func() {
+		var gw *gatewayv1.Gateway
+		var svc *corev1.Service
+
+		BeforeEach(func() {
+			gw = newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{assumeProgrammedAnnotation: "true"}
+			svc = newGatewayService(gw, "external.example.com")
+		})
+
+		expectProgrammedWith := func(r *GatewayReconciler, hostname string) {
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := getGateway(r.Client, gw)
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+			Expect(current.Status.Addresses).To(HaveLen(1))
+			Expect(current.Status.Addresses[0].Value).To(Equal(hostname))
+		}
+
+		It("should be programmed without a Route", func() {
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			expectProgrammedWith(r, "external.example.com")
+		})
+
+		It("should use the service ingress hostname even when a Route exists", func() {
+			scheme := newTestScheme()
+			route := newManagedRoute(svc, "route.example.com")
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, route), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			expectProgrammedWith(r, "external.example.com")
+		})
+	})
+
+	Context("When reporting conditions", func() {
+		It("should record the Gateway generation as observedGeneration", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Generation = 3
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := getGateway(r.Client, gw)
+			Expect(current.Status.Conditions).NotTo(BeEmpty())
+			for _, condition := range current.Status.Conditions {
+				Expect(condition.ObservedGeneration).To(Equal(current.Generation), condition.Type)
+			}
+		})
+	})
+
+	Context("When condition messages are customized", func() {
+		It("should render the defaults when no overrides are set", func() {
+			var messages *ConditionMessages
+			Expect(messages.render(MessageServiceNotFound, MessageData{Service: "gw-istio"})).
+				To(Equal("LoadBalancer service gw-istio not found"))
+		})
+
+		It("should use a custom template in Gateway conditions", func() {
+			messages, err := NewConditionMessages(map[string]string{
+				MessageServiceNotFound: "Waiting for {{.Service}} in {{.Namespace}}",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			gw := newGateway("gw", "default", "istio")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, Messages: messages}
+
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			programmed := meta.FindStatusCondition(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed).NotTo(BeNil())
+			Expect(programmed.Message).To(Equal("Waiting for gw-istio in default"))
+		})
+
+		It("should reject unknown keys and invalid templates", func() {
+			_, err := NewConditionMessages(map[string]string{"NoSuchMessage": "x"})
+			Expect(err).To(HaveOccurred())
+			_, err = NewConditionMessages(map[string]string{MessageRouteNotFound: "{{.Route"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When the backing service is dual-stack", func() {
+		It("should advertise both address families with IP address types", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{assumeProgrammedAnnotation: "true"}
+			svc := newGatewayService(gw, "")
+			svc.Spec.IPFamilyPolicy = ptr.To(corev1.IPFamilyPolicyRequireDualStack)
+			svc.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.0.2.10"}, {IP: "2001:db8::10"}}
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			addresses := getGateway(r.Client, gw).Status.Addresses
+			Expect(addresses).To(HaveLen(2))
+			for i, value := range []string{"192.0.2.10", "2001:db8::10"} {
+				Expect(addresses[i].Value).To(Equal(value))
+				Expect(*addresses[i].Type).To(Equal(gatewayv1.IPAddressType))
+			}
+		})
+
+		It("should keep hostnames typed as Hostname", func() {
+			Expect(gatewayAddressType("gw.example.com")).To(Equal(gatewayv1.HostnameAddressType))
+			Expect(gatewayAddressType("2001:db8::10")).To(Equal(gatewayv1.IPAddressType))
+		})
+	})
+
+	Context("When a managed Route changes", func() {
+		It("should enqueue the Gateway backed by the Route's service", func() {
+			gw := newGateway("gw", "default", "istio")
+			other := newGateway("other", "default", "istio")
+			unsupported := newGateway("gw", "elsewhere", "nginx")
+			route := newManagedRoute(newGatewayService(gw, ""), "gw.example.com")
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, other, unsupported), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			requests := r.mapRouteToGateways(context.Background(), route)
+			Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gw)}))
+		})
+
+		It("should ignore Routes TinyLB does not manage", func() {
+			gw := newGateway("gw", "default", "istio")
+			route := newManagedRoute(newGatewayService(gw, ""), "gw.example.com")
+			route.Labels = nil
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			Expect(r.mapRouteToGateways(context.Background(), route)).To(BeEmpty())
+		})
+	})
+
+	Context("When listeners use different TLS modes", func() {
+		It("should create a reencrypt Route for Terminate and a passthrough Route for Passthrough", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = []gatewayv1.Listener{
+				{
+					Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+					Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+				},
+				{
+					Name: "db", Port: 8443, Protocol: gatewayv1.TLSProtocolType,
+					Hostname: ptr.To(gatewayv1.Hostname("db.example.com")),
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+				},
+				{Name: "plain", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			}
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, route), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var web, db routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &web)).To(Succeed())
+			Expect(web.Spec.Host).To(Equal("web.example.com"))
+			Expect(web.Spec.TLS.Termination).To(Equal(routev1.TLSTerminationReencrypt))
+			Expect(web.Spec.Port.TargetPort.IntValue()).To(Equal(443))
+			Expect(web.OwnerReferences).To(HaveLen(1))
+
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-db"}, &db)).To(Succeed())
+			Expect(db.Spec.Host).To(Equal("db.example.com"))
+			Expect(db.Spec.TLS.Termination).To(Equal(routev1.TLSTerminationPassthrough))
+			Expect(db.Spec.Port.TargetPort.IntValue()).To(Equal(8443))
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes, client.InNamespace("default"))).To(Succeed())
+			Expect(routes.Items).To(HaveLen(3))
+		})
+
+		It("should delete the Route of a removed listener", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			stale := newManagedRoute(svc, "old.example.com")
+			stale.Name = "tinylb-gw-istio-old"
+			stale.Labels[gatewayLabel] = gw.Name
+			stale.Labels[listenerLabel] = "old"
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, route, stale), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes, client.InNamespace("default"))).To(Succeed())
+			Expect(routes.Items).To(HaveLen(1))
+			Expect(routes.Items[0].Name).To(Equal(route.Name))
+		})
+	})
+
+	Context("When only one Gateway per class is allowed", func() {
+		It("should program the oldest Gateway and reject the newer one", func() {
+			older := newGateway("first", "default", "istio")
+			older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			newer := newGateway("second", "default", "istio")
+			newer.CreationTimestamp = metav1.Now()
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, older, newer), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, SingleGatewayPerClass: true}
+			for _, gw := range []*gatewayv1.Gateway{older, newer} {
+				_, err := reconcileGateway(context.Background(), r, gw)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(meta.IsStatusConditionTrue(getGateway(r.Client, older).Status.Conditions, string(gatewayv1.GatewayConditionAccepted))).To(BeTrue())
+
+			accepted := meta.FindStatusCondition(getGateway(r.Client, newer).Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+			Expect(accepted).NotTo(BeNil())
+			Expect(accepted.Status).To(Equal(metav1.ConditionFalse))
+			Expect(accepted.Reason).To(Equal(string(gatewayReasonMultipleGateways)))
+			Expect(accepted.Message).To(ContainSubstring("first"))
+		})
+
+		It("should enqueue the other Gateways of the class when one changes", func() {
+			older := newGateway("first", "default", "istio")
+			newer := newGateway("second", "default", "istio")
+			other := newGateway("third", "default", "nginx")
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, newer, other), Scheme: scheme, SupportedGatewayClasses: []string{"istio", "nginx"}, SingleGatewayPerClass: true}
+			Expect(r.mapGatewayToClassPeers(context.Background(), older)).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(newer)}))
+		})
+	})
+
+	Context("When reporting backend readiness", func() {
+		backendReady := func(objs ...client.Object) *metav1.Condition {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, append(objs, gw, svc)...), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, ReportBackendReady: true}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			return meta.FindStatusCondition(getGateway(r.Client, gw).Status.Conditions, string(r.conditionType(gatewayConditionBackendReady)))
+		}
+
+		It("should be true when the service has ready endpoints", func() {
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gw-istio-abc",
+					Namespace: "default",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gw-istio"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses:  []string{"10.0.0.5"},
+					Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+				}},
+			}
+			condition := backendReady(slice)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(gatewayReasonEndpointsReady)))
+		})
+
+		It("should be false when the service has no ready endpoints", func() {
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gw-istio-abc",
+					Namespace: "default",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gw-istio"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses:  []string{"10.0.0.5"},
+					Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)},
+				}},
+			}
+			condition := backendReady(slice)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(gatewayReasonNoReadyEndpoints)))
+			Expect(condition.Message).To(Equal("Service gw-istio has no ready endpoints"))
+		})
+	})
+
+	Context("When the destination CA of a reencrypt listener rotates", func() {
+		It("should update the Route with the new CA", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{destinationCAAnnotation: "gw-ca"}
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+			}}
+			svc := newGatewayService(gw, "gw.example.com")
+			ca := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-ca", Namespace: "default"},
+				Data:       map[string]string{destinationCAKey: "old-ca"},
+			}
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"), ca), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			destinationCA := func() string {
+				var route routev1.Route
+				Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &route)).To(Succeed())
+				return route.Spec.TLS.DestinationCACertificate
+			}
+
+			// The ConfigMap is the user's, so it is not watched and the Gateway polls it instead
+			result, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(uncachedRecheckInterval))
+			Expect(destinationCA()).To(Equal("old-ca"))
+
+			ca.Data[destinationCAKey] = "new-ca"
+			Expect(r.Update(context.Background(), ca)).To(Succeed())
+
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destinationCA()).To(Equal("new-ca"))
+		})
+	})
+
+	Context("When running one reconciler per class", func() {
+		It("should only admit Gateways of the reconciler's class", func() {
+			istio := newGateway("gw", "default", "istio")
+			nginx := newGateway("gw", "default", "nginx")
+
+			istioFilter := gatewayClassFilter([]string{"istio"})
+			Expect(istioFilter.Create(event.CreateEvent{Object: istio})).To(BeTrue())
+			Expect(istioFilter.Create(event.CreateEvent{Object: nginx})).To(BeFalse())
+
+			nginxFilter := gatewayClassFilter([]string{"nginx"})
+			Expect(nginxFilter.Update(event.UpdateEvent{ObjectOld: nginx, ObjectNew: nginx})).To(BeTrue())
+			Expect(nginxFilter.Update(event.UpdateEvent{ObjectOld: istio, ObjectNew: istio})).To(BeFalse())
+		})
+
+		It("should leave Gateways of other classes untouched", func() {
+			istio := newGateway("istio-gw", "default", "istio")
+			nginx := newGateway("nginx-gw", "default", "nginx")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, istio, nginx)
+			istioReconciler := &GatewayReconciler{Client: c, Scheme: scheme, ControllerName: "gateway-istio", SupportedGatewayClasses: []string{"istio"}}
+
+			for _, gw := range []*gatewayv1.Gateway{istio, nginx} {
+				_, err := reconcileGateway(context.Background(), istioReconciler, gw)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(getGateway(c, istio).Status.Conditions).NotTo(BeEmpty())
+			Expect(getGateway(c, nginx).Status.Conditions).To(BeEmpty())
+			Expect(istioReconciler.name()).To(Equal("gateway-istio"))
+			Expect((&GatewayReconciler{}).name()).To(Equal("gateway"))
+		})
+	})
+
+	Context("When a Gateway requests HSTS", func() {
+		It("should set the header on TLS-terminating listener Routes only", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{hstsAnnotation: "max-age=31536000;includeSubDomains"}
+			gw.Spec.Listeners = []gatewayv1.Listener{
+				{
+					Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+					Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+				},
+				{
+					Name: "db", Port: 8443, Protocol: gatewayv1.TLSProtocolType,
+					Hostname: ptr.To(gatewayv1.Hostname("db.example.com")),
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+				},
+			}
+			svc := newGatewayService(gw, "gw.example.com")
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var web, db routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &web)).To(Succeed())
+			Expect(web.Annotations).To(HaveKeyWithValue(hstsRouteAnnotation, "max-age=31536000;includeSubDomains"))
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-db"}, &db)).To(Succeed())
+			Expect(db.Annotations).NotTo(HaveKey(hstsRouteAnnotation))
+		})
+
+		It("should warn about an invalid header once", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{hstsAnnotation: "includeSubDomains"}
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+			}}
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, Recorder: recorder, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring(hstsAnnotation)))
+			_, err = reconcileGateway(context.Background(), r, getGateway(r.Client, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring(hstsAnnotation)))
+		})
+
+		It("should validate the header syntax", func() {
+			Expect(validateHSTS("max-age=31536000")).To(Succeed())
+			Expect(validateHSTS("max-age=600; includeSubDomains; preload")).To(Succeed())
+			Expect(validateHSTS("includeSubDomains")).NotTo(Succeed())
+			Expect(validateHSTS("max-age=forever")).NotTo(Succeed())
+			Expect(validateHSTS("max-age=600;nosniff")).NotTo(Succeed())
+		})
+	})
+
+	Context("When the backing service is missing", func() {
+		It("should report ErrServiceNotFound", func() {
+			gw := newGateway("gw", "default", "istio")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := r.gatewayService(context.Background(), gw)
+			Expect(errors.Is(err, ErrServiceNotFound)).To(BeTrue())
+		})
+
+		It("should back off from 30s to a 5m cap", func() {
+			gw := newGateway("gw", "default", "istio")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			var delays []time.Duration
+			for range 6 {
+				result, err := reconcileGateway(context.Background(), r, gw)
+				Expect(err).NotTo(HaveOccurred())
+				delays = append(delays, result.RequeueAfter)
+			}
+			Expect(delays).To(Equal([]time.Duration{
+				30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute,
+			}))
+		})
+
+		It("should stop requeuing after the maximum attempts until the Gateway changes", func() {
+			gw := newGateway("gw", "default", "istio")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{
+				Client: newFakeClient(scheme, gw), Scheme: scheme, SupportedGatewayClasses: []string{"istio"},
+				ServiceNotFoundMaxAttempts: 2,
+			}
+
+			for range 2 {
+				result, err := reconcileGateway(context.Background(), r, gw)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).NotTo(BeZero())
+			}
+			result, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			programmed := meta.FindStatusCondition(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed).NotTo(BeNil())
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayReasonServiceNotFound)))
+
+			// A spec change starts the backoff over
+			current := getGateway(r.Client, gw)
+			current.Generation = 2
+			Expect(r.Update(context.Background(), current)).To(Succeed())
+			result, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+		})
+
+		It("should enqueue the Gateway when its service appears", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			Expect(r.mapServiceToGateways(context.Background(), svc)).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gw)},
+			))
+		})
+	})
+
+	Context("When the Gateway service uses named target ports", func() {
+		It("should reference the listener's service port by name", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+			}}
+			svc := newGatewayService(gw, "gw.example.com")
+			svc.Spec.Ports[0].TargetPort = intstr.FromString("https-gw")
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var web routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &web)).To(Succeed())
+			Expect(web.Spec.Port.TargetPort).To(Equal(intstr.FromString("https")))
+		})
+	})
+
+	Context("When Gateways are served as v1beta1", func() {
+		It("should program a v1beta1 Gateway", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			route.Status.Ingress = []routev1.RouteIngress{{
+				Host:       "gw.example.com",
+				Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+			}}
+			betaGateway := (*gatewayv1beta1.Gateway)(gw.DeepCopy())
+
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, betaGateway, svc, route)
+			r := &GatewayReconciler{
+				Client: NewGatewayV1beta1Client(c), Scheme: scheme, SupportedGatewayClasses: []string{"istio"},
+				GatewayAPIVersion: GatewayAPIVersionV1beta1,
+			}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current gatewayv1beta1.Gateway
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(gw), &current)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+			Expect(current.Status.Addresses).To(HaveLen(1))
+		})
+
+		It("should list v1beta1 Gateways as v1", func() {
+			betaGateway := (*gatewayv1beta1.Gateway)(newGateway("gw", "default", "istio"))
+			scheme := newTestScheme()
+			c := NewGatewayV1beta1Client(newFakeClient(scheme, betaGateway))
+
+			var gateways gatewayv1.GatewayList
+			Expect(c.List(context.Background(), &gateways)).To(Succeed())
+			Expect(gateways.Items).To(HaveLen(1))
+			Expect(gateways.Items[0].Name).To(Equal("gw"))
+		})
+
+		It("should filter either version by class", func() {
+			filter := gatewayClassFilter([]string{"istio"})
+			Expect(filter.Generic(event.GenericEvent{Object: newGateway("gw", "default", "istio")})).To(BeTrue())
+			Expect(filter.Generic(event.GenericEvent{Object: (*gatewayv1beta1.Gateway)(newGateway("gw", "default", "istio"))})).To(BeTrue())
+			Expect(filter.Generic(event.GenericEvent{Object: (*gatewayv1beta1.Gateway)(newGateway("gw", "default", "other"))})).To(BeFalse())
+		})
+
+		It("should watch the configured version", func() {
+			Expect((&GatewayReconciler{}).gatewayObject()).To(BeAssignableToTypeOf(&gatewayv1.Gateway{}))
+			Expect((&GatewayReconciler{GatewayAPIVersion: GatewayAPIVersionV1beta1}).gatewayObject()).To(BeAssignableToTypeOf(&gatewayv1beta1.Gateway{}))
+		})
+	})
+
+	Context("When Programmed requires ready endpoints", func() {
+		It("should only become programmed once an endpoint is ready", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gw-istio-abc",
+					Namespace: "default",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gw-istio"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses:  []string{"10.0.0.5"},
+					Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)},
+				}},
+			}
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, route, slice), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, RequireEndpoints: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			programmed := meta.FindStatusCondition(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed).NotTo(BeNil())
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayv1.GatewayReasonPending)))
+
+			slice.Endpoints[0].Conditions.Ready = ptr.To(true)
+			Expect(r.Update(context.Background(), slice)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+		})
+
+		It("should not gate Programmed by default", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+		})
+	})
+
+	Context("When the backing service advertises an IP and a hostname", func() {
+		It("should advertise both, IP first", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{assumeProgrammedAnnotation: "true"}
+			svc := newGatewayService(gw, "")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}, {IP: "192.0.2.10"}}
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			addresses := getGateway(r.Client, gw).Status.Addresses
+			Expect(addresses).To(HaveLen(2))
+			Expect(addresses[0].Value).To(Equal("192.0.2.10"))
+			Expect(*addresses[0].Type).To(Equal(gatewayv1.IPAddressType))
+			Expect(addresses[1].Value).To(Equal("lb.example.com"))
+			Expect(*addresses[1].Type).To(Equal(gatewayv1.HostnameAddressType))
+		})
+
+		It("should keep the IP next to the Route host", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "gw.example.com"}, {IP: "192.0.2.10"}}
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var values []string
+			for _, address := range getGateway(r.Client, gw).Status.Addresses {
+				values = append(values, address.Value)
+			}
+			Expect(values).To(Equal([]string{"192.0.2.10", "gw.example.com"}))
+		})
+
+		It("should order addresses independently of the ingress order", func() {
+			Expect(ingressAddresses([]corev1.LoadBalancerIngress{{IP: "2001:db8::10"}, {Hostname: "b.example.com"}, {IP: "192.0.2.10"}, {Hostname: "a.example.com"}})).
+				To(Equal([]string{"192.0.2.10", "2001:db8::10", "a.example.com", "b.example.com"}))
+		})
+	})
+
+	Context("When qualifying custom condition types", func() {
+		It("should default to the tinylb.io prefix", func() {
+			r := &GatewayReconciler{}
+			Expect(r.conditionType(gatewayConditionBackendReady)).To(Equal(gatewayv1.GatewayConditionType("tinylb.io/BackendReady")))
+		})
+
+		It("should report BackendReady under the configured prefix", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme, SupportedGatewayClasses: []string{"istio"},
+				ReportBackendReady: true, DomainPrefix: "lb.example.com"}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			conditions := getGateway(r.Client, gw).Status.Conditions
+			Expect(meta.FindStatusCondition(conditions, "lb.example.com/BackendReady")).NotTo(BeNil())
+			Expect(meta.FindStatusCondition(conditions, "tinylb.io/BackendReady")).To(BeNil())
+		})
+	})
+
+	Context("When taking over Gateways from another controller", func() {
+		staleGateway := func() *gatewayv1.Gateway {
+			gw := newGateway("gw", "default", "istio")
+			gw.UID = "gw-uid"
+			addressType := gatewayv1.IPAddressType
+			gw.Status.Addresses = []gatewayv1.GatewayStatusAddress{{Type: &addressType, Value: "192.0.2.10"}}
+			gw.Status.Conditions = []metav1.Condition{
+				{Type: string(gatewayv1.GatewayConditionAccepted), Status: metav1.ConditionTrue, Reason: "Accepted", LastTransitionTime: metav1.Now()},
+				{Type: "legacy-lb.example.com/Healthy", Status: metav1.ConditionTrue, Reason: "Healthy", LastTransitionTime: metav1.Now()},
+				{Type: "tinylb.io/BackendReady", Status: metav1.ConditionTrue, Reason: "EndpointsReady", LastTransitionTime: metav1.Now()},
+			}
+			return gw
+		}
+		conditionTypes := func(gw *gatewayv1.Gateway) []string {
+			var types []string
+			for _, condition := range gw.Status.Conditions {
+				types = append(types, condition.Type)
+			}
+			return types
+		}
+
+		It("should remove foreign conditions and addresses once", func() {
+			gw := staleGateway()
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, Recorder: recorder, CleanupForeignStatus: true}
+
+			current := getGateway(r.Client, gw)
+			Expect(r.cleanupForeignStatus(context.Background(), current)).To(Succeed())
+			current = getGateway(r.Client, gw)
+			Expect(conditionTypes(current)).To(ConsistOf("Accepted", "tinylb.io/BackendReady"))
+			Expect(current.Status.Addresses).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("legacy-lb.example.com/Healthy")))
+			Expect(r.claimGateway(context.Background(), current)).To(Succeed())
+
+			// Conditions showing up later belong to a controller still running alongside TinyLB
+			meta.SetStatusCondition(&current.Status.Conditions, metav1.Condition{Type: "mesh.example.com/Synced", Status: metav1.ConditionTrue, Reason: "Synced"})
+			Expect(r.Status().Update(context.Background(), current)).To(Succeed())
+			current = getGateway(r.Client, gw)
+			Expect(r.cleanupForeignStatus(context.Background(), current)).To(Succeed())
+			Expect(conditionTypes(getGateway(r.Client, gw))).To(ContainElement("mesh.example.com/Synced"))
+		})
+
+		It("should not clean up a claimed Gateway again after a restart", func() {
+			gw := staleGateway()
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw)
+			newReconciler := func() *GatewayReconciler {
+				return &GatewayReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10),
+					SupportedGatewayClasses: []string{"istio"}, CleanupForeignStatus: true}
+			}
+			_, err := reconcileGateway(context.Background(), newReconciler(), gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getGateway(c, gw).Annotations).To(HaveKeyWithValue(reconciledByAnnotation, "gateway/istio"))
+
+			current := getGateway(c, gw)
+			meta.SetStatusCondition(&current.Status.Conditions, metav1.Condition{Type: "mesh.example.com/Synced", Status: metav1.ConditionTrue, Reason: "Synced"})
+			Expect(c.Status().Update(context.Background(), current)).To(Succeed())
+
+			_, err = reconcileGateway(context.Background(), newReconciler(), gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conditionTypes(getGateway(c, gw))).To(ContainElement("mesh.example.com/Synced"))
+		})
+
+		It("should treat conditions outside the configured prefix as foreign", func() {
+			gw := staleGateway()
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, Recorder: record.NewFakeRecorder(10),
+				CleanupForeignStatus: true, DomainPrefix: "lb.example.com"}
+
+			Expect(r.cleanupForeignStatus(context.Background(), getGateway(r.Client, gw))).To(Succeed())
+			Expect(conditionTypes(getGateway(r.Client, gw))).To(ConsistOf("Accepted"))
+		})
+
+		It("should leave status alone unless enabled", func() {
+			gw := staleGateway()
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conditionTypes(getGateway(r.Client, gw))).To(ContainElement("legacy-lb.example.com/Healthy"))
+		})
+
+		It("should clean up before reconciling the Gateway", func() {
+			gw := staleGateway()
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw), Scheme: scheme, Recorder: record.NewFakeRecorder(10),
+				SupportedGatewayClasses: []string{"istio"}, CleanupForeignStatus: true}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conditionTypes(getGateway(r.Client, gw))).NotTo(ContainElement("legacy-lb.example.com/Healthy"))
+			Expect(conditionTypes(getGateway(r.Client, gw))).To(ContainElement("Programmed"))
+		})
+	})
+
+	Context("When the service ingress has no address yet", func() {
+		It("should report the Gateway pending and requeue", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{}}
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			result, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+			current := getGateway(r.Client, gw)
+			programmed := meta.FindStatusCondition(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed).NotTo(BeNil())
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayv1.GatewayReasonPending)))
+			Expect(current.Status.Addresses).To(BeEmpty())
+		})
+
+		It("should not advertise an empty address for assumed Gateways", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{assumeProgrammedAnnotation: "true"}
+			svc := newGatewayService(gw, "")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "", Hostname: ""}}
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			programmed := meta.FindStatusCondition(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayv1.GatewayReasonPending)))
+		})
+	})
+
+	Context("When matching Gateways by GatewayClass controllerName", func() {
+		newGatewayClass := func(name, controllerName string) *gatewayv1.GatewayClass {
+			return &gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       gatewayv1.GatewayClassSpec{ControllerName: gatewayv1.GatewayController(controllerName)},
+			}
+		}
+		accepted := func(r *GatewayReconciler, gw *gatewayv1.Gateway) *metav1.Condition {
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			return meta.FindStatusCondition(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+		}
+
+		It("should program Gateways whose class names a configured controller", func() {
+			gw := newGateway("gw", "default", "lb")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, newGatewayClass("lb", "example.com/tinylb")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, ControllerNames: []string{"example.com/tinylb"}}
+			Expect(accepted(r, gw)).NotTo(BeNil())
+		})
+
+		It("should skip Gateways of classes owned by other controllers or missing", func() {
+			other := newGateway("other", "default", "nginx")
+			orphan := newGateway("orphan", "default", "gone")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, other, orphan, newGatewayClass("nginx", "k8s.io/nginx")), Scheme: scheme,
+				ControllerNames: []string{"example.com/tinylb"}}
+			Expect(accepted(r, other)).To(BeNil())
+			Expect(accepted(r, orphan)).To(BeNil())
+		})
+
+		It("should fall back to the class name list", func() {
+			gw := newGateway("gw", "default", "istio")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, newGatewayClass("istio", "istio.io/gateway-controller")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, ControllerNames: []string{"example.com/tinylb"}}
+			Expect(accepted(r, gw)).NotTo(BeNil())
+		})
+
+		It("should enqueue the Gateways of a changed GatewayClass", func() {
+			gw := newGateway("gw", "default", "lb")
+			other := newGateway("other", "team-b", "nginx")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, other), Scheme: scheme, ControllerNames: []string{"example.com/tinylb"}}
+			Expect(r.mapGatewayClassToGateways(context.Background(), newGatewayClass("lb", "example.com/tinylb"))).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gw)}))
+		})
+	})
+
+	Context("When reencrypt Routes use the OpenShift service CA", func() {
+		reencryptGateway := func() *gatewayv1.Gateway {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+			}}
+			return gw
+		}
+
+		It("should request the service CA and inject it once available", func() {
+			gw := reencryptGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, UseServiceCA: true}
+			destinationCA := func() string {
+				var route routev1.Route
+				Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &route)).To(Succeed())
+				return route.Spec.TLS.DestinationCACertificate
+			}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destinationCA()).To(BeEmpty())
+
+			var configMap corev1.ConfigMap
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: serviceCAConfigMapName}, &configMap)).To(Succeed())
+			Expect(configMap.Annotations).To(HaveKeyWithValue(serviceCAInjectAnnotation, "true"))
+
+			// The service CA operator injects the bundle, which re-enqueues the Gateway
+			configMap.Data = map[string]string{serviceCAKey: "service-ca"}
+			Expect(r.Update(context.Background(), &configMap)).To(Succeed())
+			Expect(r.mapConfigMapToGateways(context.Background(), &configMap)).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gw)}))
+
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destinationCA()).To(Equal("service-ca"))
+		})
+
+		It("should prefer an explicit destination CA", func() {
+			gw := reencryptGateway()
+			gw.Annotations = map[string]string{destinationCAAnnotation: "gw-ca"}
+			svc := newGatewayService(gw, "gw.example.com")
+			ca := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-ca", Namespace: "default"},
+				Data:       map[string]string{destinationCAKey: "own-ca"},
+			}
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"), ca), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, UseServiceCA: true}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &route)).To(Succeed())
+			Expect(route.Spec.TLS.DestinationCACertificate).To(Equal("own-ca"))
+			Expect(apierrors.IsNotFound(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: serviceCAConfigMapName}, &corev1.ConfigMap{}))).To(BeTrue())
+		})
+
+		It("should delete the service CA ConfigMap once no Gateway uses it", func() {
+			gw := reencryptGateway()
+			gw.UID = "gw-uid"
+			other := reencryptGateway()
+			other.Name, other.UID = "other", "other-uid"
+			svc, otherSvc := newGatewayService(gw, "gw.example.com"), newGatewayService(other, "other.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, other, svc, otherSvc, newManagedRoute(svc, "gw.example.com"), newManagedRoute(otherSvc, "other.example.com"))
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, UseServiceCA: true}
+			owners := func() []types.UID {
+				var configMap corev1.ConfigMap
+				Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: serviceCAConfigMapName}, &configMap)).To(Succeed())
+				var uids []types.UID
+				for _, ref := range configMap.OwnerReferences {
+					uids = append(uids, ref.UID)
+				}
+				return uids
+			}
+
+			for _, g := range []*gatewayv1.Gateway{gw, other} {
+				_, err := reconcileGateway(context.Background(), r, g)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(owners()).To(ConsistOf(types.UID("gw-uid"), types.UID("other-uid")))
+
+			By("dropping a Gateway that switches to its own CA from the owners")
+			current := getGateway(c, gw)
+			current.Annotations[destinationCAAnnotation] = "gw-ca"
+			Expect(c.Update(context.Background(), current)).To(Succeed())
+			_, err := reconcileGateway(context.Background(), r, current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owners()).To(ConsistOf(types.UID("other-uid")))
+
+			By("deleting the ConfigMap when the last Gateway stops using it")
+			current = getGateway(c, other)
+			current.Spec.Listeners = nil
+			Expect(c.Update(context.Background(), current)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apierrors.IsNotFound(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: serviceCAConfigMapName}, &corev1.ConfigMap{}))).To(BeTrue())
+		})
+
+		It("should not request the service CA without reencrypt listeners", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, UseServiceCA: true}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apierrors.IsNotFound(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: serviceCAConfigMapName}, &corev1.ConfigMap{}))).To(BeTrue())
+		})
+	})
+
+	Context("When a Gateway stays pending past the programmed timeout", func() {
+		pendingGateway := func(since time.Time) (*GatewayReconciler, *gatewayv1.Gateway, *record.FakeRecorder) {
+			gw := newGateway("gw", "default", "istio")
+			if !since.IsZero() {
+				gw.Annotations = map[string]string{pendingSinceAnnotation: since.UTC().Format(time.RFC3339)}
+			}
+			svc := newGatewayService(gw, "")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				Recorder: recorder, SupportedGatewayClasses: []string{"istio"}, ProgrammedTimeout: 5 * time.Minute}
+			return r, gw, recorder
+		}
+
+		It("should record when the Gateway became pending and stay Pending until the timeout", func() {
+			r, gw, recorder := pendingGateway(time.Time{})
+			result, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+			current := getGateway(r.Client, gw)
+			Expect(current.Annotations).To(HaveKey(pendingSinceAnnotation))
+			programmed := meta.FindStatusCondition(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Reason).To(Equal(string(gatewayv1.GatewayReasonPending)))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should requeue when the timeout expires", func() {
+			r, gw, _ := pendingGateway(time.Now().Add(-4*time.Minute - 50*time.Second))
+			result, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 10*time.Second))
+		})
+
+		It("should report a Timeout and warn once", func() {
+			r, gw, recorder := pendingGateway(time.Now().Add(-10 * time.Minute))
+			result, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+			programmed := meta.FindStatusCondition(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayReasonTimeout)))
+			Expect(programmed.Message).To(ContainSubstring("5m0s"))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning ProgrammedTimeout")))
+
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should clear the pending record once the service gets an external IP", func() {
+			r, gw, _ := pendingGateway(time.Now().Add(-10 * time.Minute))
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var svc corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "gw-istio"}, &svc)).To(Succeed())
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "gw.example.com"}}
+			Expect(r.Status().Update(context.Background(), &svc)).To(Succeed())
+
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			current := getGateway(r.Client, gw)
+			Expect(current.Annotations).NotTo(HaveKey(pendingSinceAnnotation))
+			programmed := meta.FindStatusCondition(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	Context("When maintaining the Gateway summary", func() {
+		summaryKey := types.NamespacedName{Namespace: "tinylb-system", Name: "gateway-summary"}
+		summary := func(c client.Client) map[string]string {
+			var configMap corev1.ConfigMap
+			if err := c.Get(context.Background(), summaryKey, &configMap); err != nil {
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				return nil
+			}
+			return configMap.Data
+		}
+
+		It("should list programmed Gateways and drop them when they stop being programmed", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, GatewaySummary: summaryKey}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary(r.Client)).To(Equal(map[string]string{"default.gw": "gw.example.com"}))
+
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), svc)).To(Succeed())
+			svc.Status.LoadBalancer.Ingress = nil
+			Expect(r.Status().Update(context.Background(), svc)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary(r.Client)).To(BeEmpty())
+		})
+
+		It("should drop deleted Gateways", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, GatewaySummary: summaryKey}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary(r.Client)).To(HaveKey("default.gw"))
+
+			Expect(r.Delete(context.Background(), gw)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary(r.Client)).NotTo(HaveKey("default.gw"))
+		})
+
+		It("should keep entries written concurrently by another reconciler", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			existing := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: summaryKey.Name, Namespace: summaryKey.Namespace},
+				Data:       map[string]string{"other.gw": "other.example.com"},
+			}
+			scheme := newTestScheme()
+			raced := false
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(gw, svc, newManagedRoute(svc, "gw.example.com"), existing).
+				WithStatusSubresource(&gatewayv1.Gateway{}, &corev1.Service{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+						if configMap, ok := obj.(*corev1.ConfigMap); ok && !raced {
+							raced = true
+							// Another reconciler updates the summary between our read and write
+							concurrent := &corev1.ConfigMap{}
+							Expect(c.Get(ctx, client.ObjectKeyFromObject(configMap), concurrent)).To(Succeed())
+							concurrent.Data["third.gw"] = "third.example.com"
+							Expect(c.Update(ctx, concurrent)).To(Succeed())
+						}
+						return c.Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, GatewaySummary: summaryKey}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(raced).To(BeTrue())
+			Expect(summary(c)).To(Equal(map[string]string{
+				"default.gw": "gw.example.com",
+				"other.gw":   "other.example.com",
+				"third.gw":   "third.example.com",
+			}))
+		})
+	})
+
+	Context("When a Gateway's class stops being supported", func() {
+		var (
+			gw    *gatewayv1.Gateway
+			route *routev1.Route
+		)
+
+		BeforeEach(func() {
+			gw = newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+			}}
+			route = newManagedRoute(newGatewayService(gw, "gw.example.com"), "gw.example.com")
+		})
+
+		programmedGateway := func(r *GatewayReconciler) {
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			current := getGateway(r.Client, gw)
+			Expect(current.Annotations).To(HaveKeyWithValue(reconciledByAnnotation, "gateway/istio"))
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &routev1.Route{})).To(Succeed())
+		}
+
+		It("should clear the status and delete the listener Routes of Gateways it programmed", func() {
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, newGatewayService(gw, "gw.example.com"), route), Scheme: scheme,
+				Recorder: recorder, SupportedGatewayClasses: []string{"istio"}}
+			programmedGateway(r)
+
+			r.SupportedGatewayClasses = []string{"nginx"}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := getGateway(r.Client, gw)
+			Expect(current.Annotations).NotTo(HaveKey(reconciledByAnnotation))
+			Expect(meta.FindStatusCondition(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeNil())
+			Expect(meta.FindStatusCondition(current.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))).To(BeNil())
+			Expect(current.Status.Addresses).To(BeEmpty())
+			Expect(apierrors.IsNotFound(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &routev1.Route{}))).To(BeTrue())
+			// The service's own Route is left to the service controller
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(route), &routev1.Route{})).To(Succeed())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal GatewayReleased")))
+
+			// Released once: later reconciles leave the Gateway alone
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should leave Gateways claimed by another reconciler alone", func() {
+			gw.Annotations = map[string]string{reconciledByAnnotation: "gateway-nginx"}
+			gw.Status.Conditions = []metav1.Condition{{Type: string(gatewayv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue, Reason: "Programmed"}}
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, newGatewayService(gw, "gw.example.com"), route), Scheme: scheme,
+				Recorder: record.NewFakeRecorder(10), SupportedGatewayClasses: []string{"nginx"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := getGateway(r.Client, gw)
+			Expect(current.Annotations).To(HaveKeyWithValue(reconciledByAnnotation, "gateway-nginx"))
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+		})
+
+		It("should leave Gateways claimed by another install with its own controller name alone", func() {
+			gw.Annotations = map[string]string{reconciledByAnnotation: "tinylb-east/istio"}
+			gw.Status.Conditions = []metav1.Condition{{Type: string(gatewayv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue, Reason: "Programmed"}}
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, newGatewayService(gw, "gw.example.com"), route), Scheme: scheme,
+				Recorder: record.NewFakeRecorder(10), ControllerName: "tinylb-west", SupportedGatewayClasses: []string{"nginx"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := getGateway(r.Client, gw)
+			Expect(current.Annotations).To(HaveKeyWithValue(reconciledByAnnotation, "tinylb-east/istio"))
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+		})
+
+		It("should admit claimed Gateways past the class filter", func() {
+			filter := predicate.Or(gatewayClassFilter([]string{"nginx"}), reconciledByFilter("gateway"))
+			Expect(filter.Create(event.CreateEvent{Object: gw})).To(BeFalse())
+			gw.Annotations = map[string]string{reconciledByAnnotation: "gateway/istio"}
+			Expect(filter.Create(event.CreateEvent{Object: gw})).To(BeTrue())
+			gw.Annotations[reconciledByAnnotation] = "gateway-istio/istio"
+			Expect(filter.Create(event.CreateEvent{Object: gw})).To(BeFalse())
+
+			By("recognizing claims written before they recorded the class")
+			gw.Annotations[reconciledByAnnotation] = "gateway"
+			Expect(filter.Create(event.CreateEvent{Object: gw})).To(BeTrue())
+		})
+	})
+
+	Context("When reporting listener status", func() {
+		var gw *gatewayv1.Gateway
+
+		BeforeEach(func() {
+			gw = newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = []gatewayv1.Listener{
+				{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+				{Name: "metrics", Port: 9000, Protocol: gatewayv1.TCPProtocolType},
+			}
+		})
+
+		listener := func(current *gatewayv1.Gateway, name gatewayv1.SectionName) gatewayv1.ListenerStatus {
+			for _, status := range current.Status.Listeners {
+				if status.Name == name {
+					return status
+				}
+			}
+			Fail("no status for listener " + string(name))
+			return gatewayv1.ListenerStatus{}
+		}
+
+		It("should report the service port serving each listener", func() {
+			svc := newGatewayService(gw, "gw.example.com")
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "https", Port: 443, TargetPort: intstr.FromInt(8443)}}
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, ReportListenerStatus: true}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := getGateway(r.Client, gw)
+			Expect(current.Status.Listeners).To(HaveLen(2))
+
+			https := listener(current, "https")
+			Expect(https.SupportedKinds).To(ContainElement(HaveField("Kind", gatewayv1.Kind("HTTPRoute"))))
+			programmed := meta.FindStatusCondition(https.Conditions, string(gatewayv1.ListenerConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionTrue))
+			Expect(programmed.Message).To(Equal("Programmed via service gw-istio port 443 (target port 8443) route tinylb-gw-istio"))
+
+			metrics := listener(current, "metrics")
+			accepted := meta.FindStatusCondition(metrics.Conditions, string(gatewayv1.ListenerConditionAccepted))
+			Expect(accepted.Status).To(Equal(metav1.ConditionFalse))
+			Expect(accepted.Reason).To(Equal(string(gatewayv1.ListenerReasonPortUnavailable)))
+		})
+
+		It("should report listeners pending until the service has an address", func() {
+			svc := newGatewayService(gw, "")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, ReportListenerStatus: true}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			programmed := meta.FindStatusCondition(listener(getGateway(r.Client, gw), "https").Conditions, string(gatewayv1.ListenerConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayv1.ListenerReasonPending)))
+		})
+
+		It("should leave listener status alone unless enabled", func() {
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getGateway(r.Client, gw).Status.Listeners).To(BeEmpty())
+		})
+	})
+
+	Context("When mirroring listener TLS secrets", func() {
+		newTerminateGateway := func() *gatewayv1.Gateway {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS: &gatewayv1.GatewayTLSConfig{
+					Mode:            ptr.To(gatewayv1.TLSModeTerminate),
+					CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "web-cert"}},
+				},
+			}}
+			return gw
+		}
+		newCertSecret := func(data string) *corev1.Secret {
+			return &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-cert", Namespace: "default"},
+				Type:       corev1.SecretTypeTLS,
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte(data), corev1.TLSPrivateKeyKey: []byte("key")},
+			}
+		}
+		listenerRoute := func(c client.Client, namespace string) *routev1.Route {
+			var route routev1.Route
+			Expect(c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "tinylb-gw-istio-web"}, &route)).To(Succeed())
+			return &route
+		}
+
+		It("should reference the listener's Secret directly in the Gateway's namespace", func() {
+			gw := newTerminateGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"), newCertSecret("cert"))
+			r := &GatewayReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), SupportedGatewayClasses: []string{"istio"}, MirrorTLSSecrets: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listenerRoute(c, "default").Spec.TLS.ExternalCertificate).To(Equal(&routev1.LocalObjectReference{Name: "web-cert"}))
+
+			var secrets corev1.SecretList
+			Expect(c.List(context.Background(), &secrets)).To(Succeed())
+			Expect(secrets.Items).To(HaveLen(1))
+		})
+
+		It("should mirror the Secret into the route namespace and keep it in sync", func() {
+			gw := newTerminateGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			source := newCertSecret("cert")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, route, source)
+			r := &GatewayReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), SupportedGatewayClasses: []string{"istio"}, RouteNamespace: "routes", MirrorTLSSecrets: true}
+			mirror := func() *corev1.Secret {
+				var secret corev1.Secret
+				Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "routes", Name: "tinylb-gw-istio-web"}, &secret)).To(Succeed())
+				return &secret
+			}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listenerRoute(c, "routes").Spec.TLS.ExternalCertificate).To(Equal(&routev1.LocalObjectReference{Name: "tinylb-gw-istio-web"}))
+			Expect(mirror().Type).To(Equal(corev1.SecretTypeTLS))
+			Expect(mirror().Data[corev1.TLSCertKey]).To(Equal([]byte("cert")))
+			Expect(mirror().Annotations).To(HaveKeyWithValue(mirroredFromAnnotation, "default/web-cert"))
+
+			Expect(mirror().Labels).To(HaveKeyWithValue(managedLabel, "true"))
+			Expect(r.mapSecretToGateways(context.Background(), mirror())).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gw)}))
+
+			// Listener Secrets are not watched, so rotation is picked up on the periodic recheck
+			source.Data[corev1.TLSCertKey] = []byte("rotated")
+			Expect(c.Update(context.Background(), source)).To(Succeed())
+			result, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(uncachedRecheckInterval))
+			Expect(mirror().Data[corev1.TLSCertKey]).To(Equal([]byte("rotated")))
+		})
+
+		It("should not overwrite a Secret it does not manage", func() {
+			gw := newTerminateGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			users := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "tinylb-gw-istio-web", Namespace: "routes"},
+				Data:       map[string][]byte{"token": []byte("keep")},
+			}
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, route, newCertSecret("cert"), users)
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: c, Scheme: scheme, Recorder: recorder, SupportedGatewayClasses: []string{"istio"}, RouteNamespace: "routes", MirrorTLSSecrets: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("TLSSecretConflict")))
+			Expect(listenerRoute(c, "routes").Spec.TLS.ExternalCertificate).To(BeNil())
+			_, err = reconcileGateway(context.Background(), r, getGateway(c, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("TLSSecretConflict")))
+
+			var current corev1.Secret
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(users), &current)).To(Succeed())
+			Expect(current.Data).To(Equal(map[string][]byte{"token": []byte("keep")}))
+			Expect(current.Labels).To(BeEmpty())
+		})
+
+		It("should delete the mirror when the listener is removed", func() {
+			gw := newTerminateGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, route, newCertSecret("cert"))
+			r := &GatewayReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), SupportedGatewayClasses: []string{"istio"}, RouteNamespace: "routes", MirrorTLSSecrets: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := getGateway(c, gw)
+			current.Spec.Listeners = []gatewayv1.Listener{{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType}}
+			Expect(c.Update(context.Background(), current)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var secrets corev1.SecretList
+			Expect(c.List(context.Background(), &secrets, client.InNamespace("routes"))).To(Succeed())
+			Expect(secrets.Items).To(BeEmpty())
+		})
+
+		It("should delete the mirror when the Gateway is deleted", func() {
+			gw := newTerminateGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			// A Gateway of the same name in another namespace keeps its own mirror
+			other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name: "tinylb-gw-istio-other", Namespace: "routes",
+				Labels:      map[string]string{managedLabel: "true", serviceLabel: "gw-istio", gatewayLabel: "gw"},
+				Annotations: map[string]string{mirroredFromAnnotation: "staging/web-cert"},
+			}}
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, route, newCertSecret("cert"), other)
+			r := &GatewayReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), SupportedGatewayClasses: []string{"istio"}, RouteNamespace: "routes", MirrorTLSSecrets: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Delete(context.Background(), gw)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var secrets corev1.SecretList
+			Expect(c.List(context.Background(), &secrets, client.InNamespace("routes"))).To(Succeed())
+			Expect(secrets.Items).To(HaveLen(1))
+			Expect(secrets.Items[0].Name).To(Equal("tinylb-gw-istio-other"))
+		})
+
+		It("should warn and fall back to the router certificate when the Secret is missing", func() {
+			gw := newTerminateGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, route)
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: c, Scheme: scheme, Recorder: recorder, SupportedGatewayClasses: []string{"istio"}, RouteNamespace: "routes", MirrorTLSSecrets: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("TLSSecretNotFound")))
+			Expect(listenerRoute(c, "routes").Spec.TLS.ExternalCertificate).To(BeNil())
+
+			By("not repeating the warning on the next reconcile")
+			_, err = reconcileGateway(context.Background(), r, getGateway(c, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("TLSSecretNotFound")))
+
+			By("warning again once a recreated Secret goes missing")
+			Expect(c.Create(context.Background(), newCertSecret("cert"))).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, getGateway(c, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listenerRoute(c, "routes").Spec.TLS.ExternalCertificate).NotTo(BeNil())
+			Expect(c.Delete(context.Background(), newCertSecret("cert"))).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, getGateway(c, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("TLSSecretNotFound")))
+		})
+	})
+
+	Context("When counting HTTPRoutes attached through allowedRoutes", func() {
+		newHTTPRoute := func(name, namespace string) *gatewayv1.HTTPRoute {
+			return &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "gw", Namespace: ptr.To(gatewayv1.Namespace("default"))}},
+				}},
+			}
+		}
+		newNamespace := func(name string, labels map[string]string) *corev1.Namespace {
+			return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		}
+		attachedRoutes := func(namespaces *gatewayv1.RouteNamespaces) (int32, *record.FakeRecorder) {
+			gw := newGateway("gw", "default", "istio")
+			if namespaces != nil {
+				gw.Spec.Listeners[0].AllowedRoutes = &gatewayv1.AllowedRoutes{Namespaces: namespaces}
+			}
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"),
+				newNamespace("default", nil), newNamespace("shop", map[string]string{"gateway-access": "true"}), newNamespace("blog", nil),
+				newHTTPRoute("home", "default"), newHTTPRoute("cart", "shop"), newHTTPRoute("posts", "blog"))
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: c, Scheme: scheme, Recorder: recorder, SupportedGatewayClasses: []string{"istio"}, ReportListenerStatus: true, CountAttachedRoutes: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			listeners := getGateway(c, gw).Status.Listeners
+			Expect(listeners).To(HaveLen(1))
+			return listeners[0].AttachedRoutes, recorder
+		}
+
+		It("should only attach HTTPRoutes from the Gateway's namespace by default", func() {
+			attached, recorder := attachedRoutes(nil)
+			Expect(attached).To(Equal(int32(1)))
+			Expect(recorder.Events).To(Receive(ContainSubstring("RouteNotAllowed")))
+		})
+
+		It("should only attach HTTPRoutes from the Gateway's namespace with Same", func() {
+			attached, _ := attachedRoutes(&gatewayv1.RouteNamespaces{From: ptr.To(gatewayv1.NamespacesFromSame)})
+			Expect(attached).To(Equal(int32(1)))
+		})
+
+		It("should attach HTTPRoutes from every namespace with All", func() {
+			attached, recorder := attachedRoutes(&gatewayv1.RouteNamespaces{From: ptr.To(gatewayv1.NamespacesFromAll)})
+			Expect(attached).To(Equal(int32(3)))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should attach HTTPRoutes from namespaces matching the Selector", func() {
+			attached, recorder := attachedRoutes(&gatewayv1.RouteNamespaces{
+				From:     ptr.To(gatewayv1.NamespacesFromSelector),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"gateway-access": "true"}},
+			})
+			Expect(attached).To(Equal(int32(1)))
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect([]string{<-recorder.Events, <-recorder.Events}).To(ConsistOf(
+				ContainSubstring("HTTPRoute default/home"), ContainSubstring("HTTPRoute blog/posts")))
+		})
+
+		It("should warn about refused HTTPRoutes and invalid selectors once while they stand", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = append(gw.Spec.Listeners, gatewayv1.Listener{
+				Name: "broken", Port: 8443, Protocol: gatewayv1.HTTPSProtocolType,
+				AllowedRoutes: &gatewayv1.AllowedRoutes{Namespaces: &gatewayv1.RouteNamespaces{
+					From: ptr.To(gatewayv1.NamespacesFromSelector),
+					Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "team", Operator: "Bogus"},
+					}},
+				}},
+			})
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"),
+				newNamespace("default", nil), newNamespace("blog", nil), newHTTPRoute("posts", "blog"))
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: c, Scheme: scheme, Recorder: recorder, SupportedGatewayClasses: []string{"istio"}, ReportListenerStatus: true, CountAttachedRoutes: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect([]string{<-recorder.Events, <-recorder.Events}).To(ConsistOf(
+				ContainSubstring("InvalidAllowedRoutes"), ContainSubstring("RouteNotAllowed")))
+
+			_, err = reconcileGateway(context.Background(), r, getGateway(c, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+
+			By("warning again about an HTTPRoute that is deleted and comes back")
+			Expect(c.Delete(context.Background(), newHTTPRoute("posts", "blog"))).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, getGateway(c, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Create(context.Background(), newHTTPRoute("posts", "blog"))).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, getGateway(c, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("HTTPRoute blog/posts")))
+		})
+
+		It("should only count HTTPRoutes targeting the listener's section", func() {
+			route := newHTTPRoute("admin", "default")
+			route.Spec.ParentRefs[0].SectionName = ptr.To(gatewayv1.SectionName("admin"))
+			gw := newGateway("gw", "default", "istio")
+			Expect(parentRefTargets(route.Spec.ParentRefs[0], route.Namespace, gw, gw.Spec.Listeners[0])).To(BeFalse())
+			Expect(parentRefTargets(newHTTPRoute("home", "default").Spec.ParentRefs[0], "default", gw, gw.Spec.Listeners[0])).To(BeTrue())
+		})
+
+		It("should enqueue the parent Gateways of an HTTPRoute", func() {
+			r := &GatewayReconciler{}
+			route := newHTTPRoute("cart", "shop")
+			route.Spec.ParentRefs = append(route.Spec.ParentRefs, gatewayv1.ParentReference{Name: "local"})
+			Expect(r.mapHTTPRouteToGateways(context.Background(), route)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "gw"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "shop", Name: "local"}},
+			))
+		})
+	})
+
+	Context("When the Gateway requires its Route to be admitted", func() {
+		setAdmission := func(route *routev1.Route, status corev1.ConditionStatus, reason, message string) {
+			route.Status.Ingress = []routev1.RouteIngress{{
+				Host:       route.Spec.Host,
+				RouterName: "default",
+				Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: status, Reason: reason, Message: message}},
+			}}
+		}
+
+		It("should push the Gateway back to not programmed when the router rejects the Route", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			setAdmission(route, corev1.ConditionTrue, "", "")
+			scheme := newTestScheme()
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw, svc, route).
+				WithStatusSubresource(&gatewayv1.Gateway{}, &routev1.Route{}).Build()
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, RequireRouteAdmission: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			programmed := meta.FindStatusCondition(getGateway(c, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionTrue))
+
+			setAdmission(route, corev1.ConditionFalse, "HostAlreadyClaimed", "route web already exposes gw.example.com")
+			Expect(c.Status().Update(context.Background(), route)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := getGateway(c, gw)
+			programmed = meta.FindStatusCondition(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayv1.GatewayReasonInvalid)))
+			Expect(programmed.Message).To(Equal("Route tinylb-gw-istio was rejected by the router: HostAlreadyClaimed: route web already exposes gw.example.com"))
+			Expect(current.Status.Addresses).To(BeEmpty())
+		})
+
+		It("should keep the Gateway pending until a router reports", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"))
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, RequireRouteAdmission: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			programmed := meta.FindStatusCondition(getGateway(c, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayv1.GatewayReasonPending)))
+		})
+
+		It("should only check existence without the option", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			setAdmission(route, corev1.ConditionFalse, "HostAlreadyClaimed", "")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, route)
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			programmed := meta.FindStatusCondition(getGateway(c, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	Context("When a Gateway carries conditions differing only in case", func() {
+		condition := func(conditionType string, age time.Duration) metav1.Condition {
+			return metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, Reason: "Set", LastTransitionTime: metav1.NewTime(time.Now().Add(-age))}
+		}
+
+		It("should keep the newest condition of each type", func() {
+			conditions, removed := dedupeConditions([]metav1.Condition{
+				condition("Ready", time.Hour), condition("Other", time.Hour), condition("READY", time.Minute), condition("ready", 2*time.Hour),
+			}, "Programmed")
+			Expect(conditions).To(HaveLen(2))
+			Expect(conditions[0].Type).To(Equal("READY"))
+			Expect(conditions[1].Type).To(Equal("Other"))
+			Expect(removed).To(ConsistOf("Ready", "ready"))
+		})
+
+		It("should keep the condition being written over newer near-duplicates", func() {
+			conditions, removed := dedupeConditions([]metav1.Condition{
+				condition("programmed", time.Minute), condition("Programmed", time.Hour),
+			}, "Programmed")
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].Type).To(Equal("Programmed"))
+			Expect(removed).To(ConsistOf("programmed"))
+		})
+
+		It("should normalize the Gateway's conditions on reconcile", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Status.Conditions = []metav1.Condition{
+				condition("Accepted", time.Hour), condition("accepted", time.Minute),
+				condition("programmed", time.Minute), condition("example.com/Ready", time.Hour),
+			}
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"))
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var conditionTypes []string
+			for _, condition := range getGateway(c, gw).Status.Conditions {
+				conditionTypes = append(conditionTypes, condition.Type)
+			}
+			Expect(conditionTypes).To(ConsistOf("Accepted", "Programmed", "example.com/Ready"))
+		})
+	})
+
+	Context("When a Gateway limits the listeners it exposes", func() {
+		newExposingGateway := func(exposed string) *gatewayv1.Gateway {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{exposeListenersAnnotation: exposed}
+			gw.Spec.Listeners = []gatewayv1.Listener{
+				{
+					Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+					Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+				},
+				{
+					Name: "admin", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+					Hostname: ptr.To(gatewayv1.Hostname("admin.example.com")),
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+				},
+			}
+			return gw
+		}
+		listenerRouteNames := func(c client.Client) []string {
+			var routes routev1.RouteList
+			Expect(c.List(context.Background(), &routes, client.MatchingLabels{gatewayLabel: "gw"})).To(Succeed())
+			var names []string
+			for _, route := range routes.Items {
+				names = append(names, route.Name)
+			}
+			return names
+		}
+
+		It("should only create Routes for the listed listeners", func() {
+			gw := newExposingGateway("web")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"))
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, ReportListenerStatus: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listenerRouteNames(c)).To(ConsistOf("tinylb-gw-istio-web"))
+
+			listeners := getGateway(c, gw).Status.Listeners
+			Expect(listeners).To(HaveLen(2))
+			web := meta.FindStatusCondition(listeners[0].Conditions, string(gatewayv1.ListenerConditionProgrammed))
+			Expect(web.Status).To(Equal(metav1.ConditionTrue))
+			admin := meta.FindStatusCondition(listeners[1].Conditions, string(gatewayv1.ListenerConditionProgrammed))
+			Expect(admin.Status).To(Equal(metav1.ConditionFalse))
+			Expect(admin.Reason).To(Equal(string(listenerReasonNotExposed)))
+		})
+
+		It("should delete the Route of a listener dropped from the list", func() {
+			gw := newExposingGateway("web, admin")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com"))
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listenerRouteNames(c)).To(ConsistOf("tinylb-gw-istio-web", "tinylb-gw-istio-admin"))
+
+			current := getGateway(c, gw)
+			current.Annotations[exposeListenersAnnotation] = "admin"
+			Expect(c.Update(context.Background(), current)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listenerRouteNames(c)).To(ConsistOf("tinylb-gw-istio-admin"))
+		})
+
+		It("should warn about names matching no listener", func() {
+			gw := newExposingGateway("web,websecure")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, Recorder: recorder, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("does not have: websecure")))
+
+			By("not repeating the warning while the annotation is unchanged")
+			_, err = reconcileGateway(context.Background(), r, getGateway(r.Client, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("does not have")))
+		})
+	})
+
+	Context("When a Gateway has no hostname to advertise", func() {
+		const template = "{gateway}-{namespace}.apps.example.com"
+
+		newIPOnlyService := func(gw *gatewayv1.Gateway) *corev1.Service {
+			svc := newGatewayService(gw, "")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.0.2.10"}}
+			return svc
+		}
+		addressValues := func(gateway *gatewayv1.Gateway) []string {
+			var values []string
+			for _, address := range gateway.Status.Addresses {
+				values = append(values, address.Value)
+			}
+			return values
+		}
+
+		It("should advertise the templated host next to the service's IPs", func() {
+			gw := newGateway("edge", "shop", "istio")
+			svc := newIPOnlyService(gw)
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, DefaultGatewayHostTemplate: template}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			current := getGateway(r.Client, gw)
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+			Expect(addressValues(current)).To(Equal([]string{"192.0.2.10", "edge-shop.apps.example.com"}))
+		})
+
+		It("should advertise the templated host when the Gateway assumes it is programmed", func() {
+			gw := newGateway("edge", "shop", "istio")
+			gw.Annotations = map[string]string{assumeProgrammedAnnotation: "true"}
+			svc := newIPOnlyService(gw)
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, DefaultGatewayHostTemplate: template}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(addressValues(getGateway(r.Client, gw))).To(Equal([]string{"192.0.2.10", "edge-shop.apps.example.com"}))
+		})
+
+		It("should leave Gateways with a listener hostname alone", func() {
+			gw := newGateway("edge", "shop", "istio")
+			gw.Spec.Listeners[0].Hostname = ptr.To(gatewayv1.Hostname("shop.example.com"))
+			gw.Annotations = map[string]string{assumeProgrammedAnnotation: "true"}
+			svc := newIPOnlyService(gw)
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, DefaultGatewayHostTemplate: template}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(addressValues(getGateway(r.Client, gw))).To(Equal([]string{"192.0.2.10"}))
+		})
+
+		It("should render sanitized hosts and validate templates", func() {
+			gw := newGateway("Edge.v2", "shop", "istio")
+			Expect(gatewayHost(template, gw)).To(Equal("edge-v2-shop.apps.example.com"))
+
+			Expect(ValidateGatewayHostTemplate("")).To(Succeed())
+			Expect(ValidateGatewayHostTemplate(template)).To(Succeed())
+			Expect(ValidateGatewayHostTemplate("{namespace}.apps.example.com")).NotTo(Succeed())
+			Expect(ValidateGatewayHostTemplate("{gateway}..example.com")).NotTo(Succeed())
+		})
+	})
+
+	Context("When correlating listener Routes with their Gateway", func() {
+		newTLSGateway := func() *gatewayv1.Gateway {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+			}}
+			return gw
+		}
+
+		It("should find the service's Route next to the service when listener Routes are central", func() {
+			gw := newTLSGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, RouteNamespace: "routes"}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+		})
+
+		It("should delete its central listener Routes when the Gateway is deleted", func() {
+			gw := newTLSGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			// A Gateway of the same name in another namespace keeps its own listener Route
+			other := &routev1.Route{ObjectMeta: metav1.ObjectMeta{
+				Name: "tinylb-gw-istio-other", Namespace: "routes",
+				Labels:      map[string]string{managedLabel: "true", serviceLabel: "gw-istio", gatewayLabel: "gw"},
+				Annotations: map[string]string{gatewayAnnotation: "gw", gatewayNamespaceAnnotation: "staging"},
+			}}
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, gw, svc, other)
+			r := &GatewayReconciler{Client: c, Scheme: scheme, SupportedGatewayClasses: []string{"istio"}, RouteNamespace: "routes"}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var routes routev1.RouteList
+			Expect(c.List(context.Background(), &routes, client.InNamespace("routes"))).To(Succeed())
+			Expect(routes.Items).To(HaveLen(2))
+
+			Expect(c.Delete(context.Background(), gw)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.List(context.Background(), &routes, client.InNamespace("routes"))).To(Succeed())
+			Expect(routes.Items).To(HaveLen(1))
+			Expect(routes.Items[0].Name).To(Equal("tinylb-gw-istio-other"))
+		})
+
+		It("should annotate Routes in a central namespace with the Gateway's name and namespace", func() {
+			gw := newTLSGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, RouteNamespace: "routes"}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var web routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "routes", Name: "tinylb-gw-istio-web"}, &web)).To(Succeed())
+			Expect(web.Annotations).To(HaveKeyWithValue(gatewayAnnotation, "gw"))
+			Expect(web.Annotations).To(HaveKeyWithValue(gatewayNamespaceAnnotation, "default"))
+		})
+
+		It("should add the annotations to existing Routes and keep foreign ones", func() {
+			gw := newTLSGateway()
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			key := client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}
+			var web routev1.Route
+			Expect(r.Get(context.Background(), key, &web)).To(Succeed())
+			web.Annotations = map[string]string{"example.com/owner": "team-a"}
+			Expect(r.Update(context.Background(), &web)).To(Succeed())
+
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), key, &web)).To(Succeed())
+			Expect(web.Annotations).To(Equal(map[string]string{
+				"example.com/owner":        "team-a",
+				gatewayAnnotation:          "gw",
+				gatewayNamespaceAnnotation: "default",
+			}))
+		})
+	})
+
+	Context("When Gateways show how their service is exposed", func() {
+		It("should annotate the Gateway with the Route's port and termination", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{"example.com/team": "edge"}
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			route.Spec.Port = &routev1.RoutePort{TargetPort: intstr.FromString("https")}
+			route.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough}
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, route), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, ExposureAnnotations: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			current := getGateway(r.Client, gw)
+			Expect(current.Annotations).To(HaveKeyWithValue(exposedPortAnnotation, "https"))
+			Expect(current.Annotations).To(HaveKeyWithValue(exposedTerminationAnnotation, "passthrough"))
+			Expect(current.Annotations).To(HaveKeyWithValue("example.com/team", "edge"))
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+		})
+
+		It("should follow the Route and clear the annotations once it is gone", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			route.Spec.Port = &routev1.RoutePort{TargetPort: intstr.FromInt(8080)}
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, route), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, ExposureAnnotations: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			current := getGateway(r.Client, gw)
+			Expect(current.Annotations).To(HaveKeyWithValue(exposedPortAnnotation, "8080"))
+			Expect(current.Annotations).To(HaveKeyWithValue(exposedTerminationAnnotation, "none"))
+
+			Expect(r.Delete(context.Background(), route)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			current = getGateway(r.Client, gw)
+			Expect(current.Annotations).NotTo(HaveKey(exposedPortAnnotation))
+			Expect(current.Annotations).NotTo(HaveKey(exposedTerminationAnnotation))
+		})
+
+		It("should leave the Gateway unannotated unless enabled", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getGateway(r.Client, gw).Annotations).NotTo(HaveKey(exposedTerminationAnnotation))
+		})
+	})
+
+	Context("When Programmed depends on the Gateway's listeners", func() {
+		// reconcilePartiallyReady reconciles a Gateway whose service serves only one of its two listeners
+		reconcilePartiallyReady := func(policy ProgrammedPolicy) *gatewayv1.Gateway {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = append(gw.Spec.Listeners, gatewayv1.Listener{Name: "admin", Port: 9443, Protocol: gatewayv1.HTTPSProtocolType})
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, ProgrammedRequires: policy}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			return getGateway(r.Client, gw)
+		}
+
+		It("should be programmed with one ready listener when any is required", func() {
+			current := reconcilePartiallyReady(ProgrammedRequiresAny)
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+			Expect(current.Status.Addresses).To(HaveLen(1))
+		})
+
+		It("should not be programmed until every listener is ready when all are required", func() {
+			current := reconcilePartiallyReady(ProgrammedRequiresAll)
+			programmed := meta.FindStatusCondition(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed).NotTo(BeNil())
+			Expect(programmed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmed.Reason).To(Equal(string(gatewayv1.GatewayReasonListenersNotReady)))
+			Expect(programmed.Message).To(Equal("Listeners not programmed: admin"))
+			Expect(current.Status.Addresses).To(BeEmpty())
+		})
+
+		It("should ignore listeners by default", func() {
+			current := reconcilePartiallyReady("")
+			Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+		})
+
+		It("should not be programmed without any ready listener when any is required", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners[0].Port = 9443
+			svc := newGatewayService(gw, "gw.example.com")
+			Expect(unreadyListeners(gw, svc, ProgrammedRequiresAny)).To(Equal([]string{"https"}))
+			Expect(unreadyListeners(gw, svc, ProgrammedRequiresNone)).To(BeEmpty())
+		})
+
+		It("should parse the policy", func() {
+			for _, value := range []string{"none", "any", "all"} {
+				policy, err := ParseProgrammedPolicy(value)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(policy)).To(Equal(value))
+			}
+			_, err := ParseProgrammedPolicy("most")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Effective configuration endpoint", func() {
+		It("should serve the configured values as JSON", func() {
+			messages, err := NewConditionMessages(map[string]string{MessageProgrammed: "Ready on {{.Hostname}}"})
+			Expect(err).NotTo(HaveOccurred())
+			service := &ServiceReconciler{
+				BaseDomains:      []string{"apps.example.com", "apps.internal"},
+				OptInAnnotation:  ProviderAnnotation,
+				ValidatePorts:    true,
+				DrainGracePeriod: 90 * time.Second,
+			}
+			gateway := &GatewayReconciler{
+				SupportedGatewayClasses:    []string{"istio"},
+				RouteNamespace:             "routes",
+				Messages:                   messages,
+				DefaultGatewayHostTemplate: "{gateway}-{namespace}",
+				ProgrammedRequires:         ProgrammedRequiresAll,
+			}
+
+			recorder := httptest.NewRecorder()
+			ConfigHandler(NewEffectiveConfig(ManagerConfig{LeaderElection: true, SyncPeriod: "10h0m0s"}, service, gateway)).
+				ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config", nil))
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+			var config struct {
+				Manager  map[string]any   `json:"manager"`
+				Service  map[string]any   `json:"service"`
+				Gateways []map[string]any `json:"gateways"`
+			}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &config)).To(Succeed())
+			Expect(config.Manager).To(HaveKeyWithValue("leaderElection", true))
+			Expect(config.Manager).To(HaveKeyWithValue("syncPeriod", "10h0m0s"))
+			Expect(config.Service).To(HaveKeyWithValue("baseDomains", ConsistOf("apps.example.com", "apps.internal")))
+			Expect(config.Service).To(HaveKeyWithValue("optInAnnotation", ProviderAnnotation))
+			Expect(config.Service).To(HaveKeyWithValue("validatePorts", true))
+			Expect(config.Service).To(HaveKeyWithValue("drainGracePeriod", "1m30s"))
+			Expect(config.Service).To(HaveKeyWithValue("hostRegistry", ""))
+
+			Expect(config.Gateways).To(HaveLen(1))
+			Expect(config.Gateways[0]).To(HaveKeyWithValue("controllerName", "gateway"))
+			Expect(config.Gateways[0]).To(HaveKeyWithValue("supportedGatewayClasses", ConsistOf("istio")))
+			Expect(config.Gateways[0]).To(HaveKeyWithValue("routeNamespace", "routes"))
+			Expect(config.Gateways[0]).To(HaveKeyWithValue("defaultGatewayHostTemplate", "{gateway}-{namespace}"))
+			Expect(config.Gateways[0]).To(HaveKeyWithValue("programmedRequires", "all"))
+			Expect(config.Gateways[0]).To(HaveKeyWithValue("messages",
+				HaveKeyWithValue(MessageProgrammed, "Ready on {{.Hostname}}")))
+		})
+
+		It("should fail rather than serve a partial document when encoding fails", func() {
+			recorder := httptest.NewRecorder()
+			ConfigHandler(NewEffectiveConfig(ManagerConfig{}, &ServiceReconciler{RouteCreateQPS: math.Inf(1)})).
+				ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config", nil))
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+
+		It("should reject writes", func() {
+			recorder := httptest.NewRecorder()
+			ConfigHandler(NewEffectiveConfig(ManagerConfig{}, &ServiceReconciler{})).ServeHTTP(recorder,
+				httptest.NewRequest(http.MethodPost, "/config", nil))
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+
+	Context("When a Gateway requests a rewrite target", func() {
+		It("should set it on TLS-terminating listener Routes only", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{rewriteTargetAnnotation: "/"}
+			gw.Spec.Listeners = []gatewayv1.Listener{
+				{
+					Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+					Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+				},
+				{
+					Name: "db", Port: 8443, Protocol: gatewayv1.TLSProtocolType,
+					Hostname: ptr.To(gatewayv1.Hostname("db.example.com")),
+					TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModePassthrough)},
+				},
+			}
+			svc := newGatewayService(gw, "gw.example.com")
+
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+
+			var web, db routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &web)).To(Succeed())
+			Expect(web.Annotations).To(HaveKeyWithValue(rewriteTargetRouteAnnotation, "/"))
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-db"}, &db)).To(Succeed())
+			Expect(db.Annotations).NotTo(HaveKey(rewriteTargetRouteAnnotation))
+
+			By("removing it again once the Gateway annotation goes away")
+			gw = getGateway(r.Client, gw)
+			gw.Annotations = nil
+			Expect(r.Update(context.Background(), gw)).To(Succeed())
+			_, err = reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "tinylb-gw-istio-web"}, &web)).To(Succeed())
+			Expect(web.Annotations).NotTo(HaveKey(rewriteTargetRouteAnnotation))
+		})
+
+		It("should warn about an invalid target once", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Annotations = map[string]string{rewriteTargetAnnotation: "api"}
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+			}}
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme, Recorder: recorder, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring(rewriteTargetAnnotation)))
+			_, err = reconcileGateway(context.Background(), r, getGateway(r.Client, gw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring(rewriteTargetAnnotation)))
+		})
+
+		It("should validate the target", func() {
+			Expect(validateRewriteTarget("/")).To(Succeed())
+			Expect(validateRewriteTarget("/api/v1")).To(Succeed())
+			Expect(validateRewriteTarget("api")).NotTo(Succeed())
+			Expect(validateRewriteTarget("/a b")).NotTo(Succeed())
+		})
+	})
+
+	Context("When reporting how a Gateway is programmed", func() {
+		It("should name the service, port and Route in the Programmed message", func() {
+			gw := newGateway("gw", "default", "istio")
+			svc := newGatewayService(gw, "gw.example.com")
+			route := newManagedRoute(svc, "gw.example.com")
+			route.Spec.Port = &routev1.RoutePort{TargetPort: intstr.FromString("https")}
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, route), Scheme: scheme, SupportedGatewayClasses: []string{"istio"}}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			programmed := meta.FindStatusCondition(getGateway(r.Client, gw).Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(programmed.Status).To(Equal(metav1.ConditionTrue))
+			Expect(programmed.Message).To(Equal("Programmed via service gw-istio port https route tinylb-gw-istio"))
+		})
+
+		It("should name a listener's own Route in its Programmed message", func() {
+			gw := newGateway("gw", "default", "istio")
+			gw.Spec.Listeners = []gatewayv1.Listener{{
+				Name: "web", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				Hostname: ptr.To(gatewayv1.Hostname("web.example.com")),
+				TLS:      &gatewayv1.GatewayTLSConfig{Mode: ptr.To(gatewayv1.TLSModeTerminate)},
+			}}
+			svc := newGatewayService(gw, "gw.example.com")
+			scheme := newTestScheme()
+			r := &GatewayReconciler{Client: newFakeClient(scheme, gw, svc, newManagedRoute(svc, "gw.example.com")), Scheme: scheme,
+				SupportedGatewayClasses: []string{"istio"}, ReportListenerStatus: true}
+
+			_, err := reconcileGateway(context.Background(), r, gw)
+			Expect(err).NotTo(HaveOccurred())
+			current := getGateway(r.Client, gw)
+			Expect(current.Status.Listeners).To(HaveLen(1))
+			programmed := meta.FindStatusCondition(current.Status.Listeners[0].Conditions, string(gatewayv1.ListenerConditionProgrammed))
+			Expect(programmed.Message).To(Equal("Programmed via service gw-istio port 443 (target port 443) route tinylb-gw-istio-web"))
+		})
+	})
+})
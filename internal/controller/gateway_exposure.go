@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// exposedPortAnnotation shows on a Gateway the service port its service's Route targets
+	exposedPortAnnotation = "tinylb.io/exposed-port"
+
+	// exposedTerminationAnnotation shows on a Gateway the TLS termination of its service's Route,
+	// or "none" for a plain HTTP Route
+	exposedTerminationAnnotation = "tinylb.io/exposed-termination"
+)
+
+// exposureAnnotations lists the Gateway annotations TinyLB maintains with --gateway-exposure-annotations
+var exposureAnnotations = []string{exposedPortAnnotation, exposedTerminationAnnotation}
+
+// routeExposure returns the exposure annotations describing how route serves a Gateway's service
+func routeExposure(route *routev1.Route) map[string]string {
+	exposure := map[string]string{exposedTerminationAnnotation: "none"}
+	if route.Spec.Port != nil {
+		exposure[exposedPortAnnotation] = route.Spec.Port.TargetPort.String()
+	}
+	if route.Spec.TLS != nil {
+		exposure[exposedTerminationAnnotation] = string(route.Spec.TLS.Termination)
+	}
+	return exposure
+}
+
+// syncExposureAnnotations brings the exposure annotations on a Gateway in line with desired;
+// nil removes them. Other annotations are left untouched.
+func (r *GatewayReconciler) syncExposureAnnotations(ctx context.Context, gateway *gatewayv1.Gateway, desired map[string]string) error {
+	patch := client.MergeFrom(gateway.DeepCopy())
+	changed := false
+	for _, key := range exposureAnnotations {
+		current, has := gateway.Annotations[key]
+		want, wanted := desired[key]
+		switch {
+		case wanted && (!has || current != want):
+			if gateway.Annotations == nil {
+				gateway.Annotations = map[string]string{}
+			}
+			gateway.Annotations[key] = want
+			changed = true
+		case !wanted && has:
+			delete(gateway.Annotations, key)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Updating Gateway exposure annotations", "gateway", gateway.Name, "exposure", desired)
+	return r.Patch(ctx, gateway, patch)
+}
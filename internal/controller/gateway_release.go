@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// reconciledByAnnotation records the reconciler that claimed a Gateway and the class it claimed
+// it under, as {controller}/{class}. It outlives the Gateway's class being supported, so a Gateway
+// whose class is dropped from the configuration can still be recognized and released.
+const reconciledByAnnotation = "tinylb.io/reconciled-by"
+
+// gatewayClaim returns the tinylb.io/reconciled-by value the named reconciler claims a Gateway with
+func gatewayClaim(name string, gateway *gatewayv1.Gateway) string {
+	return name + "/" + string(gateway.Spec.GatewayClassName)
+}
+
+// claimedBy reports whether a Gateway carries a claim of the named reconciler, under any class.
+// A bare name is a claim written before claims recorded the class.
+func claimedBy(obj client.Object, name string) bool {
+	claim := obj.GetAnnotations()[reconciledByAnnotation]
+	return claim == name || strings.HasPrefix(claim, name+"/")
+}
+
+// reconciledByFilter admits Gateways claimed by the named reconciler, so Gateways whose class is
+// no longer supported still reach it to be released
+func reconciledByFilter(name string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return claimedBy(obj, name)
+	})
+}
+
+// claimGateway marks a supported Gateway as reconciled by this reconciler under its current class
+func (r *GatewayReconciler) claimGateway(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	claim := gatewayClaim(r.name(), gateway)
+	if gateway.Annotations[reconciledByAnnotation] == claim {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(gateway.DeepCopy())
+	metav1.SetMetaDataAnnotation(&gateway.ObjectMeta, reconciledByAnnotation, claim)
+	return r.Patch(ctx, gateway, patch)
+}
+
+// releaseGateway undoes what TinyLB did to a Gateway it claimed before its class stopped being
+// supported: the listener Routes are deleted, TinyLB's conditions and the addresses are removed,
+// and the claim is dropped so the next controller starts from a clean Gateway. Gateways claimed by
+// another reconciler are left alone.
+func (r *GatewayReconciler) releaseGateway(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	if !claimedBy(gateway, r.name()) {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	// Listener Routes; the service's own Route belongs to the service controller
+	routeNamespace := gateway.Namespace
+	if r.RouteNamespace != "" {
+		routeNamespace = r.RouteNamespace
+	}
+	routes, err := r.listenerRoutesOf(ctx, routeNamespace, gateway.Namespace, client.MatchingLabels{
+		managedLabel: "true",
+		serviceLabel: GatewayServiceName(gateway),
+		gatewayLabel: gateway.Name,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range routes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Delete(ctx, &routes[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Deleted listener Route of released Gateway", "route", routes[i].Name)
+	}
+
+	if err := r.deleteMirroredSecrets(ctx, client.ObjectKeyFromObject(gateway)); err != nil {
+		return err
+	}
+	if r.UseServiceCA {
+		if err := r.releaseServiceCA(ctx, gateway); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	gateway.Status.Conditions = slices.DeleteFunc(gateway.Status.Conditions, func(condition metav1.Condition) bool {
+		domain, _, _ := strings.Cut(condition.Type, "/")
+		return condition.Type == string(gatewayv1.GatewayConditionAccepted) ||
+			condition.Type == string(gatewayv1.GatewayConditionProgrammed) ||
+			domain == r.domainPrefix()
+	})
+	gateway.Status.Addresses = []gatewayv1.GatewayStatusAddress{}
+	if r.ReportListenerStatus {
+		gateway.Status.Listeners = nil
+	}
+	if err := r.Status().Update(ctx, gateway); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(gateway.DeepCopy())
+	delete(gateway.Annotations, reconciledByAnnotation)
+	delete(gateway.Annotations, pendingSinceAnnotation)
+	if err := r.Patch(ctx, gateway, patch); err != nil {
+		return err
+	}
+
+	logger.Info("Released Gateway whose class is no longer supported", "gateway", gateway.Name, "gatewayClassName", gateway.Spec.GatewayClassName)
+	r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "GatewayReleased",
+		"Gateway class %s is no longer handled by TinyLB; removed its status and %d listener Route(s)", gateway.Spec.GatewayClassName, len(routes))
+	return nil
+}
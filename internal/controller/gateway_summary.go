@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The Gateway summary is a ConfigMap listing every programmed Gateway with its addresses, one
+// key per Gateway ({namespace}.{name}, as ConfigMap keys cannot hold a slash) and the addresses
+// comma-separated. It gives dashboards that cannot watch Gateways a single object to poll.
+
+// ParseGatewaySummary parses a --gateway-summary value of the form namespace/name
+func ParseGatewaySummary(value string) (types.NamespacedName, error) {
+	return parseConfigMapKey("Gateway summary", value)
+}
+
+// gatewaySummaryKey returns the summary key of a Gateway; namespaces cannot contain dots, so the
+// first dot separates the namespace from the name
+func gatewaySummaryKey(gateway types.NamespacedName) string {
+	return gateway.Namespace + "." + gateway.Name
+}
+
+// programmedAddresses returns the addresses of a Gateway reported as programmed, and nil for
+// Gateways that are not
+func programmedAddresses(gateway *gatewayv1.Gateway) []string {
+	if !meta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed)) {
+		return nil
+	}
+	addresses := []string{}
+	for _, address := range gateway.Status.Addresses {
+		addresses = append(addresses, address.Value)
+	}
+	return addresses
+}
+
+// updateGatewaySummary records the addresses of a programmed Gateway in the summary ConfigMap, or
+// removes the Gateway when addresses is nil. Reconcilers of several classes share the ConfigMap,
+// so concurrent writers are handled by retrying on update conflicts.
+func (r *GatewayReconciler) updateGatewaySummary(ctx context.Context, gateway types.NamespacedName, addresses []string) error {
+	if r.GatewaySummary.Name == "" {
+		return nil
+	}
+	key, value := gatewaySummaryKey(gateway), strings.Join(addresses, ",")
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var summary corev1.ConfigMap
+		if err := getManagedConfigMap(ctx, r.Client, r.APIReader, r.GatewaySummary, &summary); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			if addresses == nil {
+				return nil
+			}
+			summary = corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      r.GatewaySummary.Name,
+					Namespace: r.GatewaySummary.Namespace,
+					Labels:    map[string]string{managedLabel: "true"},
+				},
+				Data: map[string]string{key: value},
+			}
+			log.FromContext(ctx).Info("Creating Gateway summary", "configMap", r.GatewaySummary.String())
+			if err := r.Create(ctx, &summary); err != nil {
+				if errors.IsAlreadyExists(err) {
+					// Another writer created it first; retry as an update
+					return errors.NewConflict(corev1.Resource("configmaps"), summary.Name, err)
+				}
+				return err
+			}
+			return nil
+		}
+
+		current, listed := summary.Data[key]
+		if addresses == nil {
+			if !listed {
+				return nil
+			}
+			delete(summary.Data, key)
+		} else {
+			if listed && current == value {
+				return nil
+			}
+			if summary.Data == nil {
+				summary.Data = map[string]string{}
+			}
+			summary.Data[key] = value
+		}
+		log.FromContext(ctx).Info("Updating Gateway summary", "gateway", gateway.String(), "addresses", addresses)
+		return r.Update(ctx, &summary)
+	})
+}
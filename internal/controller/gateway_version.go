@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// Gateway API versions TinyLB can reconcile Gateways through
+const (
+	GatewayAPIVersionV1      = "v1"
+	GatewayAPIVersionV1beta1 = "v1beta1"
+)
+
+// The v1beta1 Gateway is declared as the v1 type and shares its spec and status types, so the
+// versions convert losslessly. The reconciler always works on v1 Gateways; on clusters that only
+// serve v1beta1, NewGatewayV1beta1Client translates them at the API boundary.
+
+// asGateway returns obj as a v1 Gateway, converting a v1beta1 Gateway
+func asGateway(obj client.Object) (*gatewayv1.Gateway, bool) {
+	switch gateway := obj.(type) {
+	case *gatewayv1.Gateway:
+		return gateway, true
+	case *gatewayv1beta1.Gateway:
+		return (*gatewayv1.Gateway)(gateway), true
+	}
+	return nil, false
+}
+
+// gatewayObject returns an empty Gateway of the API version the reconciler watches
+func (r *GatewayReconciler) gatewayObject() client.Object {
+	if r.GatewayAPIVersion == GatewayAPIVersionV1beta1 {
+		return &gatewayv1beta1.Gateway{}
+	}
+	return &gatewayv1.Gateway{}
+}
+
+// gatewayClassObject returns an empty GatewayClass of the API version the reconciler watches
+func (r *GatewayReconciler) gatewayClassObject() client.Object {
+	if r.GatewayAPIVersion == GatewayAPIVersionV1beta1 {
+		return &gatewayv1beta1.GatewayClass{}
+	}
+	return &gatewayv1.GatewayClass{}
+}
+
+// NewGatewayV1beta1Client returns a client that reads and writes v1 Gateways and GatewayClasses
+// through the gateway.networking.k8s.io/v1beta1 API. All other objects pass through unchanged.
+func NewGatewayV1beta1Client(c client.Client) client.Client {
+	return &gatewayV1beta1Client{Client: c}
+}
+
+type gatewayV1beta1Client struct {
+	client.Client
+}
+
+// toV1beta1 converts v1 Gateways and GatewayClasses to their v1beta1 form and passes anything
+// else through
+func toV1beta1(obj client.Object) client.Object {
+	switch obj := obj.(type) {
+	case *gatewayv1.Gateway:
+		return (*gatewayv1beta1.Gateway)(obj)
+	case *gatewayv1.GatewayClass:
+		return (*gatewayv1beta1.GatewayClass)(obj)
+	}
+	return obj
+}
+
+func (c *gatewayV1beta1Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return c.Client.Get(ctx, key, toV1beta1(obj), opts...)
+}
+
+func (c *gatewayV1beta1Client) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gateways, ok := list.(*gatewayv1.GatewayList)
+	if !ok {
+		return c.Client.List(ctx, list, opts...)
+	}
+	var beta gatewayv1beta1.GatewayList
+	if err := c.Client.List(ctx, &beta, opts...); err != nil {
+		return err
+	}
+	gateways.ListMeta = beta.ListMeta
+	gateways.Items = make([]gatewayv1.Gateway, len(beta.Items))
+	for i := range beta.Items {
+		gateways.Items[i] = gatewayv1.Gateway(beta.Items[i])
+	}
+	return nil
+}
+
+func (c *gatewayV1beta1Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.Client.Create(ctx, toV1beta1(obj), opts...)
+}
+
+func (c *gatewayV1beta1Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.Client.Update(ctx, toV1beta1(obj), opts...)
+}
+
+func (c *gatewayV1beta1Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.Client.Patch(ctx, toV1beta1(obj), patch, opts...)
+}
+
+func (c *gatewayV1beta1Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.Client.Delete(ctx, toV1beta1(obj), opts...)
+}
+
+func (c *gatewayV1beta1Client) Status() client.SubResourceWriter {
+	return &gatewayV1beta1StatusWriter{SubResourceWriter: c.Client.Status()}
+}
+
+type gatewayV1beta1StatusWriter struct {
+	client.SubResourceWriter
+}
+
+func (w *gatewayV1beta1StatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.SubResourceWriter.Update(ctx, toV1beta1(obj), opts...)
+}
+
+func (w *gatewayV1beta1StatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return w.SubResourceWriter.Patch(ctx, toV1beta1(obj), patch, opts...)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// hostClaims records which service each Route host was handed to. Route lookups go through the
+// informer cache, which does not show a Route created moments ago by a concurrent reconcile, so
+// new hosts are claimed here before their Route is created.
+type hostClaims struct {
+	mu     sync.Mutex
+	owners map[string]types.NamespacedName
+	hosts  map[types.NamespacedName]string
+}
+
+// claim hands host to service, releasing the host it held before. It fails, returning the current
+// owner, when another service holds host.
+func (c *hostClaims) claim(service types.NamespacedName, host string) (types.NamespacedName, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if owner, ok := c.owners[host]; ok && owner != service {
+		return owner, false
+	}
+	if c.owners == nil {
+		c.owners = map[string]types.NamespacedName{}
+		c.hosts = map[types.NamespacedName]string{}
+	}
+	if previous, ok := c.hosts[service]; ok && previous != host {
+		delete(c.owners, previous)
+	}
+	c.owners[host] = service
+	c.hosts[service] = host
+	return service, true
+}
+
+// release frees the host held by a deleted service
+func (c *hostClaims) release(service types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if host, ok := c.hosts[service]; ok {
+		delete(c.owners, host)
+		delete(c.hosts, service)
+	}
+}
+
+// disambiguateHost derives a host for service distinct from a generated host it collided on, by
+// appending a hash of the service's namespace and name to the first DNS label
+func disambiguateHost(host string, service types.NamespacedName) string {
+	label, rest, _ := strings.Cut(host, ".")
+	return hostLabel(label, fmt.Sprintf("-%08x", hashString(service.String()))) + "." + rest
+}
+
+// claimHost claims the host of a service about to get its Route. A generated host another service
+// holds (service a-b in namespace c and service a in namespace b-c both get a-b-c) is replaced by
+// a disambiguated one; a fixed host, explicit or registered, fails with ErrHostConflict.
+func (r *ServiceReconciler) claimHost(service types.NamespacedName, host string, fixed bool) (string, error) {
+	owner, ok := r.hostClaims.claim(service, host)
+	if ok {
+		return host, nil
+	}
+	if !fixed {
+		distinct := disambiguateHost(host, service)
+		if owner, ok = r.hostClaims.claim(service, distinct); ok {
+			return distinct, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s is being assigned to service %s", ErrHostConflict, host, owner)
+}
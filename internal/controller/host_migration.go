@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// hostMigrationLabel marks the temporary Route serving a service's new host while its Route
+	// still serves the old one
+	hostMigrationLabel = "tinylb.io/host-migration"
+
+	// hostMigrationPollInterval is how often a migration waiting for admission is checked, on top
+	// of the Route status updates that already trigger reconciles
+	hostMigrationPollInterval = 10 * time.Second
+)
+
+// migrationRoute returns the temporary Route of a host migration in progress for service, or nil.
+// It is found by label rather than name: any fixed name could belong to another service's or
+// listener's Route.
+func (r *ServiceReconciler) migrationRoute(ctx context.Context, service *corev1.Service) (*routev1.Route, error) {
+	var routes routev1.RouteList
+	if err := r.List(ctx, &routes, client.InNamespace(service.Namespace), client.MatchingLabels{
+		hostMigrationLabel: "true",
+		serviceUIDLabel:    string(service.UID),
+	}); err != nil {
+		return nil, err
+	}
+	if len(routes.Items) == 0 {
+		return nil, nil
+	}
+	return &routes.Items[0], nil
+}
+
+// isMigrationRoute reports whether route is the temporary Route of a host migration
+func isMigrationRoute(route *routev1.Route) bool {
+	return route.Labels[hostMigrationLabel] == "true"
+}
+
+// routeAdmittedFor reports whether a router admitted route for host and, when one rejected it
+// instead, the router's reason and message. Admissions for any other host are stale: they date
+// from before the Route's host last changed.
+func routeAdmittedFor(route *routev1.Route, host string) (bool, string) {
+	current := *route
+	current.Status.Ingress = slices.DeleteFunc(slices.Clone(route.Status.Ingress), func(ingress routev1.RouteIngress) bool {
+		return ingress.Host != host
+	})
+	return routeAdmission(&current)
+}
+
+// migrateHost moves a service's Route from its current host to host without a gap in service:
+//
+//  1. a temporary Route serving host is created next to the current Route;
+//  2. once a router admits it, the service's status advertises host instead of the old host;
+//  3. the current Route is moved to host, taking it over from the newer temporary Route;
+//  4. once a router admits the current Route on host, settleHostMigration deletes the
+//     temporary Route.
+//
+// Each step is picked up again on the next reconcile, so an interrupted migration resumes; the
+// result says when to check again.
+func (r *ServiceReconciler) migrateHost(ctx context.Context, service *corev1.Service, current *routev1.Route, host string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	oldHost := current.Spec.Host
+
+	migration, err := r.migrationRoute(ctx, service)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if migration == nil {
+		migration = &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: current.Name + "-migration-",
+				Namespace:    current.Namespace,
+				Labels:       maps.Clone(current.Labels),
+				Annotations:  maps.Clone(current.Annotations),
+			},
+			Spec: *current.Spec.DeepCopy(),
+		}
+		if migration.Labels == nil {
+			migration.Labels = map[string]string{}
+		}
+		migration.Labels[hostMigrationLabel] = "true"
+		migration.Labels[serviceUIDLabel] = string(service.UID)
+		migration.Spec.Host = host
+		if err := controllerutil.SetOwnerReference(service, migration, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := ctx.Err(); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, migration); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Started host migration", "service", service.Name, "route", migration.Name, "from", oldHost, "to", host)
+		r.Recorder.Eventf(service, corev1.EventTypeNormal, "HostMigrationStarted",
+			"Serving %s from Route %s until a router admits it, then moving off %s", host, migration.Name, oldHost)
+		return ctrl.Result{RequeueAfter: hostMigrationPollInterval}, nil
+	}
+
+	// The target moved again mid-migration
+	if migration.Spec.Host != host {
+		if err := ctx.Err(); err != nil {
+			return ctrl.Result{}, err
+		}
+		patch := client.MergeFrom(migration.DeepCopy())
+		migration.Spec.Host = host
+		if err := r.Patch(ctx, migration, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: hostMigrationPollInterval}, nil
+	}
+
+	if admitted, rejection := routeAdmittedFor(migration, host); !admitted {
+		if rejection != "" {
+			r.Recorder.Eventf(service, corev1.EventTypeWarning, "HostMigrationBlocked",
+				"Route %s for new host %s was rejected, still serving %s: %s", migration.Name, host, oldHost, rejection)
+		}
+		logger.Info("Waiting for the new host to be admitted", "service", service.Name, "route", migration.Name, "host", host)
+		return ctrl.Result{RequeueAfter: hostMigrationPollInterval}, nil
+	}
+
+	// Clients are sent to the new host before the old one stops being served
+	ingress := slices.DeleteFunc(slices.Clone(service.Status.LoadBalancer.Ingress), func(entry corev1.LoadBalancerIngress) bool {
+		return entry.Hostname == oldHost
+	})
+	ingress = mergeIngress(ingress, host, r.ingressMergeStrategy(service))
+	if !equality.Semantic.DeepEqual(ingress, service.Status.LoadBalancer.Ingress) {
+		if err := ctx.Err(); err != nil {
+			return ctrl.Result{}, err
+		}
+		service.Status.LoadBalancer.Ingress = ingress
+		if err := r.Status().Update(ctx, service); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The temporary Route keeps serving host until the router has re-admitted the current Route on it
+	if err := ctx.Err(); err != nil {
+		return ctrl.Result{}, err
+	}
+	patch := client.MergeFrom(current.DeepCopy())
+	current.Spec.Host = host
+	if err := r.Patch(ctx, current, patch); err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Moved Route to the new host, waiting for admission", "service", service.Name, "route", current.Name, "from", oldHost, "to", host)
+	return ctrl.Result{RequeueAfter: hostMigrationPollInterval}, nil
+}
+
+// settleHostMigration deals with the temporary Route of a migration once the service's Route
+// serves the host it should. If the temporary Route serves that host too, the migration is
+// completing: the temporary Route is deleted once a router admits the service's Route on the host,
+// and done reports whether that happened. Otherwise the host no longer needs to change, e.g. the
+// configuration was reverted mid-migration, and the temporary Route is deleted right away.
+func (r *ServiceReconciler) settleHostMigration(ctx context.Context, service *corev1.Service, current *routev1.Route) (result ctrl.Result, done bool, err error) {
+	logger := log.FromContext(ctx)
+	migration, err := r.migrationRoute(ctx, service)
+	if err != nil || migration == nil {
+		return ctrl.Result{}, err == nil, err
+	}
+
+	completing := migration.Spec.Host == current.Spec.Host
+	if completing {
+		if admitted, _ := routeAdmittedFor(current, current.Spec.Host); !admitted {
+			logger.Info("Waiting for the Route to be admitted on its new host", "service", service.Name, "route", current.Name, "host", current.Spec.Host)
+			return ctrl.Result{RequeueAfter: hostMigrationPollInterval}, false, nil
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return ctrl.Result{}, false, err
+	}
+	if err := r.Delete(ctx, migration); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, false, err
+	}
+	if completing {
+		logger.Info("Completed host migration", "service", service.Name, "route", current.Name, "host", current.Spec.Host)
+		r.Recorder.Eventf(service, corev1.EventTypeNormal, "HostMigrated", "Route %s moved to %s", current.Name, current.Spec.Host)
+	} else {
+		logger.Info("Abandoning host migration", "service", service.Name, "route", migration.Name, "host", migration.Spec.Host)
+	}
+	return ctrl.Result{}, true, nil
+}
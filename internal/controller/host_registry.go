@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The host registry is a ConfigMap mapping service UIDs to the Route host TinyLB assigned them.
+// It keeps assignments stable across restarts even if the service's annotations are lost, and
+// gives external tooling one place to inspect them.
+
+// ParseHostRegistry parses a --host-registry value of the form namespace/name
+func ParseHostRegistry(value string) (types.NamespacedName, error) {
+	return parseConfigMapKey("host registry", value)
+}
+
+// parseConfigMapKey parses a namespace/name ConfigMap flag value; empty values disable the feature
+func parseConfigMapKey(what, value string) (types.NamespacedName, error) {
+	if value == "" {
+		return types.NamespacedName{}, nil
+	}
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid %s %q: expected namespace/name", what, value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// registeredHost returns the host recorded for service in the registry, if any
+func (r *ServiceReconciler) registeredHost(ctx context.Context, service *corev1.Service) (string, error) {
+	if r.HostRegistry.Name == "" {
+		return "", nil
+	}
+	var registry corev1.ConfigMap
+	if err := getManagedConfigMap(ctx, r.Client, r.APIReader, r.HostRegistry, &registry); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return registry.Data[string(service.UID)], nil
+}
+
+// registerHost records host for service in the registry, creating the ConfigMap on first use
+// Concurrent writers are handled by retrying on update conflicts
+func (r *ServiceReconciler) registerHost(ctx context.Context, service *corev1.Service, host string) error {
+	if r.HostRegistry.Name == "" {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var registry corev1.ConfigMap
+		if err := getManagedConfigMap(ctx, r.Client, r.APIReader, r.HostRegistry, &registry); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			registry.Name = r.HostRegistry.Name
+			registry.Namespace = r.HostRegistry.Namespace
+			registry.Labels = map[string]string{managedLabel: "true"}
+			registry.Data = map[string]string{string(service.UID): host}
+			log.FromContext(ctx).Info("Creating host registry", "configMap", r.HostRegistry.String())
+			if err := r.Create(ctx, &registry); err != nil {
+				if errors.IsAlreadyExists(err) {
+					// Another writer created it first; retry as an update
+					return errors.NewConflict(corev1.Resource("configmaps"), registry.Name, err)
+				}
+				return err
+			}
+			return nil
+		}
+
+		if registry.Data[string(service.UID)] == host {
+			return nil
+		}
+		if registry.Data == nil {
+			registry.Data = map[string]string{}
+		}
+		registry.Data[string(service.UID)] = host
+		log.FromContext(ctx).Info("Recording host in registry", "service", service.Name, "host", host)
+		return r.Update(ctx, &registry)
+	})
+}
+
+// unregisterHost drops the registry entry of a service that no longer gets a Route, reporting
+// whether there was one
+func (r *ServiceReconciler) unregisterHost(ctx context.Context, service *corev1.Service) (bool, error) {
+	if r.HostRegistry.Name == "" {
+		return false, nil
+	}
+	removed := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var registry corev1.ConfigMap
+		if err := getManagedConfigMap(ctx, r.Client, r.APIReader, r.HostRegistry, &registry); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if _, ok := registry.Data[string(service.UID)]; !ok {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		delete(registry.Data, string(service.UID))
+		log.FromContext(ctx).Info("Removing host from registry", "service", service.Name)
+		if err := r.Update(ctx, &registry); err != nil {
+			return err
+		}
+		removed = true
+		return nil
+	})
+	return removed, err
+}
+
+// pruneHostRegistry drops the entries of services that no longer exist, so the registry does not
+// grow with every service ever exposed. A deleted service no longer reveals its UID, so the
+// registry is compared against the services that are still around.
+func (r *ServiceReconciler) pruneHostRegistry(ctx context.Context) error {
+	if r.HostRegistry.Name == "" {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var registry corev1.ConfigMap
+		if err := getManagedConfigMap(ctx, r.Client, r.APIReader, r.HostRegistry, &registry); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if len(registry.Data) == 0 {
+			return nil
+		}
+
+		var services corev1.ServiceList
+		if err := r.List(ctx, &services); err != nil {
+			return err
+		}
+		live := map[string]bool{}
+		for _, service := range services.Items {
+			live[string(service.UID)] = true
+		}
+		pruned := 0
+		for uid := range registry.Data {
+			if !live[uid] {
+				delete(registry.Data, uid)
+				pruned++
+			}
+		}
+		if pruned == 0 {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Pruning host registry entries of deleted services", "entries", pruned)
+		return r.Update(ctx, &registry)
+	})
+}
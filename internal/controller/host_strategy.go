@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// hostStrategyAnnotation selects the host strategy of a single service, overriding --host-strategy
+const hostStrategyAnnotation = "tinylb.io/host-strategy"
+
+// Names of the built-in host strategies, as accepted by --host-strategy and tinylb.io/host-strategy
+const (
+	// HostStrategyTemplate generates {service}-{namespace}{suffix}.{baseDomain} (the default)
+	HostStrategyTemplate = "template"
+	// HostStrategyAnnotation uses the external-dns hostname annotation, falling back to the template
+	HostStrategyAnnotation = "annotation"
+	// HostStrategySubdomain generates {service}{suffix}.{namespace}.{baseDomain}, giving each
+	// namespace its own subdomain (which needs a matching wildcard DNS record)
+	HostStrategySubdomain = "subdomain"
+)
+
+// HostStrategy generates the Route host of a service
+type HostStrategy interface {
+	Host(service *corev1.Service) (string, error)
+}
+
+// TemplateHostStrategy generates hosts from the service name and namespace under a base domain
+type TemplateHostStrategy struct {
+	BaseDomain string
+	Suffix     string // appended to the first DNS label, e.g. "-dev"
+}
+
+// Host implements HostStrategy
+func (s TemplateHostStrategy) Host(service *corev1.Service) (string, error) {
+	return routeHostLabel(service, s.Suffix) + "." + s.BaseDomain, nil
+}
+
+// AnnotationHostStrategy uses the host a service asks for through the external-dns hostname
+// annotation, and the Fallback strategy for services without one
+type AnnotationHostStrategy struct {
+	Fallback HostStrategy
+}
+
+// Host implements HostStrategy
+func (s AnnotationHostStrategy) Host(service *corev1.Service) (string, error) {
+	if annotated := externalDNSHostname(service); annotated != "" {
+		return annotated, nil
+	}
+	return s.Fallback.Host(service)
+}
+
+// SubdomainHostStrategy generates hosts under a per-namespace subdomain of a base domain
+type SubdomainHostStrategy struct {
+	BaseDomain string
+	Suffix     string // appended to the service label, e.g. "-dev"
+}
+
+// Host implements HostStrategy
+func (s SubdomainHostStrategy) Host(service *corev1.Service) (string, error) {
+	return fmt.Sprintf("%s.%s.%s", hostLabel(service.Name, s.Suffix), service.Namespace, s.BaseDomain), nil
+}
+
+// ValidateHostStrategy checks a --host-strategy or tinylb.io/host-strategy value
+func ValidateHostStrategy(name string) error {
+	switch name {
+	case HostStrategyTemplate, HostStrategyAnnotation, HostStrategySubdomain:
+		return nil
+	}
+	return fmt.Errorf("unknown host strategy %q (expected template, annotation or subdomain)", name)
+}
+
+// hostStrategyName returns the host strategy selected for a service: its tinylb.io/host-strategy
+// annotation, else --host-strategy, where --honor-external-dns-hostname turns the template into
+// the annotation strategy. Services can only opt into explicit hosts when the operator allows them.
+func (r *ServiceReconciler) hostStrategyName(service *corev1.Service) string {
+	name := cmp.Or(r.DefaultHostStrategy, HostStrategyTemplate)
+	if name == HostStrategyTemplate && r.HonorExternalDNSHostname {
+		name = HostStrategyAnnotation
+	}
+
+	value, ok := service.Annotations[hostStrategyAnnotation]
+	if !ok {
+		r.warnings.resolve(service, hostStrategyAnnotation)
+		return name
+	}
+	if err := ValidateHostStrategy(value); err != nil {
+		r.warnings.warnf(r.Recorder, service, hostStrategyAnnotation, "InvalidAnnotation",
+			"Ignoring %s annotation: %v", hostStrategyAnnotation, err)
+		return name
+	}
+	if value == HostStrategyAnnotation && name != HostStrategyAnnotation && !r.HonorExternalDNSHostname {
+		r.warnings.warnf(r.Recorder, service, hostStrategyAnnotation, "InvalidAnnotation",
+			"Ignoring %s annotation: explicit hosts are not enabled", hostStrategyAnnotation)
+		return name
+	}
+	r.warnings.resolve(service, hostStrategyAnnotation)
+	return value
+}
+
+// hostStrategy returns the strategy generating a service's host under baseDomain
+func (r *ServiceReconciler) hostStrategy(service *corev1.Service, baseDomain string) HostStrategy {
+	template := TemplateHostStrategy{BaseDomain: baseDomain, Suffix: r.HostSuffix}
+	switch r.hostStrategyName(service) {
+	case HostStrategyAnnotation:
+		return AnnotationHostStrategy{Fallback: template}
+	case HostStrategySubdomain:
+		return SubdomainHostStrategy{BaseDomain: baseDomain, Suffix: r.HostSuffix}
+	}
+	return template
+}
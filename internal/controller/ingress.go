@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IngressMergeStrategy controls how TinyLB's Route host is combined with ingress entries
+// another LoadBalancer provider has already written to a Service's status
+type IngressMergeStrategy string
+
+const (
+	// IngressMergeReplace overwrites the ingress list with TinyLB's hostname
+	IngressMergeReplace IngressMergeStrategy = "replace"
+	// IngressMergeAppend keeps existing entries and adds TinyLB's hostname after them
+	IngressMergeAppend IngressMergeStrategy = "append"
+	// IngressMergeSkipIfPresent leaves services that already have foreign ingress entries untouched
+	// (the default, so services another provider serves are never taken over unasked)
+	IngressMergeSkipIfPresent IngressMergeStrategy = "skip-if-present"
+)
+
+// ParseIngressMergeStrategy validates an --ingress-merge-strategy value
+func ParseIngressMergeStrategy(value string) (IngressMergeStrategy, error) {
+	strategy := IngressMergeStrategy(value)
+	switch strategy {
+	case IngressMergeReplace, IngressMergeAppend, IngressMergeSkipIfPresent:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown ingress merge strategy %q (expected replace, append or skip-if-present)", value)
+}
+
+// ingressMergeStrategy returns the strategy for service: the configured one, defaulting to
+// IngressMergeSkipIfPresent. Services annotated tinylb.io/force are taken over (replace) instead
+// of skipped, as they are when preserving node IPs.
+func (r *ServiceReconciler) ingressMergeStrategy(service *corev1.Service) IngressMergeStrategy {
+	strategy := cmp.Or(r.IngressMergeStrategy, IngressMergeSkipIfPresent)
+	if strategy == IngressMergeSkipIfPresent && service.Annotations[forceAnnotation] == "true" {
+		return IngressMergeReplace
+	}
+	return strategy
+}
+
+// hasForeignIngress reports whether any ingress entry was written by someone other than TinyLB
+func hasForeignIngress(ingress []corev1.LoadBalancerIngress, hostname string) bool {
+	return slices.ContainsFunc(ingress, func(entry corev1.LoadBalancerIngress) bool {
+		return entry.Hostname != hostname
+	})
+}
+
+// ingressTakenOver reports whether another provider has replaced TinyLB's hostname with its own
+// ingress entries, i.e. the list is non-empty and no longer advertises hostname
+func ingressTakenOver(ingress []corev1.LoadBalancerIngress, hostname string) bool {
+	return len(ingress) > 0 && !slices.ContainsFunc(ingress, func(entry corev1.LoadBalancerIngress) bool {
+		return entry.Hostname == hostname
+	})
+}
+
+// mergeIngress returns the ingress list to advertise for hostname under strategy
+func mergeIngress(existing []corev1.LoadBalancerIngress, hostname string, strategy IngressMergeStrategy) []corev1.LoadBalancerIngress {
+	ours := corev1.LoadBalancerIngress{Hostname: hostname}
+
+	switch strategy {
+	case IngressMergeAppend:
+		merged := slices.DeleteFunc(slices.Clone(existing), func(entry corev1.LoadBalancerIngress) bool {
+			return entry.Hostname == hostname
+		})
+		return append(merged, ours)
+	case IngressMergeSkipIfPresent:
+		if hasForeignIngress(existing, hostname) {
+			return existing
+		}
+	}
+	return []corev1.LoadBalancerIngress{ours}
+}
+
+// hasNodeIngress reports whether any ingress entry advertises one of nodeIPs, as bare-metal
+// setups do when the service is reached directly on its nodes
+func hasNodeIngress(ingress []corev1.LoadBalancerIngress, nodeIPs map[string]bool) bool {
+	return slices.ContainsFunc(ingress, func(entry corev1.LoadBalancerIngress) bool {
+		return entry.IP != "" && nodeIPs[entry.IP]
+	})
+}
+
+// nodeIPs returns the internal and external addresses of the cluster's nodes
+func (r *ServiceReconciler) nodeIPs(ctx context.Context) (map[string]bool, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		return nil, err
+	}
+	ips := map[string]bool{}
+	for _, node := range nodes.Items {
+		for _, address := range node.Status.Addresses {
+			if address.Type == corev1.NodeInternalIP || address.Type == corev1.NodeExternalIP {
+				ips[address.Address] = true
+			}
+		}
+	}
+	return ips, nil
+}
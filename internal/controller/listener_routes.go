@@ -0,0 +1,502 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// destinationCAAnnotation names a ConfigMap in the Gateway's namespace holding the CA that
+	// reencrypt Routes use to verify the Gateway's serving certificate
+	destinationCAAnnotation = "tinylb.io/destination-ca"
+
+	// destinationCAKey is the ConfigMap key holding the PEM encoded CA bundle
+	destinationCAKey = "ca.crt"
+
+	// serviceCAConfigMapName is the ConfigMap TinyLB creates in a Gateway's namespace with
+	// --use-service-ca, for OpenShift's service CA operator to inject its CA bundle into
+	serviceCAConfigMapName = "tinylb-service-ca"
+
+	// serviceCAInjectAnnotation asks the service CA operator to inject its CA bundle into a ConfigMap
+	serviceCAInjectAnnotation = "service.beta.openshift.io/inject-cabundle"
+
+	// serviceCAKey is the ConfigMap key the service CA operator injects the CA bundle under
+	serviceCAKey = "service-ca.crt"
+
+	// exposeListenersAnnotation limits the Gateway listeners TinyLB exposes to a comma-separated
+	// list of listener names; without it every listener is exposed
+	exposeListenersAnnotation = "tinylb.io/expose-listeners"
+)
+
+// listenerExposed reports whether the Gateway's tinylb.io/expose-listeners annotation, if any, lists listener
+func listenerExposed(gateway *gatewayv1.Gateway, listener gatewayv1.SectionName) bool {
+	value, ok := gateway.Annotations[exposeListenersAnnotation]
+	if !ok {
+		return true
+	}
+	for _, name := range strings.Split(value, ",") {
+		if strings.TrimSpace(name) == string(listener) {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownExposedListeners returns the names in the Gateway's tinylb.io/expose-listeners
+// annotation that match none of its listeners
+func unknownExposedListeners(gateway *gatewayv1.Gateway) []string {
+	var unknown []string
+	for _, name := range strings.Split(gateway.Annotations[exposeListenersAnnotation], ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.ContainsFunc(gateway.Spec.Listeners, func(listener gatewayv1.Listener) bool {
+			return string(listener.Name) == name
+		}) {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// listenerTermination maps a listener's TLS mode to the Route TLS termination fronting it
+// Terminate listeners still expect TLS on their port, so the router re-encrypts after terminating
+// the client connection; Passthrough listeners get the client's TLS stream untouched. Listeners
+// without TLS settings are served by the service's passthrough Route and get no Route of their own
+func listenerTermination(listener gatewayv1.Listener) (routev1.TLSTerminationType, bool) {
+	if listener.TLS == nil {
+		return "", false
+	}
+	if listener.TLS.Mode != nil && *listener.TLS.Mode == gatewayv1.TLSModePassthrough {
+		return routev1.TLSTerminationPassthrough, true
+	}
+	return routev1.TLSTerminationReencrypt, true
+}
+
+// hasReencryptListener reports whether any listener of gateway is fronted by a reencrypt Route
+func hasReencryptListener(gateway *gatewayv1.Gateway) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if termination, ok := listenerTermination(listener); ok && termination == routev1.TLSTerminationReencrypt {
+			return true
+		}
+	}
+	return false
+}
+
+// listenerRouteAnnotations are the listener Route annotations TinyLB owns; others are left alone
+var listenerRouteAnnotations = []string{hstsRouteAnnotation, rewriteTargetRouteAnnotation, gatewayAnnotation, gatewayNamespaceAnnotation}
+
+// listenerRouteOptions are the Gateway-wide settings applied to its listener Routes
+type listenerRouteOptions struct {
+	destinationCA string // CA verifying the Gateway's serving certificate on reencrypt Routes
+	hsts          string // Strict-Transport-Security header for TLS-terminating Routes
+	rewriteTarget string // path requests on TLS-terminating Routes are rewritten to
+
+	certificates map[gatewayv1.SectionName]string // Secret in the Route namespace serving each listener's certificate
+}
+
+// listenerRouteHost returns the host of a listener's Route; listeners without a concrete hostname get none
+func listenerRouteHost(listener gatewayv1.Listener) (string, bool) {
+	if listener.Hostname == nil {
+		return "", false
+	}
+	host := string(*listener.Hostname)
+	if host == "" || strings.HasPrefix(host, "*") {
+		return "", false
+	}
+	return host, true
+}
+
+// desiredListenerRoutes builds the per-listener Routes for a Gateway's TLS listeners
+// Only listeners with a concrete hostname get a Route: the router admits a single Route per host,
+// so listeners without one (or with a wildcard) are left to the service's own Route
+func desiredListenerRoutes(gateway *gatewayv1.Gateway, service *corev1.Service, routeNamespace string, opts listenerRouteOptions) []routev1.Route {
+	var routes []routev1.Route
+	for _, listener := range gateway.Spec.Listeners {
+		termination, ok := listenerTermination(listener)
+		if !ok || !listenerExposed(gateway, listener.Name) {
+			continue
+		}
+		host, ok := listenerRouteHost(listener)
+		if !ok {
+			continue
+		}
+
+		tls := &routev1.TLSConfig{Termination: termination}
+		annotations := map[string]string{
+			gatewayAnnotation:          gateway.Name,
+			gatewayNamespaceAnnotation: gateway.Namespace,
+		}
+		if termination == routev1.TLSTerminationReencrypt {
+			tls.DestinationCACertificate = opts.destinationCA
+			if secret, ok := opts.certificates[listener.Name]; ok {
+				tls.ExternalCertificate = &routev1.LocalObjectReference{Name: secret}
+			}
+			// The router can only add headers to, or rewrite paths of, connections it terminates
+			if opts.hsts != "" {
+				annotations[hstsRouteAnnotation] = opts.hsts
+			}
+			if opts.rewriteTarget != "" {
+				annotations[rewriteTargetRouteAnnotation] = opts.rewriteTarget
+			}
+		}
+
+		// Follow the matching service port by name when it targets a named container port
+		targetPort := intstr.FromInt32(int32(listener.Port))
+		for _, port := range service.Spec.Ports {
+			if port.Port == int32(listener.Port) {
+				targetPort = routeTargetPort(port, false)
+				break
+			}
+		}
+
+		routes = append(routes, routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: annotations,
+				Name:        listenerRouteName(service.Name, listener.Name),
+				Namespace:   routeNamespace,
+				Labels: map[string]string{
+					managedLabel:  "true",
+					serviceLabel:  service.Name,
+					gatewayLabel:  gateway.Name,
+					listenerLabel: string(listener.Name),
+				},
+			},
+			Spec: routev1.RouteSpec{
+				Host: host,
+				To: routev1.RouteTargetReference{
+					Kind: "Service",
+					Name: service.Name,
+				},
+				Port: &routev1.RoutePort{
+					TargetPort: targetPort,
+				},
+				TLS: tls,
+			},
+		})
+	}
+	return routes
+}
+
+// reconcileListenerRoutes creates or updates a Route per TLS listener of a Gateway, with the
+// termination matching the listener's TLS mode, and deletes Routes for listeners that are gone
+func (r *GatewayReconciler) reconcileListenerRoutes(ctx context.Context, gateway *gatewayv1.Gateway, service *corev1.Service, routeNamespace string) error {
+	logger := log.FromContext(ctx)
+
+	destinationCA, err := r.destinationCA(ctx, gateway)
+	if err != nil {
+		return err
+	}
+	opts := listenerRouteOptions{destinationCA: destinationCA}
+	if value, ok := gateway.Annotations[hstsAnnotation]; ok {
+		if err := validateHSTS(value); err != nil {
+			r.warnings.warnf(r.Recorder, gateway, hstsAnnotation, "InvalidAnnotation", "Ignoring %s annotation: %v", hstsAnnotation, err)
+		} else {
+			r.warnings.resolve(gateway, hstsAnnotation)
+			opts.hsts = value
+		}
+	} else {
+		r.warnings.resolve(gateway, hstsAnnotation)
+	}
+	if value, ok := gateway.Annotations[rewriteTargetAnnotation]; ok {
+		if err := validateRewriteTarget(value); err != nil {
+			r.warnings.warnf(r.Recorder, gateway, rewriteTargetAnnotation, "InvalidAnnotation", "Ignoring %s annotation: %v", rewriteTargetAnnotation, err)
+		} else {
+			r.warnings.resolve(gateway, rewriteTargetAnnotation)
+			opts.rewriteTarget = value
+		}
+	} else {
+		r.warnings.resolve(gateway, rewriteTargetAnnotation)
+	}
+
+	if r.MirrorTLSSecrets {
+		if opts.certificates, err = r.listenerCertificates(ctx, gateway, service, routeNamespace); err != nil {
+			return err
+		}
+	}
+
+	if unknown := unknownExposedListeners(gateway); len(unknown) > 0 {
+		r.warnings.warnf(r.Recorder, gateway, exposeListenersAnnotation, "InvalidAnnotation",
+			"%s annotation names listeners the Gateway does not have: %s", exposeListenersAnnotation, strings.Join(unknown, ", "))
+	} else {
+		r.warnings.resolve(gateway, exposeListenersAnnotation)
+	}
+
+	desired := desiredListenerRoutes(gateway, service, routeNamespace, opts)
+	keep := make(map[string]bool, len(desired))
+	for i := range desired {
+		route := &desired[i]
+		keep[route.Name] = true
+
+		// Owner references cannot cross namespaces; Routes in a central namespace are cleaned up below instead
+		if routeNamespace == gateway.Namespace {
+			if err := controllerutil.SetOwnerReference(gateway, route, r.Scheme); err != nil {
+				return err
+			}
+		}
+
+		var existing routev1.Route
+		err := r.Get(ctx, types.NamespacedName{Name: route.Name, Namespace: route.Namespace}, &existing)
+		if errors.IsNotFound(err) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := r.Create(ctx, route); err != nil {
+				return err
+			}
+			logger.Info("Created listener Route", "route", route.Name, "host", route.Spec.Host, "termination", route.Spec.TLS.Termination)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		annotationsCurrent := !slices.ContainsFunc(listenerRouteAnnotations, func(key string) bool {
+			return existing.Annotations[key] != route.Annotations[key]
+		})
+		if equality.Semantic.DeepEqual(existing.Spec.Host, route.Spec.Host) &&
+			equality.Semantic.DeepEqual(existing.Spec.Port, route.Spec.Port) &&
+			equality.Semantic.DeepEqual(existing.Spec.TLS, route.Spec.TLS) && annotationsCurrent {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, key := range listenerRouteAnnotations {
+			if value, ok := route.Annotations[key]; ok {
+				if existing.Annotations == nil {
+					existing.Annotations = map[string]string{}
+				}
+				existing.Annotations[key] = value
+			} else {
+				delete(existing.Annotations, key)
+			}
+		}
+		existing.Spec.Host = route.Spec.Host
+		existing.Spec.Port = route.Spec.Port
+		existing.Spec.TLS = route.Spec.TLS
+		if err := r.Update(ctx, &existing); err != nil {
+			return err
+		}
+		logger.Info("Updated listener Route", "route", existing.Name, "host", existing.Spec.Host, "termination", existing.Spec.TLS.Termination)
+	}
+
+	// Remove Routes for listeners that were deleted or no longer qualify
+	routes, err := r.listenerRoutesOf(ctx, routeNamespace, gateway.Namespace, client.MatchingLabels{
+		managedLabel: "true",
+		serviceLabel: service.Name,
+		gatewayLabel: gateway.Name,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range routes {
+		route := &routes[i]
+		if keep[route.Name] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Delete(ctx, route); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Deleted stale listener Route", "route", route.Name, "listener", route.Labels[listenerLabel])
+	}
+	return nil
+}
+
+// listenerRoutesOf lists the listener Routes in routeNamespace matching labels that belong to a
+// Gateway of gatewayNamespace. In a central route namespace, Gateways of the same name in other
+// namespaces share the labels and are told apart by the tinylb.io/gateway-namespace annotation.
+func (r *GatewayReconciler) listenerRoutesOf(ctx context.Context, routeNamespace, gatewayNamespace string, labels client.MatchingLabels) ([]routev1.Route, error) {
+	var routes routev1.RouteList
+	if err := r.List(ctx, &routes, client.InNamespace(routeNamespace), labels); err != nil {
+		return nil, err
+	}
+	if routeNamespace == gatewayNamespace {
+		return routes.Items, nil
+	}
+	return slices.DeleteFunc(routes.Items, func(route routev1.Route) bool {
+		return route.Annotations[gatewayNamespaceAnnotation] != gatewayNamespace
+	}), nil
+}
+
+// deleteCentralListenerRoutes removes the listener Routes of a deleted Gateway from RouteNamespace
+// Owner references cannot reach across namespaces, so garbage collection leaves them behind
+func (r *GatewayReconciler) deleteCentralListenerRoutes(ctx context.Context, gateway types.NamespacedName) error {
+	if r.RouteNamespace == "" || r.RouteNamespace == gateway.Namespace {
+		return nil
+	}
+	routes, err := r.listenerRoutesOf(ctx, r.RouteNamespace, gateway.Namespace, client.MatchingLabels{
+		managedLabel: "true",
+		gatewayLabel: gateway.Name,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range routes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Delete(ctx, &routes[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		log.FromContext(ctx).Info("Deleted listener Route of deleted Gateway", "route", routes[i].Name, "namespace", r.RouteNamespace)
+	}
+	return nil
+}
+
+// destinationCA returns the CA bundle from the ConfigMap named by the Gateway's
+// tinylb.io/destination-ca annotation, or with UseServiceCA the OpenShift service CA. It is read
+// on every reconcile, so a rotated CA reaches the reencrypt Routes: changes to the service CA
+// ConfigMap re-enqueue the Gateway, while the user's ConfigMap is read uncached and polled.
+func (r *GatewayReconciler) destinationCA(ctx context.Context, gateway *gatewayv1.Gateway) (string, error) {
+	name := gateway.Annotations[destinationCAAnnotation]
+	if name == "" && r.UseServiceCA && hasReencryptListener(gateway) {
+		return r.serviceCA(ctx, gateway)
+	}
+	if r.UseServiceCA {
+		if err := r.releaseServiceCA(ctx, gateway); err != nil {
+			return "", err
+		}
+	}
+	if name == "" {
+		return "", nil
+	}
+	var configMap corev1.ConfigMap
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: name}, &configMap); err != nil {
+		if errors.IsNotFound(err) {
+			log.FromContext(ctx).Info("Destination CA ConfigMap not found, reencrypt Routes use the router default", "configMap", name)
+			return "", nil
+		}
+		return "", err
+	}
+	return configMap.Data[destinationCAKey], nil
+}
+
+// serviceCA returns the OpenShift service CA bundle, which verifies Gateways serving
+// service-serving certificates. The bundle is injected by the service CA operator into a
+// ConfigMap TinyLB creates on first use; until then reencrypt Routes use the router default.
+// Every Gateway using the ConfigMap owns it, so it is garbage collected with the last of them.
+func (r *GatewayReconciler) serviceCA(ctx context.Context, gateway *gatewayv1.Gateway) (string, error) {
+	var configMap corev1.ConfigMap
+	err := r.Get(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: serviceCAConfigMapName}, &configMap)
+	if errors.IsNotFound(err) {
+		configMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        serviceCAConfigMapName,
+				Namespace:   gateway.Namespace,
+				Labels:      map[string]string{managedLabel: "true"},
+				Annotations: map[string]string{serviceCAInjectAnnotation: "true"},
+			},
+		}
+		if err := controllerutil.SetOwnerReference(gateway, &configMap, r.Scheme); err != nil {
+			return "", err
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := r.Create(ctx, &configMap); err != nil && !errors.IsAlreadyExists(err) {
+			return "", err
+		}
+		log.FromContext(ctx).Info("Created service CA ConfigMap, waiting for the CA bundle to be injected", "configMap", serviceCAConfigMapName)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// ConfigMaps created before Gateways owned them, or shared with a new Gateway, gain an owner
+	owners := len(configMap.OwnerReferences)
+	if err := controllerutil.SetOwnerReference(gateway, &configMap, r.Scheme); err != nil {
+		return "", err
+	}
+	if len(configMap.OwnerReferences) != owners {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := r.Update(ctx, &configMap); err != nil {
+			return "", err
+		}
+	}
+	return configMap.Data[serviceCAKey], nil
+}
+
+// releaseServiceCA removes gateway from the owners of the service CA ConfigMap in its namespace
+// once it no longer verifies with the service CA, deleting the ConfigMap when no other Gateway
+// uses it
+func (r *GatewayReconciler) releaseServiceCA(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: serviceCAConfigMapName}, &configMap); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	owners := slices.DeleteFunc(slices.Clone(configMap.OwnerReferences), func(ref metav1.OwnerReference) bool {
+		return ref.UID == gateway.UID
+	})
+	if len(owners) == len(configMap.OwnerReferences) {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(owners) == 0 {
+		if err := r.Delete(ctx, &configMap); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		log.FromContext(ctx).Info("Deleted service CA ConfigMap no Gateway uses anymore", "configMap", serviceCAConfigMapName)
+		return nil
+	}
+	configMap.OwnerReferences = owners
+	return r.Update(ctx, &configMap)
+}
+
+// mapConfigMapToGateways enqueues the Gateways whose destination CA is read from a service CA ConfigMap
+func (r *GatewayReconciler) mapConfigMapToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to list Gateways for ConfigMap", "configMap", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gateway := range gateways.Items {
+		caConfigMap := gateway.Annotations[destinationCAAnnotation]
+		if caConfigMap == "" && r.UseServiceCA {
+			caConfigMap = serviceCAConfigMapName
+		}
+		if caConfigMap == obj.GetName() && r.isGatewayClassSupported(ctx, string(gateway.Spec.GatewayClassName)) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listenerReasonNotExposed marks listeners left out of the Gateway's tinylb.io/expose-listeners annotation
+const listenerReasonNotExposed gatewayv1.ListenerConditionReason = "NotExposed"
+
+// ProgrammedPolicy controls how many of a Gateway's listeners must be programmed before the
+// Gateway itself is reported Programmed
+type ProgrammedPolicy string
+
+const (
+	// ProgrammedRequiresNone leaves listeners out of the Gateway's Programmed condition (the default)
+	ProgrammedRequiresNone ProgrammedPolicy = "none"
+	// ProgrammedRequiresAny requires at least one programmed listener
+	ProgrammedRequiresAny ProgrammedPolicy = "any"
+	// ProgrammedRequiresAll requires every listener to be programmed
+	ProgrammedRequiresAll ProgrammedPolicy = "all"
+)
+
+// ParseProgrammedPolicy validates a --programmed-requires value
+func ParseProgrammedPolicy(value string) (ProgrammedPolicy, error) {
+	policy := ProgrammedPolicy(value)
+	switch policy {
+	case ProgrammedRequiresNone, ProgrammedRequiresAny, ProgrammedRequiresAll:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown programmed policy %q (expected none, any or all)", value)
+}
+
+// unreadyListeners returns the listeners keeping a Gateway from being programmed under policy,
+// judged by the listener statuses the service yields; none means the policy is satisfied
+func unreadyListeners(gateway *gatewayv1.Gateway, service *corev1.Service, policy ProgrammedPolicy) []string {
+	if policy == "" || policy == ProgrammedRequiresNone {
+		return nil
+	}
+	var unready []string
+	programmed := 0
+	for _, status := range listenerStatuses(gateway, service) {
+		if meta.IsStatusConditionTrue(status.Conditions, string(gatewayv1.ListenerConditionProgrammed)) {
+			programmed++
+		} else {
+			unready = append(unready, string(status.Name))
+		}
+	}
+	if policy == ProgrammedRequiresAny && programmed > 0 {
+		return nil
+	}
+	return unready
+}
+
+// listenerRouteKinds are the Route kinds each listener protocol supports
+var listenerRouteKinds = map[gatewayv1.ProtocolType][]gatewayv1.Kind{
+	gatewayv1.HTTPProtocolType:  {"HTTPRoute", "GRPCRoute"},
+	gatewayv1.HTTPSProtocolType: {"HTTPRoute", "GRPCRoute"},
+	gatewayv1.TLSProtocolType:   {"TLSRoute"},
+	gatewayv1.TCPProtocolType:   {"TCPRoute"},
+	gatewayv1.UDPProtocolType:   {"UDPRoute"},
+}
+
+// listenerServicePort returns the LoadBalancer service port serving a listener, matched by port number
+func listenerServicePort(listener gatewayv1.Listener, service *corev1.Service) *corev1.ServicePort {
+	if service == nil || service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+	for i, port := range service.Spec.Ports {
+		if port.Port == int32(listener.Port) {
+			return &service.Spec.Ports[i]
+		}
+	}
+	return nil
+}
+
+// listenerRoute returns the name of the Route serving a listener: its own listener Route when it
+// gets one, otherwise the service's Route
+func listenerRoute(gateway *gatewayv1.Gateway, listener gatewayv1.Listener, service *corev1.Service) string {
+	if _, ok := listenerTermination(listener); ok && listenerExposed(gateway, listener.Name) {
+		if _, ok := listenerRouteHost(listener); ok {
+			return listenerRouteName(service.Name, listener.Name)
+		}
+	}
+	return routeNameForService(service.Name)
+}
+
+// listenerStatuses returns the status of each of the Gateway's listeners: whether the backing
+// service (nil when missing) exposes the listener's port, and the service port and target port it
+// resolved to. GatewayStatusAddress carries no port, so this is where consumers find the port
+// behind the advertised address. Conditions already on a listener keep their transition times.
+func listenerStatuses(gateway *gatewayv1.Gateway, service *corev1.Service) []gatewayv1.ListenerStatus {
+	previous := map[gatewayv1.SectionName][]metav1.Condition{}
+	for _, status := range gateway.Status.Listeners {
+		previous[status.Name] = status.Conditions
+	}
+	addressed := service != nil && len(ingressAddresses(service.Status.LoadBalancer.Ingress)) > 0
+
+	statuses := make([]gatewayv1.ListenerStatus, 0, len(gateway.Spec.Listeners))
+	for _, listener := range gateway.Spec.Listeners {
+		kinds := []gatewayv1.RouteGroupKind{}
+		for _, kind := range listenerRouteKinds[listener.Protocol] {
+			kinds = append(kinds, gatewayv1.RouteGroupKind{Group: ptr.To(gatewayv1.Group(gatewayv1.GroupName)), Kind: kind})
+		}
+
+		conditions := previous[listener.Name]
+		set := func(conditionType gatewayv1.ListenerConditionType, status metav1.ConditionStatus, reason gatewayv1.ListenerConditionReason, message string) {
+			meta.SetStatusCondition(&conditions, metav1.Condition{
+				Type:               string(conditionType),
+				Status:             status,
+				Reason:             string(reason),
+				Message:            message,
+				ObservedGeneration: gateway.Generation,
+			})
+		}
+
+		port := listenerServicePort(listener, service)
+		switch {
+		case !listenerExposed(gateway, listener.Name):
+			message := fmt.Sprintf("Listener is not listed in the Gateway's %s annotation", exposeListenersAnnotation)
+			set(gatewayv1.ListenerConditionAccepted, metav1.ConditionTrue, gatewayv1.ListenerReasonAccepted, message)
+			set(gatewayv1.ListenerConditionProgrammed, metav1.ConditionFalse, listenerReasonNotExposed, message)
+		case port == nil:
+			message := fmt.Sprintf("No LoadBalancer service port %d serves this listener", listener.Port)
+			set(gatewayv1.ListenerConditionAccepted, metav1.ConditionFalse, gatewayv1.ListenerReasonPortUnavailable, message)
+			set(gatewayv1.ListenerConditionProgrammed, metav1.ConditionFalse, gatewayv1.ListenerReasonInvalid, message)
+		case !addressed:
+			message := fmt.Sprintf("Served by service %s port %d, which has no external address yet", service.Name, port.Port)
+			set(gatewayv1.ListenerConditionAccepted, metav1.ConditionTrue, gatewayv1.ListenerReasonAccepted, message)
+			set(gatewayv1.ListenerConditionProgrammed, metav1.ConditionFalse, gatewayv1.ListenerReasonPending, message)
+		default:
+			targetPort := port.TargetPort
+			if targetPort.IntValue() == 0 && targetPort.Type == intstr.Int {
+				targetPort = intstr.FromInt32(port.Port)
+			}
+			message := fmt.Sprintf("Programmed via service %s port %d (target port %s) route %s",
+				service.Name, port.Port, targetPort.String(), listenerRoute(gateway, listener, service))
+			set(gatewayv1.ListenerConditionAccepted, metav1.ConditionTrue, gatewayv1.ListenerReasonAccepted, message)
+			set(gatewayv1.ListenerConditionProgrammed, metav1.ConditionTrue, gatewayv1.ListenerReasonProgrammed, message)
+		}
+
+		statuses = append(statuses, gatewayv1.ListenerStatus{
+			Name:           listener.Name,
+			SupportedKinds: kinds,
+			Conditions:     conditions,
+		})
+	}
+	return statuses
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Keys of the Gateway condition messages that can be customized
+// Several messages share a condition reason (e.g. NoResources), so messages are keyed by situation
+const (
+	MessageAccepted                = "Accepted"
+	MessageServiceNotFound         = "ServiceNotFound"
+	MessageServiceNotFoundTerminal = "ServiceNotFoundTerminal"
+	MessageServiceNotLoadBalancer  = "ServiceNotLoadBalancer"
+	MessageServiceNoExternalIP     = "ServiceNoExternalIP"
+	MessageProgrammedTimeout       = "ProgrammedTimeout"
+	MessageRouteNotFound           = "RouteNotFound"
+	MessageRouteNotAdmitted        = "RouteNotAdmitted"
+	MessageRouteRejected           = "RouteRejected"
+	MessageProgrammed              = "Programmed"
+	MessageAssumedProgrammed       = "AssumedProgrammed"
+	MessageMultipleGateways        = "MultipleGateways"
+	MessageBackendReady            = "BackendReady"
+	MessageBackendNotReady         = "BackendNotReady"
+	MessageListenersNotReady       = "ListenersNotReady"
+)
+
+// defaultMessageTemplates are the built-in condition messages
+var defaultMessageTemplates = map[string]string{
+	MessageAccepted:                "Gateway is accepted",
+	MessageServiceNotFound:         "LoadBalancer service {{.Service}} not found",
+	MessageServiceNotFoundTerminal: "LoadBalancer service {{.Service}} not found; stopped retrying until the Gateway or service changes",
+	MessageServiceNotLoadBalancer:  "Service {{.Service}} is not LoadBalancer type",
+	MessageServiceNoExternalIP:     "LoadBalancer service {{.Service}} has no external IP",
+	MessageProgrammedTimeout:       "LoadBalancer service {{.Service}} has had no external IP for over {{.Timeout}}; check that TinyLB's service controller is running and that the service is not excluded from it",
+	MessageRouteNotFound:           "Route {{.Route}} not found",
+	MessageRouteNotAdmitted:        "Route {{.Route}} has not been admitted by a router yet",
+	MessageRouteRejected:           "Route {{.Route}} was rejected by the router: {{.RouteRejection}}",
+	MessageProgrammed:              "Programmed via service {{.Service}}{{with .Port}} port {{.}}{{end}} route {{.Route}}",
+	MessageAssumedProgrammed:       "Gateway is programmed (Route check bypassed by " + assumeProgrammedAnnotation + ")",
+	MessageMultipleGateways:        "Gateway {{.ActiveGateway}} is the active Gateway of this class in {{.Namespace}}",
+	MessageBackendReady:            "Service {{.Service}} has ready endpoints",
+	MessageBackendNotReady:         "Service {{.Service}} has no ready endpoints",
+	MessageListenersNotReady:       "Listeners not programmed: {{.UnreadyListeners}}",
+}
+
+// MessageData is the context condition message templates are executed with
+type MessageData struct {
+	Gateway   string
+	Namespace string
+	Service   string
+	Route     string
+	Hostname  string
+
+	// Port is the service port the service's Route targets, set once the Route is found
+	Port string
+
+	// ActiveGateway is the Gateway programmed instead of this one in single-gateway-per-class mode
+	ActiveGateway string
+
+	// Timeout is the configured programmed timeout, set once it has expired
+	Timeout string
+
+	// RouteRejection is the router's reason and message for rejecting the Route, set once rejected
+	RouteRejection string
+
+	// UnreadyListeners lists the listeners keeping the Gateway from being programmed under
+	// --programmed-requires, comma-separated
+	UnreadyListeners string
+}
+
+// ConditionMessages renders Gateway condition messages from text/template strings
+type ConditionMessages struct {
+	templates map[string]*template.Template
+	sources   map[string]string
+}
+
+// defaultConditionMessages renders the built-in messages
+var defaultConditionMessages = mustConditionMessages(nil)
+
+// NewConditionMessages parses the default templates with the given overrides applied on top
+func NewConditionMessages(overrides map[string]string) (*ConditionMessages, error) {
+	messages := &ConditionMessages{templates: map[string]*template.Template{}, sources: map[string]string{}}
+	for key, text := range defaultMessageTemplates {
+		if override, ok := overrides[key]; ok {
+			text = override
+		}
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for message %s: %w", key, err)
+		}
+		messages.templates[key] = tmpl
+		messages.sources[key] = text
+	}
+	for key := range overrides {
+		if _, ok := defaultMessageTemplates[key]; !ok {
+			return nil, fmt.Errorf("unknown message key %q", key)
+		}
+	}
+	return messages, nil
+}
+
+func mustConditionMessages(overrides map[string]string) *ConditionMessages {
+	messages, err := NewConditionMessages(overrides)
+	if err != nil {
+		panic(err)
+	}
+	return messages
+}
+
+// configValue reports the message templates in use, overrides applied, on the /config endpoint
+func (m *ConditionMessages) configValue() map[string]string {
+	if m == nil {
+		m = defaultConditionMessages
+	}
+	return m.sources
+}
+
+// render executes the template for key, falling back to the built-in message if a custom template fails
+func (m *ConditionMessages) render(key string, data MessageData) string {
+	if m == nil {
+		m = defaultConditionMessages
+	}
+
+	var out strings.Builder
+	if err := m.templates[key].Execute(&out, data); err != nil {
+		if m == defaultConditionMessages {
+			return key
+		}
+		return defaultConditionMessages.render(key, data)
+	}
+	return out.String()
+}
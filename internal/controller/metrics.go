@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// reconcilesInFlight counts the reconciles currently running per controller; a value that
+// never drops back to zero points at a stuck reconcile
+var reconcilesInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "tinylb_reconciles_in_flight",
+	Help: "Number of reconciles currently in progress, by controller.",
+}, []string{"controller"})
+
+// buildInfo is always 1; its labels identify the running build, so a fleet can be checked for
+// stragglers with a single query
+var buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "tinylb_build_info",
+	Help: "A metric with a constant '1' value labeled by the version, commit and Go version TinyLB was built from.",
+}, []string{"version", "commit", "goversion"})
+
+// routesPerDomain counts the managed Routes whose host falls under each configured base domain,
+// so the spread across domains can be checked against what the hashing promises
+var routesPerDomain = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "tinylb_routes_per_domain",
+	Help: "Number of TinyLB-managed Routes using each configured base domain.",
+}, []string{"domain"})
+
+func init() {
+	metrics.Registry.MustRegister(reconcilesInFlight, buildInfo, routesPerDomain)
+}
+
+// SetBuildInfo publishes the version and commit of the running binary as tinylb_build_info
+func SetBuildInfo(version, commit string) {
+	buildInfo.Reset()
+	buildInfo.WithLabelValues(version, commit, runtime.Version()).Set(1)
+}
+
+// trackInFlight marks a reconcile of controller as started and returns the func that marks it
+// finished; defer the result so the gauge is decremented on every return path, panics included
+func trackInFlight(controller string) func() {
+	gauge := reconcilesInFlight.WithLabelValues(controller)
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// routeDomain returns the configured base domain a Route host falls under, the longest one when
+// several match, or "" when it uses none of them
+func routeDomain(host string, domains []string) string {
+	match := ""
+	for _, domain := range domains {
+		if strings.HasSuffix(host, "."+domain) && len(domain) > len(match) {
+			match = domain
+		}
+	}
+	return match
+}
+
+// routeDistributionInterval is how often tinylb_routes_per_domain is recounted
+const routeDistributionInterval = 30 * time.Second
+
+// routeDistributionRunnable recounts tinylb_routes_per_domain every routeDistributionInterval.
+// Counting on a timer rather than after each reconcile keeps the cost at one List per interval,
+// however many services there are. Only needed when more than one base domain is configured,
+// since there is nothing to spread otherwise.
+func (r *ServiceReconciler) routeDistributionRunnable() manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		wait.UntilWithContext(ctx, r.recordRouteDistribution, routeDistributionInterval)
+		return nil
+	})
+}
+
+// recordRouteDistribution recounts the managed Routes per base domain into tinylb_routes_per_domain.
+// Listing is served from the cache, and a failure only leaves the previous counts.
+func (r *ServiceReconciler) recordRouteDistribution(ctx context.Context) {
+	var routes routev1.RouteList
+	if err := r.List(ctx, &routes, client.MatchingLabels{managedLabel: "true"}); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to list Routes for the per-domain metric")
+		return
+	}
+
+	counts := make(map[string]int, len(r.BaseDomains))
+	for _, domain := range r.BaseDomains {
+		counts[domain] = 0
+	}
+	for _, route := range routes.Items {
+		if domain := routeDomain(route.Spec.Host, r.BaseDomains); domain != "" {
+			counts[domain]++
+		}
+	}
+	routesPerDomain.Reset()
+	for domain, count := range counts {
+		routesPerDomain.WithLabelValues(domain).Set(float64(count))
+	}
+}
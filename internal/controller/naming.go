@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The functions in this file are a stable API for integrators and tests that need to
+// predict the names TinyLB generates. Route hosts depend on the reconciler's configuration, so
+// they are predicted with (*ServiceReconciler).RouteHost instead.
+
+// RouteName returns the name of the Route TinyLB manages for a service
+func RouteName(service *corev1.Service) string {
+	return routeNameForService(service.Name)
+}
+
+// ValidateHostSuffix checks that suffix (e.g. "-dev") can be appended to a host's first DNS label
+func ValidateHostSuffix(suffix string) error {
+	if suffix == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label("x" + suffix); len(errs) > 0 {
+		return fmt.Errorf("invalid host suffix %q: %s", suffix, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// sanitizeHost lowercases host and replaces characters that are invalid in a DNS name with '-',
+// trimming dashes left at the edges of a label
+func sanitizeHost(host string) string {
+	host = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(host))
+
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		labels[i] = strings.Trim(label, "-")
+	}
+	return strings.Join(labels, ".")
+}
+
+// validateHost checks that host is a valid DNS subdomain made of valid DNS labels
+func validateHost(host string) error {
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+		return fmt.Errorf("host %q is invalid: %s", host, strings.Join(errs, "; "))
+	}
+	for _, label := range strings.Split(host, ".") {
+		if errs := validation.IsDNS1123Label(label); len(errs) > 0 {
+			return fmt.Errorf("host label %q is not a valid DNS label: %s", label, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// gatewayHost renders a --default-gateway-host-template for a Gateway, substituting {gateway} and
+// {namespace}; the result is sanitized, since Gateway names may contain dots
+func gatewayHost(template string, gateway *gatewayv1.Gateway) string {
+	return sanitizeHost(strings.NewReplacer(
+		"{gateway}", strings.ReplaceAll(gateway.Name, ".", "-"),
+		"{namespace}", gateway.Namespace,
+	).Replace(template))
+}
+
+// ValidateGatewayHostTemplate checks that a --default-gateway-host-template renders valid hosts
+func ValidateGatewayHostTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	if !strings.Contains(template, "{gateway}") {
+		return fmt.Errorf("invalid default gateway host template %q: must contain {gateway}", template)
+	}
+	sample := &gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "namespace"}}
+	if err := validateHost(gatewayHost(template, sample)); err != nil {
+		return fmt.Errorf("invalid default gateway host template %q: %w", template, err)
+	}
+	return nil
+}
+
+// GatewayServiceName returns the name of the LoadBalancer service expected to back a Gateway
+// Based on current TinyLB behavior, this follows patterns like: {gateway-name}-{gatewayClassName}
+func GatewayServiceName(gateway *gatewayv1.Gateway) string {
+	return fmt.Sprintf("%s-%s", gateway.Name, gateway.Spec.GatewayClassName)
+}
+
+// routeNameForService returns the Route name for a service known only by name
+func routeNameForService(serviceName string) string {
+	return fmt.Sprintf("tinylb-%s", serviceName)
+}
+
+// listenerRouteName returns the name of the Route TinyLB manages for one listener of a Gateway
+func listenerRouteName(serviceName string, listener gatewayv1.SectionName) string {
+	return fmt.Sprintf("%s-%s", routeNameForService(serviceName), listener)
+}
+
+// routeHostLabel returns the first DNS label of a service's Route host, e.g. {service}-{namespace}{suffix}
+func routeHostLabel(service *corev1.Service, suffix string) string {
+	return hostLabel(service.Name+"-"+service.Namespace, suffix)
+}
+
+// hostLabel joins base and suffix into one DNS label. A base that would push the label past the
+// 63 character limit is truncated and ends in a hash of the full base, so long names sharing a
+// prefix still get distinct labels; the suffix is always kept. A suffix leaving no room for the
+// hash is left for host validation to reject.
+func hostLabel(base, suffix string) string {
+	hash := fmt.Sprintf("%08x", hashString(base))
+	keep := validation.DNS1123LabelMaxLength - len(suffix) - len(hash) - 1
+	if len(base)+len(suffix) <= validation.DNS1123LabelMaxLength || keep < 1 {
+		return base + suffix
+	}
+	return strings.TrimRight(base[:keep], "-") + "-" + hash + suffix
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// pendingSinceAnnotation records when TinyLB first saw a Gateway waiting for its service's
+// external IP (RFC 3339). It survives controller restarts, so the programmed timeout is measured
+// from the first pending reconcile rather than from the last start.
+const pendingSinceAnnotation = "tinylb.io/pending-since"
+
+// gatewayReasonTimeout marks Gateways whose service got no external IP within the programmed timeout
+const gatewayReasonTimeout gatewayv1.GatewayConditionReason = "Timeout"
+
+// pendingSince returns when the Gateway started waiting for an external IP, stamping the current
+// time on the Gateway if it has no valid record yet
+func (r *GatewayReconciler) pendingSince(ctx context.Context, gateway *gatewayv1.Gateway) (time.Time, error) {
+	if since, err := time.Parse(time.RFC3339, gateway.Annotations[pendingSinceAnnotation]); err == nil {
+		return since, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	patch := client.MergeFrom(gateway.DeepCopy())
+	metav1.SetMetaDataAnnotation(&gateway.ObjectMeta, pendingSinceAnnotation, now.Format(time.RFC3339))
+	return now, r.Patch(ctx, gateway, patch)
+}
+
+// clearPendingSince removes the pending record once the service has an external IP, so the
+// timeout starts over if the Gateway becomes pending again
+func (r *GatewayReconciler) clearPendingSince(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	if _, ok := gateway.Annotations[pendingSinceAnnotation]; !ok {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(gateway.DeepCopy())
+	delete(gateway.Annotations, pendingSinceAnnotation)
+	return r.Patch(ctx, gateway, patch)
+}
+
+// programmedTimedOut reports whether the Gateway has been pending for longer than the programmed
+// timeout, and otherwise how long until it will be
+func (r *GatewayReconciler) programmedTimedOut(ctx context.Context, gateway *gatewayv1.Gateway) (bool, time.Duration, error) {
+	since, err := r.pendingSince(ctx, gateway)
+	if err != nil {
+		return false, 0, err
+	}
+	remaining := r.ProgrammedTimeout - time.Since(since)
+	return remaining <= 0, remaining, nil
+}
+
+// timedOut reports whether the Gateway is already marked as timed out
+func timedOut(gateway *gatewayv1.Gateway) bool {
+	programmed := meta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+	return programmed != nil && programmed.Reason == string(gatewayReasonTimeout)
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// http2Annotation turns HTTP/2 off ("false") or forces it on ("true") for a service's Route;
+	// without it the router default applies
+	http2Annotation = "tinylb.io/http2"
+
+	// disableHTTP2RouteAnnotation is the router annotation controlling HTTP/2 on a Route
+	disableHTTP2RouteAnnotation = "haproxy.router.openshift.io/disable_http2"
+
+	// hstsAnnotation sets the Strict-Transport-Security header (e.g. "max-age=31536000;includeSubDomains")
+	// on TLS-terminating Routes. It is read from Gateways for their listener Routes; a Service's own
+	// Route is passthrough, where the router never sees the HTTP response.
+	hstsAnnotation = "tinylb.io/hsts"
+
+	// hstsRouteAnnotation is the router annotation adding the HSTS header
+	hstsRouteAnnotation = "haproxy.router.openshift.io/hsts_header"
+
+	// rewriteTargetAnnotation rewrites the path of requests (e.g. "/") before they reach the backend.
+	// It is read from Gateways for their TLS-terminating listener Routes, and from Services whose
+	// Route is plain HTTP (h2c ports): the router cannot see the path on a passthrough Route.
+	rewriteTargetAnnotation = "tinylb.io/rewrite-target"
+
+	// rewriteTargetRouteAnnotation is the router annotation replacing the Route's path prefix
+	rewriteTargetRouteAnnotation = "haproxy.router.openshift.io/rewrite-target"
+
+	// wildcardPolicyAnnotation requests a Route wildcard policy: "None" or "Subdomain", which
+	// makes the Route also serve every host in the parent domain of its own host
+	wildcardPolicyAnnotation = "tinylb.io/wildcard-policy"
+
+	// routeWeightAnnotation sets the backend weight (0-256) of a service's Route, overriding
+	// --default-route-weight
+	routeWeightAnnotation = "tinylb.io/route-weight"
+
+	// balanceRouteAnnotation selects the router's load-balancing algorithm for a Route
+	balanceRouteAnnotation = "haproxy.router.openshift.io/balance"
+
+	// ipAllowlistRouteAnnotation restricts a Route to clients in a space-separated list of CIDRs
+	ipAllowlistRouteAnnotation = "haproxy.router.openshift.io/ip_whitelist"
+
+	// sourceRangesAnnotation is the annotation form of loadBalancerSourceRanges, a comma-separated
+	// list of CIDRs, honored when the field is not set
+	sourceRangesAnnotation = "service.beta.kubernetes.io/load-balancer-source-ranges"
+
+	// denyAllAllowlist is an IP allowlist no client matches, since 0.0.0.0 is never a source address
+	denyAllAllowlist = "0.0.0.0/32"
+
+	// ownedRouteAnnotationsAnnotation lists, comma-separated, the managed Route annotations TinyLB
+	// set on a Route, so only those are removed again, never ones set by hand
+	ownedRouteAnnotationsAnnotation = "tinylb.io/owned-route-annotations"
+)
+
+// managedRouteAnnotations are the Route annotations TinyLB derives from service annotations.
+// Those TinyLB set are removed from the Route again once the service annotation driving them
+// goes away.
+var managedRouteAnnotations = []string{
+	disableHTTP2RouteAnnotation,
+	hstsRouteAnnotation,
+	rewriteTargetRouteAnnotation,
+	balanceRouteAnnotation,
+	ipAllowlistRouteAnnotation,
+}
+
+// validateHSTS checks a Strict-Transport-Security value: a max-age in seconds, optionally
+// followed by includeSubDomains and preload, separated by semicolons
+func validateHSTS(value string) error {
+	hasMaxAge := false
+	for _, directive := range strings.Split(value, ";") {
+		directive = strings.TrimSpace(directive)
+		name, arg, hasArg := strings.Cut(directive, "=")
+		switch {
+		case strings.EqualFold(name, "max-age") && hasArg:
+			if _, err := strconv.ParseUint(strings.Trim(arg, `"`), 10, 64); err != nil {
+				return fmt.Errorf("max-age %q is not a number of seconds", arg)
+			}
+			hasMaxAge = true
+		case (strings.EqualFold(name, "includeSubDomains") || strings.EqualFold(name, "preload")) && !hasArg:
+		default:
+			return fmt.Errorf("unknown HSTS directive %q", directive)
+		}
+	}
+	if !hasMaxAge {
+		return fmt.Errorf("HSTS header %q has no max-age", value)
+	}
+	return nil
+}
+
+// validateRewriteTarget checks a rewrite target: an absolute path without whitespace, which the
+// router substitutes for the Route's path prefix. Listener Routes have no path, so the prefix
+// replaced is always "/".
+func validateRewriteTarget(value string) error {
+	if !strings.HasPrefix(value, "/") {
+		return fmt.Errorf("rewrite target %q is not an absolute path", value)
+	}
+	if strings.ContainsFunc(value, unicode.IsSpace) {
+		return fmt.Errorf("rewrite target %q contains whitespace", value)
+	}
+	return nil
+}
+
+// routeAnnotations returns the Route annotations requested by the service's tinylb.io annotations
+// Invalid values are reported with a Warning event and ignored, leaving the router default
+func (r *ServiceReconciler) routeAnnotations(service *corev1.Service) map[string]string {
+	annotations := map[string]string{}
+
+	if value, ok := service.Annotations[http2Annotation]; ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			r.warnings.warnf(r.Recorder, service, http2Annotation, "InvalidAnnotation",
+				"Ignoring %s annotation: %q is not true or false", http2Annotation, value)
+		} else {
+			r.warnings.resolve(service, http2Annotation)
+			annotations[disableHTTP2RouteAnnotation] = strconv.FormatBool(!enabled)
+		}
+	} else {
+		r.warnings.resolve(service, http2Annotation)
+	}
+
+	// Keep ClientIP affinity end-to-end by pinning clients to a backend by source address. Cookie
+	// based stickiness is not an option: the router cannot read or set cookies on passthrough Routes.
+	if service.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
+		annotations[balanceRouteAnnotation] = "source"
+	}
+
+	// loadBalancerSourceRanges is the native way to restrict a load balancer; the router enforces it per Route
+	if allowlist := r.sourceRangesAllowlist(service); allowlist != "" {
+		annotations[ipAllowlistRouteAnnotation] = allowlist
+	}
+
+	// Service Routes are passthrough: the router cannot add headers to the TLS stream
+	if _, ok := service.Annotations[hstsAnnotation]; ok {
+		r.warnings.warnf(r.Recorder, service, hstsAnnotation, "InvalidAnnotation",
+			"Ignoring %s annotation: HSTS needs a TLS-terminating Route and the service's Route is passthrough", hstsAnnotation)
+	} else {
+		r.warnings.resolve(service, hstsAnnotation)
+	}
+
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// rewriteTarget returns the path requests on the service's Route are rewritten to, as requested by
+// its tinylb.io/rewrite-target annotation, or "" for none. Only Routes the router does not pass TLS
+// through qualify; otherwise, or when the value is invalid, a Warning event is emitted once and the
+// annotation is ignored.
+func (r *ServiceReconciler) rewriteTarget(service *corev1.Service, tls *routev1.TLSConfig) string {
+	value, ok := service.Annotations[rewriteTargetAnnotation]
+	if !ok {
+		r.warnings.resolve(service, rewriteTargetAnnotation)
+		return ""
+	}
+	if tls != nil && tls.Termination == routev1.TLSTerminationPassthrough {
+		r.warnings.warnf(r.Recorder, service, rewriteTargetAnnotation, "InvalidAnnotation",
+			"Ignoring %s annotation: rewriting paths needs a Route the router does not pass TLS through and the service's Route is passthrough",
+			rewriteTargetAnnotation)
+		return ""
+	}
+	if err := validateRewriteTarget(value); err != nil {
+		r.warnings.warnf(r.Recorder, service, rewriteTargetAnnotation, "InvalidAnnotation", "Ignoring %s annotation: %v", rewriteTargetAnnotation, err)
+		return ""
+	}
+	r.warnings.resolve(service, rewriteTargetAnnotation)
+	return value
+}
+
+// sourceRangesAllowlist returns the service's loadBalancerSourceRanges, or without them its
+// load-balancer-source-ranges annotation, as a router IP allowlist. Ranges that are not CIDRs are
+// reported with a Warning event and left out, which only narrows access: when none is valid, the
+// allowlist admits no client rather than every client.
+func (r *ServiceReconciler) sourceRangesAllowlist(service *corev1.Service) string {
+	requested, source := service.Spec.LoadBalancerSourceRanges, "loadBalancerSourceRanges"
+	if value := service.Annotations[sourceRangesAnnotation]; len(requested) == 0 && strings.TrimSpace(value) != "" {
+		requested, source = strings.Split(value, ","), sourceRangesAnnotation
+	}
+
+	var ranges, invalid []string
+	for _, sourceRange := range requested {
+		sourceRange = strings.TrimSpace(sourceRange)
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			invalid = append(invalid, strconv.Quote(sourceRange))
+			continue
+		}
+		ranges = append(ranges, sourceRange)
+	}
+
+	if len(invalid) == 0 {
+		r.warnings.resolve(service, "InvalidSourceRange")
+	} else if len(ranges) == 0 {
+		r.warnings.warnf(r.Recorder, service, "InvalidSourceRange", "InvalidSourceRange",
+			"No %s entry is a CIDR (%s); the Route admits no client until they are fixed", source, strings.Join(invalid, ", "))
+		return denyAllAllowlist
+	} else {
+		r.warnings.warnf(r.Recorder, service, "InvalidSourceRange", "InvalidSourceRange",
+			"Ignoring %s entries that are not CIDRs: %s", source, strings.Join(invalid, ", "))
+	}
+	return strings.Join(ranges, " ")
+}
+
+// wildcardPolicy returns the Route wildcard policy requested by the service's tinylb.io/wildcard-policy
+// annotation, or "" for the router default. A Subdomain policy needs a host with a parent domain
+// below the top level, since the Route then claims *.parent; values that cannot apply to host are
+// reported with a Warning event, once while they stand, and ignored.
+func (r *ServiceReconciler) wildcardPolicy(service *corev1.Service, host string) routev1.WildcardPolicyType {
+	value, ok := service.Annotations[wildcardPolicyAnnotation]
+	if !ok {
+		r.warnings.resolve(service, wildcardPolicyAnnotation)
+		return ""
+	}
+	switch policy := routev1.WildcardPolicyType(value); policy {
+	case routev1.WildcardPolicyNone:
+		r.warnings.resolve(service, wildcardPolicyAnnotation)
+		return policy
+	case routev1.WildcardPolicySubdomain:
+		if strings.Count(host, ".") < 2 {
+			r.warnings.warnf(r.Recorder, service, wildcardPolicyAnnotation, "InvalidAnnotation",
+				"Ignoring %s annotation: host %q has no parent domain a Subdomain wildcard could cover", wildcardPolicyAnnotation, host)
+			return ""
+		}
+		r.warnings.resolve(service, wildcardPolicyAnnotation)
+		return policy
+	default:
+		r.warnings.warnf(r.Recorder, service, wildcardPolicyAnnotation, "InvalidAnnotation",
+			"Ignoring %s annotation: %q is not None or Subdomain", wildcardPolicyAnnotation, value)
+		return ""
+	}
+}
+
+// routeWeight returns the backend weight for the service's Route: its tinylb.io/route-weight
+// annotation if valid, otherwise DefaultRouteWeight. Invalid values get a Warning event when they
+// are first seen.
+func (r *ServiceReconciler) routeWeight(service *corev1.Service) *int32 {
+	value, ok := service.Annotations[routeWeightAnnotation]
+	if !ok {
+		r.warnings.resolve(service, routeWeightAnnotation)
+		return r.DefaultRouteWeight
+	}
+	weight, err := strconv.Atoi(value)
+	if err == nil {
+		err = ValidateRouteWeight(weight)
+	}
+	if err != nil {
+		r.warnings.warnf(r.Recorder, service, routeWeightAnnotation, "InvalidAnnotation",
+			"Ignoring %s annotation: %q is not a weight between 0 and %d", routeWeightAnnotation, value, maxRouteWeight)
+		return r.DefaultRouteWeight
+	}
+	r.warnings.resolve(service, routeWeightAnnotation)
+	return ptr.To(int32(weight))
+}
+
+// ownedRouteAnnotations returns the managed annotations TinyLB recorded setting on a Route
+func ownedRouteAnnotations(route *routev1.Route) []string {
+	value := route.Annotations[ownedRouteAnnotationsAnnotation]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// setOwnedRouteAnnotations records which managed annotations on a Route TinyLB set
+func setOwnedRouteAnnotations(route *routev1.Route, owned []string) {
+	if len(owned) == 0 {
+		delete(route.Annotations, ownedRouteAnnotationsAnnotation)
+		return
+	}
+	metav1.SetMetaDataAnnotation(&route.ObjectMeta, ownedRouteAnnotationsAnnotation, strings.Join(owned, ","))
+}
+
+// claimRouteAnnotations records every managed annotation on a Route about to be created as set by TinyLB
+func claimRouteAnnotations(route *routev1.Route) {
+	var owned []string
+	for _, key := range managedRouteAnnotations {
+		if _, has := route.Annotations[key]; has {
+			owned = append(owned, key)
+		}
+	}
+	setOwnedRouteAnnotations(route, owned)
+}
+
+// syncRouteAnnotations brings the TinyLB-managed annotations on an existing Route in line with
+// desired. An annotation no longer desired is only removed when TinyLB set it.
+func (r *ServiceReconciler) syncRouteAnnotations(ctx context.Context, route *routev1.Route, desired map[string]string) error {
+	patch := client.MergeFrom(route.DeepCopy())
+	changed := false
+	previouslyOwned := ownedRouteAnnotations(route)
+	var owned []string
+	for _, key := range managedRouteAnnotations {
+		current, has := route.Annotations[key]
+		want, wanted := desired[key]
+		switch {
+		case wanted:
+			owned = append(owned, key)
+			if !has || current != want {
+				if route.Annotations == nil {
+					route.Annotations = map[string]string{}
+				}
+				route.Annotations[key] = want
+				changed = true
+			}
+		case has && slices.Contains(previouslyOwned, key):
+			delete(route.Annotations, key)
+			changed = true
+		}
+	}
+	if strings.Join(owned, ",") != route.Annotations[ownedRouteAnnotationsAnnotation] {
+		setOwnedRouteAnnotations(route, owned)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Updating Route annotations", "route", route.Name)
+	return r.Patch(ctx, route, patch)
+}
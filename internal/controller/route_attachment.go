@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// parentRefTargets reports whether an HTTPRoute in routeNamespace attaches through ref to listener
+func parentRefTargets(ref gatewayv1.ParentReference, routeNamespace string, gateway *gatewayv1.Gateway, listener gatewayv1.Listener) bool {
+	if ref.Group != nil && *ref.Group != gatewayv1.GroupName {
+		return false
+	}
+	if ref.Kind != nil && *ref.Kind != "Gateway" {
+		return false
+	}
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	if namespace != gateway.Namespace || string(ref.Name) != gateway.Name {
+		return false
+	}
+	if ref.SectionName != nil && *ref.SectionName != listener.Name {
+		return false
+	}
+	return ref.Port == nil || *ref.Port == listener.Port
+}
+
+// listenerAllowsHTTPRoutes reports whether a listener accepts HTTPRoutes at all, from its
+// allowedRoutes.kinds or, when unset, the kinds its protocol supports
+func listenerAllowsHTTPRoutes(listener gatewayv1.Listener) bool {
+	isHTTPRoute := func(kind gatewayv1.Kind) bool { return kind == "HTTPRoute" }
+	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
+		return slices.ContainsFunc(listenerRouteKinds[listener.Protocol], isHTTPRoute)
+	}
+	return slices.ContainsFunc(listener.AllowedRoutes.Kinds, func(kind gatewayv1.RouteGroupKind) bool {
+		return (kind.Group == nil || *kind.Group == gatewayv1.GroupName) && isHTTPRoute(kind.Kind)
+	})
+}
+
+// listenerAllowsNamespace applies a listener's allowedRoutes.namespaces to a Route namespace:
+// Same (the default) admits the Gateway's namespace only, All every namespace, and Selector the
+// namespaces whose labels match
+func (r *GatewayReconciler) listenerAllowsNamespace(ctx context.Context, gateway *gatewayv1.Gateway, listener gatewayv1.Listener, namespace string) (bool, error) {
+	from := gatewayv1.NamespacesFromSame
+	var selector *metav1.LabelSelector
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil {
+		if listener.AllowedRoutes.Namespaces.From != nil {
+			from = *listener.AllowedRoutes.Namespaces.From
+		}
+		selector = listener.AllowedRoutes.Namespaces.Selector
+	}
+
+	switch from {
+	case gatewayv1.NamespacesFromAll:
+		return true, nil
+	case gatewayv1.NamespacesFromSelector:
+		if selector == nil {
+			return false, nil
+		}
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			r.warnings.warnf(r.Recorder, gateway, "InvalidAllowedRoutes/"+string(listener.Name), "InvalidAllowedRoutes",
+				"Listener %s has an invalid namespace selector: %v", listener.Name, err)
+			return false, nil
+		}
+		r.warnings.resolve(gateway, "InvalidAllowedRoutes/"+string(listener.Name))
+		var ns corev1.Namespace
+		if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return labelSelector.Matches(labels.Set(ns.Labels)), nil
+	default:
+		return namespace == gateway.Namespace, nil
+	}
+}
+
+// attachedRoutes counts, per listener, the HTTPRoutes attached to the Gateway that the listener's
+// allowedRoutes admits. HTTPRoutes whose every matching listener refuses them are not counted and
+// are reported with a RouteNotAllowed Warning when they are first refused; their own status
+// belongs to the controller implementing HTTPRoute.
+func (r *GatewayReconciler) attachedRoutes(ctx context.Context, gateway *gatewayv1.Gateway) (map[gatewayv1.SectionName]int32, error) {
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return nil, err
+	}
+
+	attached := map[gatewayv1.SectionName]int32{}
+	var refused []string
+	for _, route := range routes.Items {
+		targeted, admitted := false, false
+		for _, listener := range gateway.Spec.Listeners {
+			if !slices.ContainsFunc(route.Spec.ParentRefs, func(ref gatewayv1.ParentReference) bool {
+				return parentRefTargets(ref, route.Namespace, gateway, listener)
+			}) {
+				continue
+			}
+			targeted = true
+			if !listenerAllowsHTTPRoutes(listener) {
+				continue
+			}
+			allowed, err := r.listenerAllowsNamespace(ctx, gateway, listener, route.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			if allowed {
+				attached[listener.Name]++
+				admitted = true
+			}
+		}
+		if targeted && !admitted {
+			log.FromContext(ctx).Info("HTTPRoute not allowed by any listener", "httpRoute", client.ObjectKeyFromObject(&route))
+			key := "RouteNotAllowed/" + client.ObjectKeyFromObject(&route).String()
+			refused = append(refused, key)
+			r.warnings.warnf(r.Recorder, gateway, key, "RouteNotAllowed",
+				"HTTPRoute %s/%s is not allowed by the allowedRoutes of the listeners it targets", route.Namespace, route.Name)
+		}
+	}
+	r.warnings.resolveOthers(gateway, "RouteNotAllowed/", refused)
+	return attached, nil
+}
+
+// mapHTTPRouteToGateways enqueues the Gateways an HTTPRoute names as parents
+func (r *GatewayReconciler) mapHTTPRouteToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	route, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, ref := range route.Spec.ParentRefs {
+		if (ref.Group != nil && *ref.Group != gatewayv1.GroupName) || (ref.Kind != nil && *ref.Kind != "Gateway") {
+			continue
+		}
+		namespace := route.Namespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: string(ref.Name)}}
+		if !slices.Contains(requests, request) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	networkingv1alpha1 "github.com/jctanner/tinylb/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// A RouteBinding mirrors the state of a service's Route in a queryable object, so tooling can
+// list every service→Route binding and see why a Route is missing without digging through events.
+// It shares the Route's name and is owned by the service, so it goes away with it.
+
+// bindRoute records route, or the error that kept it from being created, in the service's
+// RouteBinding. It is a no-op unless RouteBindings is enabled.
+func (r *ServiceReconciler) bindRoute(ctx context.Context, service *corev1.Service, route *routev1.Route, createErr error) error {
+	if !r.RouteBindings {
+		return nil
+	}
+
+	binding := &networkingv1alpha1.RouteBinding{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: service.Namespace, Name: RouteName(service)}, binding)
+	if errors.IsNotFound(err) {
+		binding = &networkingv1alpha1.RouteBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      RouteName(service),
+				Namespace: service.Namespace,
+				Labels: map[string]string{
+					managedLabel:    "true",
+					serviceLabel:    service.Name,
+					serviceUIDLabel: string(service.UID),
+				},
+			},
+			Spec: networkingv1alpha1.RouteBindingSpec{ServiceName: service.Name, RouteName: route.Name},
+		}
+		if err := controllerutil.SetOwnerReference(service, binding, r.Scheme); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, binding); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Created RouteBinding", "routeBinding", binding.Name, "service", service.Name)
+	} else if err != nil {
+		return err
+	}
+
+	status := *binding.Status.DeepCopy()
+	if createErr != nil {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               networkingv1alpha1.RouteBindingConditionRouteCreated,
+			Status:             metav1.ConditionFalse,
+			Reason:             "CreateFailed",
+			Message:            createErr.Error(),
+			ObservedGeneration: binding.Generation,
+		})
+	} else {
+		status.Host = route.Spec.Host
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               networkingv1alpha1.RouteBindingConditionRouteCreated,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Created",
+			Message:            "Route " + route.Name + " exists",
+			ObservedGeneration: binding.Generation,
+		})
+		meta.SetStatusCondition(&status.Conditions, routeAdmittedCondition(route, binding.Generation))
+	}
+	if binding.Spec.RouteName != route.Name {
+		patch := client.MergeFrom(binding.DeepCopy())
+		binding.Spec.RouteName = route.Name
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Patch(ctx, binding, patch); err != nil {
+			return err
+		}
+	}
+	if equality.Semantic.DeepEqual(status, binding.Status) {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	binding.Status = status
+	return r.Status().Update(ctx, binding)
+}
+
+// routeAdmittedCondition summarizes the router's admission of route as the RouteBinding's
+// Admitted condition; it stays Unknown until a router reports on the Route
+func routeAdmittedCondition(route *routev1.Route, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               networkingv1alpha1.RouteBindingConditionAdmitted,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Pending",
+		Message:            "No router has reported on the Route yet",
+		ObservedGeneration: generation,
+	}
+	for _, ingress := range route.Status.Ingress {
+		for _, c := range ingress.Conditions {
+			if c.Type != routev1.RouteAdmitted {
+				continue
+			}
+			switch c.Status {
+			case corev1.ConditionTrue:
+				condition.Status, condition.Reason = metav1.ConditionTrue, "Admitted"
+				condition.Message = "Route admitted by router " + ingress.RouterName
+				return condition
+			case corev1.ConditionFalse:
+				condition.Status, condition.Reason = metav1.ConditionFalse, "Rejected"
+				condition.Message = "Route rejected by router " + ingress.RouterName
+				if c.Message != "" {
+					condition.Message += ": " + c.Message
+				}
+			}
+		}
+	}
+	return condition
+}
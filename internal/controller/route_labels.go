@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// routerShardAnnotation names the router shard a service's Route should be served by
+	routerShardAnnotation = "tinylb.io/router-shard"
+
+	// routerShardSelectionLabel carries tinylb.io/router-shard onto the Route, for router shards
+	// whose route selector matches on it
+	routerShardSelectionLabel = "router-shard"
+
+	// routerShardLabelledAnnotation marks a Route whose router-shard label TinyLB set, so only
+	// that label is removed when the service stops asking for a shard, never one set by hand
+	routerShardLabelledAnnotation = "tinylb.io/router-shard-labelled"
+
+	// routerLabelledAnnotation marks a Route whose hash-derived router label TinyLB set, so only
+	// that label is removed when --router-shards is turned off
+	routerLabelledAnnotation = "tinylb.io/router-labelled"
+)
+
+// labelMarkers maps the Route labels TinyLB removes only when it set them itself to the
+// annotation recording that it did
+var labelMarkers = map[string]string{
+	routerShardSelectionLabel: routerShardLabelledAnnotation,
+	routerShardLabel:          routerLabelledAnnotation,
+}
+
+// markLabels records on a Route about to be created which of the marked labels TinyLB set
+func markLabels(route *routev1.Route) {
+	for key, marker := range labelMarkers {
+		if _, ok := route.Labels[key]; ok {
+			metav1.SetMetaDataAnnotation(&route.ObjectMeta, marker, "true")
+		}
+	}
+}
+
+// isReservedLabel reports whether key belongs to TinyLB or to Kubernetes itself. Such labels are
+// never mirrored: TinyLB's own labels identify the Route, and system labels describe the service
+// object rather than the workload. app.kubernetes.io is the exception, being the recommended
+// namespace for application labels.
+func isReservedLabel(key string) bool {
+	domain, _, ok := strings.Cut(key, "/")
+	if !ok {
+		return false
+	}
+	if domain == "app.kubernetes.io" {
+		return false
+	}
+	for _, reserved := range []string{"tinylb.io", "kubernetes.io", "k8s.io"} {
+		if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// mirrorsLabel reports whether key is selected by MirrorServiceLabels. Entries ending in "/"
+// select every label with that prefix; other entries select the exact key.
+func (r *ServiceReconciler) mirrorsLabel(key string) bool {
+	if isReservedLabel(key) {
+		return false
+	}
+	for _, selector := range r.MirrorServiceLabels {
+		if strings.HasSuffix(selector, "/") && strings.HasPrefix(key, selector) || key == selector {
+			return true
+		}
+	}
+	return false
+}
+
+// mirroredLabels returns the service labels selected for copying onto its Route
+func (r *ServiceReconciler) mirroredLabels(service *corev1.Service) map[string]string {
+	if len(r.MirrorServiceLabels) == 0 {
+		return nil
+	}
+	labels := map[string]string{}
+	for key, value := range service.Labels {
+		if r.mirrorsLabel(key) {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// requestedRouterShard returns the router shard a service asks for through tinylb.io/router-shard,
+// or "" when it names none or an invalid label value
+func (r *ServiceReconciler) requestedRouterShard(service *corev1.Service) string {
+	value := service.Annotations[routerShardAnnotation]
+	if value == "" {
+		r.warnings.resolve(service, routerShardAnnotation)
+		return ""
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		r.warnings.warnf(r.Recorder, service, routerShardAnnotation, "InvalidAnnotation",
+			"Ignoring %s annotation: %s", routerShardAnnotation, strings.Join(errs, "; "))
+		return ""
+	}
+	r.warnings.resolve(service, routerShardAnnotation)
+	return value
+}
+
+// syncRouteLabels brings the mirrored and router shard labels on an existing Route in line with
+// desired, removing those the service no longer asks for and leaving every other label untouched.
+// A router shard label is only removed when TinyLB set it.
+func (r *ServiceReconciler) syncRouteLabels(ctx context.Context, route *routev1.Route, desired map[string]string) error {
+	patch := client.MergeFrom(route.DeepCopy())
+	changed := false
+	for key, want := range desired {
+		if current, has := route.Labels[key]; !has || current != want {
+			if route.Labels == nil {
+				route.Labels = map[string]string{}
+			}
+			route.Labels[key] = want
+			changed = true
+		}
+	}
+	for key := range route.Labels {
+		if _, wanted := desired[key]; wanted {
+			continue
+		}
+		marker, marked := labelMarkers[key]
+		if marked && route.Annotations[marker] == "true" || r.mirrorsLabel(key) {
+			delete(route.Labels, key)
+			changed = true
+		}
+	}
+	for key, marker := range labelMarkers {
+		_, wanted := desired[key]
+		if labelled := route.Annotations[marker] == "true"; wanted == labelled {
+			continue
+		}
+		if wanted {
+			metav1.SetMetaDataAnnotation(&route.ObjectMeta, marker, "true")
+		} else {
+			delete(route.Annotations, marker)
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Updating Route labels", "route", route.Name)
+	return r.Patch(ctx, route, patch)
+}
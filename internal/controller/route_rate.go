@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// routeCreateLimiter paces Route creations across every service, so a mass onboarding reaches
+// the router as a steady trickle of new Routes rather than all at once
+type routeCreateLimiter struct {
+	mu      sync.Mutex
+	limiter *rate.Limiter
+}
+
+// reserve takes a Route creation from the budget of qps creations per second. It returns zero when
+// the creation may go ahead, or how long to wait before trying again; waiting does not hold a
+// place, so the budget goes to whichever service asks first once it refills.
+func (l *routeCreateLimiter) reserve(qps float64, now time.Time) time.Duration {
+	if qps <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limiter == nil || l.limiter.Limit() != rate.Limit(qps) {
+		l.limiter = rate.NewLimiter(rate.Limit(qps), 1)
+	}
+	reservation := l.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
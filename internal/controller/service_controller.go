@@ -17,87 +17,826 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"context"
+	stderrors "errors"
 	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	networkingv1alpha1 "github.com/jctanner/tinylb/api/v1alpha1"
 	routev1 "github.com/openshift/api/route/v1"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// DefaultManagementPorts are common management/status ports avoided during port selection
+var DefaultManagementPorts = []int32{15021, 15090, 9090, 8181}
+
+const (
+	// DefaultBaseDomain is the wildcard domain used when no base domains are configured
+	DefaultBaseDomain = "apps-crc.testing"
+
+	// DefaultRouteWeightUnset is the default route weight setting that leaves each Route at the
+	// router's default weight
+	DefaultRouteWeightUnset = -1
+
+	// Labels identifying Routes managed by TinyLB and the service they front
+	managedLabel    = "tinylb.io/managed"
+	serviceLabel    = "tinylb.io/service"
+	serviceUIDLabel = "tinylb.io/service-uid"
+
+	// Labels identifying per-listener Routes created on behalf of a Gateway
+	gatewayLabel  = "tinylb.io/gateway"
+	listenerLabel = "tinylb.io/listener"
+
+	// Annotations naming the Gateway a listener Route was created for; unlike the labels they also
+	// carry its namespace, which Routes in a central namespace cannot otherwise tell
+	gatewayAnnotation          = "tinylb.io/gateway"
+	gatewayNamespaceAnnotation = "tinylb.io/gateway-namespace"
+
+	// routerShardLabel is the Route label OpenShift router shards select on
+	routerShardLabel = "router"
+
+	// skipPortsAnnotation lists extra ports (e.g. "9000,9001") a service wants excluded from selection
+	skipPortsAnnotation = "tinylb.io/skip-ports"
+
+	// externalDNSHostnameAnnotation is the external-dns hostname annotation, honored as the Route host when enabled
+	externalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+	// placeholderRouteWeight marks an eagerly created Route whose backend is not ready yet; a Route
+	// whose only backend has weight 0 makes the router answer 503
+	placeholderRouteWeight int32 = 0
+
+	// defaultRouteWeight is the router's default backend weight
+	defaultRouteWeight int32 = 100
+
+	// maxRouteWeight is the highest backend weight the router accepts
+	maxRouteWeight = 256
+
+	// forceAnnotation keeps TinyLB in charge of a service even when another provider writes its ingress
+	forceAnnotation = "tinylb.io/force"
+
+	// handedOffAnnotation marks a service whose ingress another provider took over; TinyLB leaves
+	// it alone until the foreign entries disappear
+	handedOffAnnotation = "tinylb.io/handed-off"
+
+	// baseDomainAnnotation records the base domain assigned to a service so the
+	// assignment stays stable when the configured domain list changes order or size
+	baseDomainAnnotation = "tinylb.io/base-domain"
+
+	// providerTinyLB is the ProviderAnnotation value naming TinyLB
+	providerTinyLB = "tinylb"
+
+	// h2cAppProtocol marks a service port speaking cleartext HTTP/2. The router only speaks h2c to
+	// backends of plain HTTP Routes, so such ports cannot sit behind a passthrough Route
+	h2cAppProtocol = "kubernetes.io/h2c"
+
+	// routeTargetPortAnnotation records the target port of a service's Route, so the port
+	// selection can be audited after the fact
+	routeTargetPortAnnotation = "tinylb.io/route-target-port"
+)
+
+// ProviderAnnotation names the controller responsible for a service's load balancer, so TinyLB can
+// share a cluster with another provider; services naming another provider are left alone. Used as
+// the opt-in annotation, only services naming TinyLB in it are reconciled.
+const ProviderAnnotation = "tinylb.io/provider"
+
 // ServiceReconciler reconciles a Service object
+// Service metadata is only written with merge patches carrying TinyLB's own annotations, and
+// status through the status subresource, so annotations and labels owned by others (such as
+// service.kubernetes.io/topology-mode) are never clobbered, even when written concurrently.
 type ServiceReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// Configuration
+	OptInAnnotation  string   // only services carrying this annotation are reconciled (empty = all); ProviderAnnotation must name TinyLB
+	BaseDomains      []string // wildcard domains routes are spread across, e.g. ["apps.example.com"]
+	RouterShards     int      // number of router shards to spread routes across (0 = no shard label)
+	ManagementPorts  []int32  // ports avoided during port selection (nil = DefaultManagementPorts)
+	PreferredPorts   []int32  // ports chosen first during port selection, in order (nil = 443/8443, then 80/8080)
+	PortNamePriority []string // port name hints tried in order when no port number matches (nil = DefaultPortNamePriority)
+	HostSuffix       string   // environment tag appended to the host label, e.g. "-dev"
+
+	HonorExternalDNSHostname bool                 // use the external-dns hostname annotation as the Route host when present
+	DefaultHostStrategy      string               // host strategy for services without tinylb.io/host-strategy (empty = template)
+	IngressMergeStrategy     IngressMergeStrategy // how the Route host is combined with existing ingress (empty = skip-if-present)
+	PreserveNodeIngress      bool                 // leave services whose ingress already advertises a node IP alone unless tinylb.io/force is set
+	CompanionServices        bool                 // route to a ClusterIP companion service mirroring the service's selector and ports
+
+	NamespaceFailureThreshold int           // consecutive Route creation failures before a namespace is backed off (0 = default)
+	NamespaceFailureBackoff   time.Duration // how long a failing namespace is backed off (0 = default)
+
+	EagerRouteCreation bool                 // create a placeholder Route serving 503s while the service has no ready endpoints
+	HostRegistry       types.NamespacedName // ConfigMap persisting service UID → host assignments (empty = disabled)
+	APIReader          client.Reader        // uncached reader finding a host registry created before it was labelled for the cache (nil = none)
+	DrainGracePeriod   time.Duration        // how long a tinylb.io/drain service keeps advertising its host (0 = default)
+	VerifyDNS          bool                 // look up advertised hosts and flag those that do not resolve
+	Resolver           HostResolver         // resolver used by VerifyDNS (nil = net.DefaultResolver)
+
+	MirrorServiceLabels   []string // service label keys, or prefixes ending in "/", copied onto the Route (nil = none)
+	MaxRoutesPerNamespace int      // managed Routes allowed per namespace before new services are refused one (0 = unlimited)
+	IgnoreStatusUpdates   bool     // skip reconciles for service updates that only change status
+	RouteBindings         bool     // maintain a RouteBinding per service recording its Route's state (needs the CRD)
+	DefaultRouteWeight    *int32   // backend weight of every Route unless tinylb.io/route-weight overrides it (nil = router default)
+
+	Debounce         time.Duration // delay before reconciling after an event, coalescing rapid updates of one service (0 = no delay)
+	RecordTargetPort bool          // record the Route's target port in the tinylb.io/route-target-port service annotation
+	ManageDNS        bool          // publish each admitted Route's host through an external-dns DNSEndpoint (needs the CRD)
+	RoutePortByName  bool          // reference the Route's target port by service port name whenever the port is named
+	ValidatePorts    bool          // skip port candidates without a ready endpoint in the service's EndpointSlices
+
+	WarnPlainHTTPPassthrough bool // emit a Warning when the selected port looks like plain HTTP, which passthrough Routes cannot serve
+	ZeroDowntimeHostChange   bool // move an existing Route to a changed host through a temporary Route instead of keeping the old host
+
+	MaxConcurrentReconciles int     // services reconciled in parallel (0 = 1)
+	RouteCreateQPS          float64 // Routes created per second across all services; services over budget are requeued (0 = unlimited)
+
+	statusForbidden    statusForbiddenReporter
+	namespaceBreaker   namespaceCircuitBreaker
+	hostClaims         hostClaims
+	routeCreateLimiter routeCreateLimiter
+	warnings           warningTracker
 }
 
-// selectHTTPPort selects the best port for HTTP/HTTPS traffic from a service's ports
-// Since we use passthrough TLS termination, we prioritize HTTPS ports
-func selectHTTPPort(ports []corev1.ServicePort) *corev1.ServicePort {
-	// Priority 1: Standard HTTPS ports (for passthrough mode)
-	for _, port := range ports {
-		if port.Port == 443 || port.Port == 8443 {
-			return &port
+// serviceEventFilter only admits LoadBalancer services, and when optInAnnotation is set,
+// only those carrying that annotation. Services of other types are admitted when they carry
+// tinylb.io annotations, and updates are admitted when either side would be, so a LoadBalancer
+// edited to another type reaches the reconciler for cleanup. Filtering at the watch level keeps
+// ClusterIP service churn from waking the reconciler at all.
+func serviceEventFilter(optInAnnotation string) predicate.Predicate {
+	admits := func(obj client.Object) bool {
+		service, ok := obj.(*corev1.Service)
+		if !ok {
+			return false
+		}
+		// Other types only matter when meant for TinyLB, or left with its annotations after a type change
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			return requestsTinyLB(service, optInAnnotation)
+		}
+		if optInAnnotation != "" {
+			if _, found := service.Annotations[optInAnnotation]; !found {
+				return false
+			}
 		}
+		return true
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return admits(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return admits(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return admits(e.Object) },
+		// The old object counts too, so a LoadBalancer edited to another type still gets cleaned up
+		UpdateFunc: func(e event.UpdateEvent) bool { return admits(e.ObjectOld) || admits(e.ObjectNew) },
+	}
+}
+
+// providerFilter drops services that name another provider in tinylb.io/provider; the opt-in
+// annotation is left to serviceEventFilter, which still admits services of other types for cleanup
+func providerFilter() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return providedByTinyLB(obj, "")
+	})
+}
+
+// providedByTinyLB reports whether TinyLB is the provider of an object's load balancer. Objects
+// naming another provider in tinylb.io/provider never are; with optInAnnotation set, only those
+// carrying it are, which with optInAnnotation tinylb.io/provider means naming TinyLB there.
+func providedByTinyLB(obj client.Object, optInAnnotation string) bool {
+	annotations := obj.GetAnnotations()
+	if provider, found := annotations[ProviderAnnotation]; found && provider != providerTinyLB {
+		return false
+	}
+	if optInAnnotation == "" {
+		return true
 	}
+	_, found := annotations[optInAnnotation]
+	return found
+}
 
-	// Priority 2: Standard HTTP ports (fallback)
-	for _, port := range ports {
-		if port.Port == 80 || port.Port == 8080 {
-			return &port
+// requestsTinyLB reports whether a service carries TinyLB's opt-in annotation or any tinylb.io
+// annotation, either set by its owner or left by TinyLB while the service was a LoadBalancer
+func requestsTinyLB(service *corev1.Service, optInAnnotation string) bool {
+	for key := range service.Annotations {
+		if key == optInAnnotation || strings.HasPrefix(key, "tinylb.io/") {
+			return true
 		}
 	}
+	return false
+}
+
+// specChangePredicate admits updates that change an object's spec, annotations or labels and drops
+// status-only updates, most of which TinyLB causes itself. Annotations and labels are kept because
+// they carry TinyLB's per-object settings but do not bump the generation.
+func specChangePredicate() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{})
+}
 
-	// Priority 3: Ports with "https" in the name
+// serviceSpecChangePredicate is specChangePredicate for Services, whose generation never changes:
+// the spec itself is compared instead
+func serviceSpecChangePredicate() predicate.Predicate {
+	specChanged := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldService, ok := e.ObjectOld.(*corev1.Service)
+			if !ok {
+				return true
+			}
+			newService, ok := e.ObjectNew.(*corev1.Service)
+			if !ok {
+				return true
+			}
+			return !equality.Semantic.DeepEqual(oldService.Spec, newService.Spec)
+		},
+	}
+	return predicate.Or(specChanged, predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{})
+}
+
+// selectBaseDomain picks the base domain for a service's route host
+// A previously persisted assignment is kept as long as the domain is still configured,
+// otherwise the domain is chosen deterministically from a hash of the service UID
+func selectBaseDomain(service *corev1.Service, domains []string) string {
+	if len(domains) == 0 {
+		return DefaultBaseDomain
+	}
+
+	if domain, ok := service.Annotations[baseDomainAnnotation]; ok && slices.Contains(domains, domain) {
+		return domain
+	}
+
+	return domains[hashString(string(service.UID))%uint32(len(domains))]
+}
+
+// routerShard returns the router shard label value for a service, or "" when sharding is disabled
+// The shard is derived from the service's namespace and name so it survives service re-creation
+func routerShard(service *corev1.Service, shards int) string {
+	if shards <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("shard-%d", hashString(service.Namespace+"/"+service.Name)%uint32(shards))
+}
+
+// hashString returns a stable 32-bit FNV-1a hash of value
+func hashString(value string) uint32 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(value))
+	return hash.Sum32()
+}
+
+// externalDNSHostname returns the first hostname from the service's external-dns annotation, if any
+func externalDNSHostname(service *corev1.Service) string {
+	value := service.Annotations[externalDNSHostnameAnnotation]
+	if value == "" {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimSpace(strings.Split(value, ",")[0]), ".")
+}
+
+// checkHostAvailable returns an ErrHostConflict error if a Route for another service already uses host
+func (r *ServiceReconciler) checkHostAvailable(ctx context.Context, service *corev1.Service, host string) error {
+	var routes routev1.RouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return err
+	}
+	for _, route := range routes.Items {
+		if route.Spec.Host != host {
+			continue
+		}
+		if routeTargetsService(&route, service) {
+			continue
+		}
+		return fmt.Errorf("%w: %s is used by Route %s/%s", ErrHostConflict, host, route.Namespace, route.Name)
+	}
+	return nil
+}
+
+// routeHost builds the Route host for a service under baseDomain with the service's host strategy.
+// explicit reports whether the host was requested through the external-dns hostname annotation
+func (r *ServiceReconciler) routeHost(service *corev1.Service, baseDomain string) (host string, explicit bool, err error) {
+	strategy := r.hostStrategy(service, baseDomain)
+	host, err = strategy.Host(service)
+	if err != nil {
+		return "", false, err
+	}
+	_, annotated := strategy.(AnnotationHostStrategy)
+	explicit = annotated && externalDNSHostname(service) != ""
+
+	// Annotated hosts are user input and may use uppercase or characters Routes reject
+	if sanitized := sanitizeHost(host); sanitized != host {
+		r.warnings.warnf(r.Recorder, service, "HostSanitized", "HostSanitized", "Route host %q was sanitized to %q", host, sanitized)
+		host = sanitized
+	} else {
+		r.warnings.resolve(service, "HostSanitized")
+	}
+	if err := validateHost(host); err != nil {
+		return "", false, err
+	}
+	return host, explicit, nil
+}
+
+// RouteHost returns the Route host the reconciler generates for a service: the host its strategy
+// builds under the base domain assigned to the service, with the host suffix and any external-dns
+// hostname applied and sanitized like the Route's. A host already recorded in the host registry
+// wins over it at runtime. Problems with the service's annotations are reported through Recorder,
+// as during a reconcile.
+func (r *ServiceReconciler) RouteHost(service *corev1.Service) (string, error) {
+	host, _, err := r.routeHost(service, selectBaseDomain(service, r.BaseDomains))
+	return host, err
+}
+
+// recordRouteCreateFailure counts a failed Route creation against the service's namespace and
+// warns once when the namespace gets backed off
+func (r *ServiceReconciler) recordRouteCreateFailure(service *corev1.Service) {
+	threshold := r.NamespaceFailureThreshold
+	if threshold <= 0 {
+		threshold = DefaultNamespaceFailureThreshold
+	}
+	backoff := r.NamespaceFailureBackoff
+	if backoff <= 0 {
+		backoff = DefaultNamespaceFailureBackoff
+	}
+
+	if r.namespaceBreaker.recordFailure(service.Namespace, threshold, backoff, time.Now()) {
+		r.Recorder.Eventf(service, corev1.EventTypeWarning, "NamespaceBackoff",
+			"Route creation in namespace %s failed %d times in a row; backing off for %s", service.Namespace, threshold, backoff)
+	}
+}
+
+// withdrawHost stops advertising hostname in the service's status while its Route is missing and
+// cannot be recreated right away, so clients are not pointed at a host the router no longer serves.
+// The entry comes back once the Route is recreated.
+func (r *ServiceReconciler) withdrawHost(ctx context.Context, service *corev1.Service, hostname string) error {
+	ingress := slices.DeleteFunc(slices.Clone(service.Status.LoadBalancer.Ingress), func(entry corev1.LoadBalancerIngress) bool {
+		return entry.Hostname == hostname
+	})
+	if equality.Semantic.DeepEqual(ingress, service.Status.LoadBalancer.Ingress) {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	serviceCopy := service.DeepCopy()
+	serviceCopy.Status.LoadBalancer.Ingress = ingress
+	if err := r.Status().Update(ctx, serviceCopy); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Route is missing, stopped advertising host", "service", service.Name, "hostname", hostname)
+	r.Recorder.Eventf(service, corev1.EventTypeWarning, "RouteMissing",
+		"Stopped advertising %s until its Route can be recreated", hostname)
+	return nil
+}
+
+// recordTargetPort records the target port of a service's Route in the tinylb.io/route-target-port
+// annotation, with an event whenever it changes. Routes without a port record nothing.
+func (r *ServiceReconciler) recordTargetPort(ctx context.Context, service *corev1.Service, route *routev1.Route) error {
+	targetPort := ""
+	if route.Spec.Port != nil {
+		targetPort = route.Spec.Port.TargetPort.String()
+	}
+	recorded, ok := service.Annotations[routeTargetPortAnnotation]
+	if recorded == targetPort && ok == (targetPort != "") {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(service.DeepCopy())
+	if targetPort == "" {
+		delete(service.Annotations, routeTargetPortAnnotation)
+	} else {
+		if service.Annotations == nil {
+			service.Annotations = map[string]string{}
+		}
+		service.Annotations[routeTargetPortAnnotation] = targetPort
+	}
+	if err := r.Patch(ctx, service, patch); err != nil {
+		return err
+	}
+	if targetPort != "" {
+		r.Recorder.Eventf(service, corev1.EventTypeNormal, "RouteTargetPort", "Route %s targets service port %s", route.Name, targetPort)
+	}
+	return nil
+}
+
+// handOff deletes TinyLB's Route for a service whose ingress another provider has taken over,
+// and marks the service so the Route is not recreated while that provider stays in charge
+func (r *ServiceReconciler) handOff(ctx context.Context, service *corev1.Service, route *routev1.Route) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if route.Labels[managedLabel] == "true" && route.Labels[serviceUIDLabel] == string(service.UID) {
+		if err := r.Delete(ctx, route); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	patch := client.MergeFrom(service.DeepCopy())
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[handedOffAnnotation] = "true"
+	if err := r.Patch(ctx, service, patch); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Another provider took over the service's ingress, removed Route", "service", service.Name, "route", route.Name)
+	r.Recorder.Eventf(service, corev1.EventTypeNormal, "HandedOff",
+		"Ingress is now provided by another LoadBalancer implementation; removed Route %s (set %s=true to keep it)", route.Name, forceAnnotation)
+	return nil
+}
+
+// clearHandOff removes the hand-off mark once the other provider's ingress entries are gone
+func (r *ServiceReconciler) clearHandOff(ctx context.Context, service *corev1.Service) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(service.DeepCopy())
+	delete(service.Annotations, handedOffAnnotation)
+	log.FromContext(ctx).Info("Foreign ingress is gone, resuming management of service", "service", service.Name)
+	return r.Patch(ctx, service, patch)
+}
+
+// syncRouteWeight brings the backend weight of an existing Route in line with weight once the
+// service's backend is ready. Without a configured weight only a placeholder Route left by eager
+// route creation is changed, getting the router's default traffic share.
+func (r *ServiceReconciler) syncRouteWeight(ctx context.Context, route *routev1.Route, weight *int32) error {
+	current := route.Spec.To.Weight
+	if weight == nil {
+		if current == nil || *current != placeholderRouteWeight {
+			return nil
+		}
+		weight = ptr.To(defaultRouteWeight)
+	}
+	if current != nil && *current == *weight {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(route.DeepCopy())
+	route.Spec.To.Weight = weight
+	log.FromContext(ctx).Info("Updating Route backend weight", "route", route.Name, "weight", *weight)
+	return r.Patch(ctx, route, patch)
+}
+
+// isPlainHTTP reports whether a service port looks like it serves cleartext HTTP: its appProtocol
+// says http, or it has none and is port 80 or 8080 without "https" in its name
+func isPlainHTTP(port corev1.ServicePort) bool {
+	if port.AppProtocol != nil {
+		return strings.EqualFold(*port.AppProtocol, "http")
+	}
+	return (port.Port == 80 || port.Port == 8080) && !strings.Contains(strings.ToLower(port.Name), "https")
+}
+
+// isH2C reports whether a service port declares cleartext HTTP/2 through its appProtocol
+func isH2C(port corev1.ServicePort) bool {
+	return port.AppProtocol != nil && *port.AppProtocol == h2cAppProtocol
+}
+
+// syncRouteTLS switches an existing Route between passthrough and plain HTTP when the selected
+// port starts or stops declaring h2c. Other TLS settings are left as they are.
+func (r *ServiceReconciler) syncRouteTLS(ctx context.Context, route *routev1.Route, tls *routev1.TLSConfig) error {
+	if (route.Spec.TLS == nil) == (tls == nil) {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(route.DeepCopy())
+	route.Spec.TLS = tls
+	log.FromContext(ctx).Info("Updating Route TLS", "route", route.Name, "plainHTTP", tls == nil)
+	return r.Patch(ctx, route, patch)
+}
+
+// ValidateRouteWeight checks that weight is a backend weight the router accepts (0-256)
+func ValidateRouteWeight(weight int) error {
+	if weight < 0 || weight > maxRouteWeight {
+		return fmt.Errorf("route weight %d is outside 0-%d", weight, maxRouteWeight)
+	}
+	return nil
+}
+
+// ParsePortList parses a comma-separated list of port numbers such as "9000,9001"
+func ParsePortList(value string) ([]int32, error) {
+	var ports []int32
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		port, err := strconv.ParseInt(item, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q", item)
+		}
+		ports = append(ports, int32(port))
+	}
+	return ports, nil
+}
+
+// FormatPortList formats ports as the comma-separated list ParsePortList reads
+func FormatPortList(ports []int32) string {
+	items := make([]string, len(ports))
+	for i, port := range ports {
+		items[i] = strconv.Itoa(int(port))
+	}
+	return strings.Join(items, ",")
+}
+
+// skippedPorts merges the global management ports with the service's tinylb.io/skip-ports annotation
+func (r *ServiceReconciler) skippedPorts(service *corev1.Service) []int32 {
+	skip := r.ManagementPorts
+	if skip == nil {
+		skip = DefaultManagementPorts
+	}
+
+	value, ok := service.Annotations[skipPortsAnnotation]
+	if !ok {
+		r.warnings.resolve(service, skipPortsAnnotation)
+		return skip
+	}
+	extra, err := ParsePortList(value)
+	if err != nil {
+		r.warnings.warnf(r.Recorder, service, skipPortsAnnotation, "InvalidAnnotation",
+			"Ignoring %s annotation: %v", skipPortsAnnotation, err)
+		return skip
+	}
+	r.warnings.resolve(service, skipPortsAnnotation)
+	return append(slices.Clone(skip), extra...)
+}
+
+// tcpPorts returns the service ports carrying TCP, the only protocol a Route can front
+// An empty protocol defaults to TCP
+func tcpPorts(ports []corev1.ServicePort) []corev1.ServicePort {
+	var tcp []corev1.ServicePort
 	for _, port := range ports {
-		if strings.Contains(strings.ToLower(port.Name), "https") {
-			return &port
+		if port.Protocol == "" || port.Protocol == corev1.ProtocolTCP {
+			tcp = append(tcp, port)
 		}
 	}
+	return tcp
+}
 
-	// Priority 4: Ports with "http" in the name
+// uniquePorts drops ports repeating an earlier port's number, so a misconfigured service listing
+// one number under several names always resolves to its first entry. It also returns the
+// repeated numbers.
+func uniquePorts(ports []corev1.ServicePort) ([]corev1.ServicePort, []int32) {
+	var unique []corev1.ServicePort
+	var duplicates []int32
+	seen := map[int32]bool{}
 	for _, port := range ports {
-		if strings.Contains(strings.ToLower(port.Name), "http") {
-			return &port
+		if seen[port.Port] {
+			if !slices.Contains(duplicates, port.Port) {
+				duplicates = append(duplicates, port.Port)
+			}
+			continue
+		}
+		seen[port.Port] = true
+		unique = append(unique, port)
+	}
+	return unique, duplicates
+}
+
+// defaultPortTiers are the port-number tiers tried before the name hints when no preferred ports
+// are configured: standard HTTPS ports (for passthrough mode), then standard HTTP ports
+var defaultPortTiers = [][]int32{{443, 8443}, {80, 8080}}
+
+// DefaultPortNamePriority are the port name hints tried, in order, when no port number tier matches
+var DefaultPortNamePriority = []string{"https", "http"}
+
+// selectHTTPPort selects the best port for HTTP/HTTPS traffic from a service's ports
+// Since we use passthrough TLS termination, we prioritize HTTPS ports; only TCP ports are considered.
+// preferred, when set, replaces the standard port-number tiers with one tier per port, in order.
+// namePriority (nil = DefaultPortNamePriority) lists the name hints tried next: the first port
+// whose name contains a hint wins, earlier hints first.
+func selectHTTPPort(ports []corev1.ServicePort, preferred []int32, namePriority []string, skipPorts []int32) *corev1.ServicePort {
+	candidates := rankHTTPPorts(ports, preferred, namePriority, skipPorts)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return &candidates[0]
+}
+
+// rankHTTPPorts orders a service's TCP ports by the priorities selectHTTPPort applies, each port
+// listed once at its best priority, so callers can fall back to the next candidate
+func rankHTTPPorts(ports []corev1.ServicePort, preferred []int32, namePriority []string, skipPorts []int32) []corev1.ServicePort {
+	ports, _ = uniquePorts(tcpPorts(ports))
+	ranked := make([]corev1.ServicePort, 0, len(ports))
+	rank := func(port corev1.ServicePort) {
+		if !slices.ContainsFunc(ranked, func(p corev1.ServicePort) bool { return p.Port == port.Port }) {
+			ranked = append(ranked, port)
+		}
+	}
+
+	tiers := defaultPortTiers
+	if preferred != nil {
+		tiers = nil
+		for _, number := range preferred {
+			tiers = append(tiers, []int32{number})
+		}
+	}
+
+	// Priority 1-2: Preferred port numbers, by default 443/8443 then 80/8080
+	for _, tier := range tiers {
+		for _, port := range ports {
+			if slices.Contains(tier, port.Port) {
+				rank(port)
+			}
+		}
+	}
+
+	// Priority 3-4: Ports named after a hint, by default "https" then "http"
+	if namePriority == nil {
+		namePriority = DefaultPortNamePriority
+	}
+	for _, hint := range namePriority {
+		for _, port := range ports {
+			if strings.Contains(strings.ToLower(port.Name), strings.ToLower(hint)) {
+				rank(port)
+			}
 		}
 	}
 
 	// Priority 5: Avoid known management/status ports
 	for _, port := range ports {
-		// Skip common management ports
-		if port.Port == 15021 || port.Port == 15090 || port.Port == 9090 || port.Port == 8181 {
+		// Skip management ports and any the service asked us to avoid
+		if slices.Contains(skipPorts, port.Port) {
 			continue
 		}
-		return &port
+		rank(port)
+	}
+
+	// Fallback: the skipped ports, in spec order
+	for _, port := range ports {
+		rank(port)
+	}
+
+	return ranked
+}
+
+// routeTargetPort returns the Route target port for a service port. When the service port forwards
+// to a named container port, the Route references the service port by name: the router then
+// resolves it through the endpoints on every change, so moving the container port needs no Route
+// update. With byName, every named service port is referenced by name, which also survives the
+// service renumbering its ports. Unnamed service ports have unnamed endpoint ports and can only be
+// referenced by number.
+func routeTargetPort(port corev1.ServicePort, byName bool) intstr.IntOrString {
+	if (byName || port.TargetPort.Type == intstr.String) && port.Name != "" {
+		return intstr.FromString(port.Name)
+	}
+	return intstr.FromInt(int(port.Port))
+}
+
+// removeDuplicateRoutes deletes extra managed Routes labeled for the same service UID
+// The canonically named Route is kept; if it is missing, the oldest Route is kept instead
+func (r *ServiceReconciler) removeDuplicateRoutes(ctx context.Context, service *corev1.Service) error {
+	logger := log.FromContext(ctx)
+
+	var routes routev1.RouteList
+	if err := r.List(ctx, &routes, client.InNamespace(service.Namespace), client.MatchingLabels{
+		managedLabel:    "true",
+		serviceUIDLabel: string(service.UID),
+	}); err != nil {
+		return err
+	}
+	// The temporary Route of a host migration is not a duplicate while migrations are enabled
+	if r.ZeroDowntimeHostChange {
+		routes.Items = slices.DeleteFunc(routes.Items, func(route routev1.Route) bool { return isMigrationRoute(&route) })
+	}
+	if len(routes.Items) <= 1 {
+		return nil
 	}
 
-	// Fallback: return first port if nothing else matches
-	if len(ports) > 0 {
-		return &ports[0]
+	keep := slices.MinFunc(routes.Items, func(a, b routev1.Route) int {
+		return cmp.Or(a.CreationTimestamp.Compare(b.CreationTimestamp.Time), cmp.Compare(a.Name, b.Name))
+	}).Name
+	if slices.ContainsFunc(routes.Items, func(route routev1.Route) bool { return route.Name == RouteName(service) }) {
+		keep = RouteName(service)
 	}
 
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if route.Name == keep {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		logger.Info("Deleting duplicate Route", "service", service.Name, "route", route.Name, "kept", keep)
+		if err := r.Delete(ctx, route); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.Recorder.Eventf(service, corev1.EventTypeWarning, "DuplicateRoutes",
+			"Deleted duplicate Route %s, keeping %s", route.Name, keep)
+	}
 	return nil
 }
 
+// findEquivalentRoute returns a differently named managed Route with the same host and target
+// service as desired, or nil if there is none
+func (r *ServiceReconciler) findEquivalentRoute(ctx context.Context, desired *routev1.Route) (*routev1.Route, error) {
+	var routes routev1.RouteList
+	if err := r.List(ctx, &routes, client.InNamespace(desired.Namespace), client.MatchingLabels{managedLabel: "true"}); err != nil {
+		return nil, err
+	}
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if route.Spec.Host == desired.Spec.Host && route.Spec.To.Kind == desired.Spec.To.Kind && route.Spec.To.Name == desired.Spec.To.Name {
+			return route, nil
+		}
+	}
+	return nil, nil
+}
+
+// routeQuotaExceeded reports whether the namespace already holds MaxRoutesPerNamespace managed Routes
+func (r *ServiceReconciler) routeQuotaExceeded(ctx context.Context, namespace string) (bool, error) {
+	if r.MaxRoutesPerNamespace <= 0 {
+		return false, nil
+	}
+	var routes routev1.RouteList
+	if err := r.List(ctx, &routes, client.InNamespace(namespace), client.MatchingLabels{managedLabel: "true"}); err != nil {
+		return false, err
+	}
+	return len(routes.Items) >= r.MaxRoutesPerNamespace, nil
+}
+
+// restoreOwnerReference re-adds the service owner reference to its managed Route when a tool
+// (e.g. a GitOps sync) has stripped it, so garbage collection still removes the Route
+// The Route is only adopted when its labels show it was created by TinyLB for this exact service
+func (r *ServiceReconciler) restoreOwnerReference(ctx context.Context, service *corev1.Service) error {
+	var route routev1.Route
+	if err := r.Get(ctx, types.NamespacedName{Name: RouteName(service), Namespace: service.Namespace}, &route); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if route.Labels[managedLabel] != "true" || route.Labels[serviceUIDLabel] != string(service.UID) {
+		return nil
+	}
+	for _, ref := range route.OwnerReferences {
+		if ref.UID == service.UID {
+			return nil
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(route.DeepCopy())
+	if err := controllerutil.SetOwnerReference(service, &route, r.Scheme); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Restoring owner reference on Route", "route", route.Name, "service", service.Name)
+	return r.Patch(ctx, &route, patch)
+}
+
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=update
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.tinylb.io,resources=routebindings,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=networking.tinylb.io,resources=routebindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints,verbs=get;list;watch;create;update
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startSpan(ctx, "ServiceReconciler.Reconcile",
+		attribute.String("k8s.namespace", req.Namespace),
+		attribute.String("k8s.name", req.Name))
+	defer func() { endSpan(span, err) }()
+	defer trackInFlight("service")()
+	defer func() { err = classifyError(err) }()
+
 	logger := log.FromContext(ctx)
 
 	// Get the service
@@ -105,41 +844,233 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err := r.Get(ctx, req.NamespacedName, &service); err != nil {
 		if errors.IsNotFound(err) {
 			// Service was deleted, cleanup will be handled by owner references
+			r.hostClaims.release(req.NamespacedName)
+			r.warnings.forget(req.NamespacedName)
+			if err := r.pruneHostRegistry(ctx); err != nil {
+				logger.Error(err, "Unable to prune host registry")
+				return ctrl.Result{}, err
+			}
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Unable to fetch Service")
 		return ctrl.Result{}, err
 	}
 
-	// Only process LoadBalancer services
+	// Route events reach the service regardless of the watch filters
+	if !providedByTinyLB(&service, r.OptInAnnotation) {
+		logger.Info("Service is handled by another provider or not opted in, skipping", "service", service.Name,
+			"provider", service.Annotations[ProviderAnnotation], "optInAnnotation", r.OptInAnnotation)
+		return ctrl.Result{}, nil
+	}
+
+	// A service edited away from LoadBalancer leaves its Route and advertised host behind. Clean up
+	// before its type's handler runs, which may warn about the new type
 	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		if err := r.unexpose(ctx, &service); err != nil {
+			logger.Error(err, "Unable to clean up after service type change", "type", service.Spec.Type)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Dispatch on the service type; only types whose handler asks for a Route go further
+	typeHandler, ok := serviceTypeHandlers[service.Spec.Type]
+	if !ok || typeHandler(r, ctx, &service) != exposureRoute {
 		return ctrl.Result{}, nil
 	}
 
-	// Check if service already has an external IP
-	if len(service.Status.LoadBalancer.Ingress) > 0 {
-		// Service already has an external IP, nothing to do
+	// Back off namespaces where Route creation keeps failing
+	if wait := r.namespaceBreaker.remaining(service.Namespace, time.Now()); wait > 0 {
+		logger.Info("Namespace is backed off after repeated Route creation failures", "namespace", service.Namespace, "retryAfter", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	// Self-heal any duplicate Routes left behind for this service
+	if err := r.removeDuplicateRoutes(ctx, &service); err != nil {
+		logger.Error(err, "Unable to remove duplicate Routes")
+		return ctrl.Result{}, err
+	}
+
+	// Re-link the Route for garbage collection if its owner reference was stripped
+	if err := r.restoreOwnerReference(ctx, &service); err != nil {
+		logger.Error(err, "Unable to restore owner reference on Route")
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Processing LoadBalancer service", "service", service.Name)
+
+	// Assign a base domain and persist the choice so the host stays stable
+	baseDomain := selectBaseDomain(&service, r.BaseDomains)
+	if service.Annotations[baseDomainAnnotation] != baseDomain {
+		if err := ctx.Err(); err != nil {
+			return ctrl.Result{}, err
+		}
+		patch := client.MergeFrom(service.DeepCopy())
+		if service.Annotations == nil {
+			service.Annotations = map[string]string{}
+		}
+		service.Annotations[baseDomainAnnotation] = baseDomain
+		if err := r.Patch(ctx, &service, patch); err != nil {
+			logger.Error(err, "Unable to record base domain on Service")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Assigned base domain to service", "service", service.Name, "baseDomain", baseDomain)
+	}
+
+	host, explicitHost, err := r.routeHost(&service, baseDomain)
+	if err != nil {
+		logger.Error(err, "Unable to build Route host", "service", service.Name)
+		r.Recorder.Event(&service, corev1.EventTypeWarning, "InvalidHost", err.Error())
 		return ctrl.Result{}, nil
 	}
 
-	logger.Info("Processing LoadBalancer service without external IP", "service", service.Name)
+	// A host recorded in the registry outlives annotation loss and configuration changes
+	registered, err := r.registeredHost(ctx, &service)
+	if err != nil {
+		logger.Error(err, "Unable to read host registry")
+		return ctrl.Result{}, err
+	}
+	if registered != "" {
+		host = registered
+	}
+
+	// A user-chosen host may already be taken by another service's Route
+	if explicitHost {
+		if err := r.checkHostAvailable(ctx, &service, host); err != nil {
+			if !stderrors.Is(err, ErrHostConflict) {
+				logger.Error(err, "Unable to check Route host for conflicts")
+				return ctrl.Result{}, err
+			}
+			// Conflicts resolve when the other Route goes away; poll instead of backing off exponentially
+			logger.Info("Route host is already in use, not creating Route", "service", service.Name, "host", host, "reason", err.Error())
+			r.warnings.warnf(r.Recorder, &service, "HostConflict", "HostConflict",
+				"Host from %s cannot be used: %v", externalDNSHostnameAnnotation, err)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+	}
+
+	// The host TinyLB already advertises may predate a configuration change, so an existing Route
+	// wins, unless host changes are migrated to a new Route first
+	var existingRoute routev1.Route
+	if err := r.Get(ctx, types.NamespacedName{Name: RouteName(&service), Namespace: service.Namespace}, &existingRoute); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Unable to get Route")
+			return ctrl.Result{}, err
+		}
+	} else if r.ZeroDowntimeHostChange && existingRoute.Spec.Host != "" && existingRoute.Spec.Host != host {
+		if _, ok := r.hostClaims.claim(req.NamespacedName, host); !ok {
+			r.warnings.warnf(r.Recorder, &service, "HostConflict", "HostConflict", "Host %s cannot be used: it is being assigned to another service", host)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+		r.warnings.resolve(&service, "HostConflict")
+		result, err := r.migrateHost(ctx, &service, &existingRoute, host)
+		if err != nil {
+			logger.Error(err, "Unable to migrate Route host", "from", existingRoute.Spec.Host, "to", host)
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	} else if existingRoute.Spec.Host != "" {
+		host = existingRoute.Spec.Host
+		if r.ZeroDowntimeHostChange {
+			result, done, err := r.settleHostMigration(ctx, &service, &existingRoute)
+			if err != nil {
+				logger.Error(err, "Unable to settle host migration")
+				return ctrl.Result{}, err
+			}
+			if !done {
+				return result, nil
+			}
+		}
+	}
+
+	// Concurrent reconciles must not hand one host to two services. An existing Route keeps its
+	// host and only records the claim
+	if existingRoute.Name != "" {
+		r.hostClaims.claim(req.NamespacedName, host)
+	} else {
+		claimed, err := r.claimHost(req.NamespacedName, host, explicitHost || registered != "")
+		if err != nil {
+			logger.Info("Route host is being assigned to another service, not creating Route", "service", service.Name, "host", host, "reason", err.Error())
+			r.warnings.warnf(r.Recorder, &service, "HostConflict", "HostConflict", "Host %s cannot be used: %v", host, err)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+		host = claimed
+	}
+	r.warnings.resolve(&service, "HostConflict")
+
+	// Once another provider takes over the ingress, TinyLB's Route is no longer needed
+	draining := service.Annotations[drainAnnotation] == "true"
+	if service.Annotations[forceAnnotation] != "true" && r.ingressMergeStrategy(&service) != IngressMergeAppend && !draining {
+		ingress := service.Status.LoadBalancer.Ingress
+		if existingRoute.Name != "" && ingressTakenOver(ingress, host) {
+			return ctrl.Result{}, r.handOff(ctx, &service, &existingRoute)
+		}
+		if service.Annotations[handedOffAnnotation] == "true" {
+			if hasForeignIngress(ingress, host) {
+				logger.Info("Service was handed off to another provider, skipping", "service", service.Name)
+				return ctrl.Result{}, nil
+			}
+			if err := r.clearHandOff(ctx, &service); err != nil {
+				logger.Error(err, "Unable to clear hand-off annotation")
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	// Bare-metal services already reached on a node IP keep that ingress unless forced
+	if r.PreserveNodeIngress && service.Annotations[forceAnnotation] != "true" && len(service.Status.LoadBalancer.Ingress) > 0 {
+		nodeIPs, err := r.nodeIPs(ctx)
+		if err != nil {
+			logger.Error(err, "Unable to list nodes")
+			return ctrl.Result{}, err
+		}
+		if hasNodeIngress(service.Status.LoadBalancer.Ingress, nodeIPs) {
+			logger.Info("Service ingress already advertises a node IP, skipping", "service", service.Name)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Leave services advertised by another provider alone when configured to
+	if r.ingressMergeStrategy(&service) == IngressMergeSkipIfPresent && hasForeignIngress(service.Status.LoadBalancer.Ingress, host) {
+		logger.Info("Service already has ingress from another provider, skipping", "service", service.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// Some meshes need the Route to target a plain ClusterIP service instead of the LoadBalancer
+	target := service.Name
+	if r.CompanionServices {
+		if target, err = r.syncCompanionService(ctx, &service); err != nil {
+			// Pointing the Route at someone else's service would be worse than having none
+			if stderrors.Is(err, ErrCompanionConflict) {
+				logger.Info("Companion service name is taken, not creating Route", "service", service.Name, "reason", err.Error())
+				r.warnings.warnf(r.Recorder, &service, "CompanionConflict", "CompanionConflict",
+					"Service %s already exists and was not created by TinyLB; rename it or disable companion services", companionServiceName(&service))
+				return ctrl.Result{RequeueAfter: time.Minute}, nil
+			}
+			logger.Error(err, "Unable to sync companion service")
+			return ctrl.Result{}, err
+		}
+	} else if err := r.deleteCompanionService(ctx, &service); err != nil {
+		logger.Error(err, "Unable to delete companion service")
+		return ctrl.Result{}, err
+	}
+	r.warnings.resolve(&service, "CompanionConflict")
 
 	// Create or update the OpenShift Route
 	route := &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("tinylb-%s", service.Name),
+			Name:      RouteName(&service),
 			Namespace: service.Namespace,
 			Labels: map[string]string{
-				"tinylb.io/managed":     "true",
-				"tinylb.io/service":     service.Name,
-				"tinylb.io/service-uid": string(service.UID),
+				managedLabel:    "true",
+				serviceLabel:    service.Name,
+				serviceUIDLabel: string(service.UID),
 			},
 		},
 		Spec: routev1.RouteSpec{
-			Host: fmt.Sprintf("%s-%s.apps-crc.testing", service.Name, service.Namespace),
+			Host: host,
 			To: routev1.RouteTargetReference{
 				Kind: "Service",
-				Name: service.Name,
+				Name: target,
 			},
 			TLS: &routev1.TLSConfig{
 				Termination: routev1.TLSTerminationPassthrough,
@@ -147,64 +1078,293 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		},
 	}
 
-	// Set the service port if specified
+	// Apply the configured traffic share, if any
+	weight := r.routeWeight(&service)
+	route.Spec.To.Weight = weight
+
+	// Until the backend is ready, an eager Route carries no traffic share, so the router answers 503
+	backendReady := true
+	if r.EagerRouteCreation {
+		if backendReady, err = serviceHasReadyEndpoints(ctx, r.Client, &service); err != nil {
+			logger.Error(err, "Unable to list EndpointSlices")
+			return ctrl.Result{}, err
+		}
+		if !backendReady {
+			route.Spec.To.Weight = ptr.To(placeholderRouteWeight)
+		}
+	}
+
+	// Carry over router settings requested through service annotations
+	route.Annotations = r.routeAnnotations(&service)
+
+	// The wildcard policy of a Route is immutable, so it only takes effect when the Route is created
+	route.Spec.WildcardPolicy = r.wildcardPolicy(&service, host)
+
+	// Copy selected service labels so tooling can correlate the Route with its service, the
+	// router shard the service asks for so that shard's route selector picks the Route up, and
+	// the hash-derived router shard
+	mirrored := r.mirroredLabels(&service)
+	if shard := r.requestedRouterShard(&service); shard != "" {
+		if mirrored == nil {
+			mirrored = map[string]string{}
+		}
+		mirrored[routerShardSelectionLabel] = shard
+	}
+	if shard := routerShard(&service, r.RouterShards); shard != "" {
+		if mirrored == nil {
+			mirrored = map[string]string{}
+		}
+		mirrored[routerShardLabel] = shard
+	}
+	for key, value := range mirrored {
+		route.Labels[key] = value
+	}
+	markLabels(route)
+
+	// Set the service port if specified. The router reaches the service through its endpoints, so
+	// node ports are never used and services with allocateLoadBalancerNodePorts: false work as is
 	if len(service.Spec.Ports) > 0 {
+		// Repeated port numbers resolve to their first entry; flag the misconfiguration
+		if _, duplicates := uniquePorts(tcpPorts(service.Spec.Ports)); len(duplicates) > 0 {
+			r.warnings.warnf(r.Recorder, &service, "DuplicatePorts", "DuplicatePorts",
+				"Service lists port numbers %v more than once; only the first entry of each is considered", duplicates)
+		} else {
+			r.warnings.resolve(&service, "DuplicatePorts")
+		}
+
 		// Select the best HTTP port for the route
-		port := selectHTTPPort(service.Spec.Ports)
+		port, err := r.selectRoutePort(ctx, &service)
+		if err != nil {
+			logger.Error(err, "Unable to list EndpointSlices")
+			return ctrl.Result{}, err
+		}
 		if port != nil {
+			if r.RoutePortByName && port.Name == "" {
+				r.warnings.warnf(r.Recorder, &service, "UnnamedPort", "UnnamedPort",
+					"Service port %d has no name; the Route references it by number", port.Port)
+			} else {
+				r.warnings.resolve(&service, "UnnamedPort")
+			}
 			route.Spec.Port = &routev1.RoutePort{
-				TargetPort: intstr.FromInt(int(port.Port)),
+				TargetPort: routeTargetPort(*port, r.RoutePortByName),
 			}
 			logger.Info("Selected port for Route", "service", service.Name, "port", port.Port, "portName", port.Name)
+
+			// A passthrough Route hands the client's TLS stream to a backend that cannot speak it
+			if r.WarnPlainHTTPPassthrough && !isH2C(*port) && isPlainHTTP(*port) {
+				r.warnings.warnf(r.Recorder, &service, "PlainHTTPPassthrough", "PlainHTTPPassthrough",
+					"Port %d looks like plain HTTP but the Route uses passthrough TLS, so clients reaching it over TLS will fail; "+
+						"expose an HTTPS port, or terminate TLS at the router (edge) instead", port.Port)
+			} else {
+				r.warnings.resolve(&service, "PlainHTTPPassthrough")
+			}
+
+			// Cleartext HTTP/2 backends get a plain HTTP Route, with HTTP/2 on unless the service says otherwise
+			if isH2C(*port) {
+				route.Spec.TLS = nil
+				if _, ok := service.Annotations[http2Annotation]; !ok {
+					if route.Annotations == nil {
+						route.Annotations = map[string]string{}
+					}
+					route.Annotations[disableHTTP2RouteAnnotation] = "false"
+				}
+			}
+		}
+	}
+
+	// Paths can only be rewritten once the Route's TLS settings are known
+	if target := r.rewriteTarget(&service, route.Spec.TLS); target != "" {
+		if route.Annotations == nil {
+			route.Annotations = map[string]string{}
 		}
+		route.Annotations[rewriteTargetRouteAnnotation] = target
 	}
 
+	// Record which router annotations TinyLB set, so it never removes ones set by hand later
+	claimRouteAnnotations(route)
+
 	// Set owner reference so route is cleaned up when service is deleted
 	if err := controllerutil.SetOwnerReference(&service, route, r.Scheme); err != nil {
 		logger.Error(err, "Unable to set owner reference on Route")
 		return ctrl.Result{}, err
 	}
 
-	// Create or update the route
-	if err := r.Get(ctx, types.NamespacedName{Name: route.Name, Namespace: route.Namespace}, &routev1.Route{}); err != nil {
-		if errors.IsNotFound(err) {
+	// Create the route unless it (or an equivalent one) already exists
+	current := &existingRoute
+	if existingRoute.Name == "" {
+		// A Route from an earlier naming scheme may already serve this exact host and target
+		existing, err := r.findEquivalentRoute(ctx, route)
+		if err != nil {
+			logger.Error(err, "Unable to list managed Routes")
+			return ctrl.Result{}, err
+		}
+		if existing != nil {
+			logger.Info("Reusing existing equivalent Route", "route", existing.Name, "service", service.Name)
+		} else {
+			exceeded, err := r.routeQuotaExceeded(ctx, service.Namespace)
+			if err != nil {
+				logger.Error(err, "Unable to count managed Routes")
+				return ctrl.Result{}, err
+			}
+			if exceeded {
+				// Capacity frees up when another service's Route goes away; poll for it
+				logger.Info("Namespace has reached its Route limit, not creating Route", "service", service.Name, "limit", r.MaxRoutesPerNamespace)
+				r.warnings.warnf(r.Recorder, &service, "RouteQuotaExceeded", "RouteQuotaExceeded",
+					"Namespace %s already has %d TinyLB Routes, the configured maximum", service.Namespace, r.MaxRoutesPerNamespace)
+				if err := r.withdrawHost(ctx, &service, host); err != nil {
+					logger.Error(err, "Unable to update Service status")
+					return r.statusForbidden.handle(ctx, r.Recorder, &service, "services/status", err)
+				}
+				return ctrl.Result{RequeueAfter: time.Minute}, nil
+			}
+			r.warnings.resolve(&service, "RouteQuotaExceeded")
+			if delay := r.routeCreateLimiter.reserve(r.RouteCreateQPS, time.Now()); delay > 0 {
+				logger.Info("Route creation rate limit reached, requeuing", "service", service.Name, "after", delay)
+				return ctrl.Result{RequeueAfter: delay}, nil
+			}
+			if err := ctx.Err(); err != nil {
+				return ctrl.Result{}, err
+			}
 			logger.Info("Creating Route for LoadBalancer service", "route", route.Name, "service", service.Name)
 			if err := r.Create(ctx, route); err != nil {
 				logger.Error(err, "Unable to create Route")
+				r.recordRouteCreateFailure(&service)
+				if bindErr := r.bindRoute(ctx, &service, route, err); bindErr != nil {
+					logger.Error(bindErr, "Unable to record Route creation failure in RouteBinding")
+				}
+				if !errors.IsAlreadyExists(err) {
+					if statusErr := r.withdrawHost(ctx, &service, host); statusErr != nil {
+						logger.Error(statusErr, "Unable to stop advertising host of missing Route")
+					}
+				}
 				return ctrl.Result{}, err
 			}
-		} else {
-			logger.Error(err, "Unable to get Route")
+			r.namespaceBreaker.recordSuccess(service.Namespace)
+		}
+		current = cmp.Or(existing, route)
+		if err := r.bindRoute(ctx, &service, current, nil); err != nil {
+			logger.Error(err, "Unable to update RouteBinding")
+			return ctrl.Result{}, err
+		}
+	} else {
+		if err := r.syncRouteAnnotations(ctx, &existingRoute, route.Annotations); err != nil {
+			logger.Error(err, "Unable to update Route annotations")
+			return ctrl.Result{}, err
+		}
+		if err := r.syncRouteLabels(ctx, &existingRoute, mirrored); err != nil {
+			logger.Error(err, "Unable to update Route labels")
+			return ctrl.Result{}, err
+		}
+		if err := r.syncRouteTLS(ctx, &existingRoute, route.Spec.TLS); err != nil {
+			logger.Error(err, "Unable to update Route TLS")
+			return ctrl.Result{}, err
+		}
+		if err := r.syncRouteTarget(ctx, &existingRoute, target); err != nil {
+			logger.Error(err, "Unable to update Route target")
+			return ctrl.Result{}, err
+		}
+		if err := r.syncRoutePort(ctx, &existingRoute, route.Spec.Port); err != nil {
+			logger.Error(err, "Unable to update Route target port")
+			return ctrl.Result{}, err
+		}
+		if backendReady {
+			if err := r.syncRouteWeight(ctx, &existingRoute, weight); err != nil {
+				logger.Error(err, "Unable to update Route backend weight")
+				return ctrl.Result{}, err
+			}
+		}
+		if err := r.bindRoute(ctx, &service, &existingRoute, nil); err != nil {
+			logger.Error(err, "Unable to update RouteBinding")
 			return ctrl.Result{}, err
 		}
 	}
 
-	// Update service status with the route hostname
-	serviceCopy := service.DeepCopy()
-	serviceCopy.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{
-		{
-			Hostname: route.Spec.Host,
-		},
+	// Make the port selection auditable on the service
+	if r.RecordTargetPort {
+		if err := r.recordTargetPort(ctx, &service, current); err != nil {
+			logger.Error(err, "Unable to record Route target port on Service")
+			return ctrl.Result{}, err
+		}
 	}
 
-	if err := r.Status().Update(ctx, serviceCopy); err != nil {
-		logger.Error(err, "Unable to update Service status")
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	// Publish the host through external-dns once a router has admitted the Route
+	if r.ManageDNS {
+		if err := r.syncDNSEndpoint(ctx, &service, current); err != nil {
+			logger.Error(err, "Unable to update DNSEndpoint")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.registerHost(ctx, &service, route.Spec.Host); err != nil {
+		logger.Error(err, "Unable to record host in registry")
+		return ctrl.Result{}, err
+	}
+
+	// Draining services keep their Route but stop advertising its host after a grace period
+	if service.Annotations[drainAnnotation] == "true" {
+		return r.drain(ctx, &service, route.Spec.Host)
+	}
+	if err := r.clearDrain(ctx, &service); err != nil {
+		logger.Error(err, "Unable to clear drain annotation")
+		return ctrl.Result{}, err
 	}
 
-	logger.Info("Successfully created Route and updated Service status",
-		"service", service.Name,
-		"route", route.Name,
-		"hostname", route.Spec.Host)
+	// Update service status with the route hostname
+	ingress := mergeIngress(service.Status.LoadBalancer.Ingress, route.Spec.Host, r.ingressMergeStrategy(&service))
+	if !equality.Semantic.DeepEqual(ingress, service.Status.LoadBalancer.Ingress) {
+		serviceCopy := service.DeepCopy()
+		serviceCopy.Status.LoadBalancer.Ingress = ingress
 
+		if err := ctx.Err(); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Status().Update(ctx, serviceCopy); err != nil {
+			logger.Error(err, "Unable to update Service status")
+			return r.statusForbidden.handle(ctx, r.Recorder, &service, "services/status", err)
+		}
+
+		logger.Info("Successfully created Route and updated Service status",
+			"service", service.Name,
+			"route", route.Name,
+			"hostname", route.Spec.Host)
+	}
+
+	// Diagnose advertised hosts that DNS does not know about
+	if r.VerifyDNS {
+		return r.verifyDNS(ctx, &service, route.Spec.Host)
+	}
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Service{}).
+	if len(r.BaseDomains) > 1 {
+		if err := mgr.Add(r.routeDistributionRunnable()); err != nil {
+			return err
+		}
+	}
+	predicates := []predicate.Predicate{serviceEventFilter(r.OptInAnnotation), providerFilter()}
+	if r.IgnoreStatusUpdates {
+		predicates = append(predicates, serviceSpecChangePredicate())
+	}
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Service{}, builder.WithPredicates(predicates...)).
 		Owns(&routev1.Route{}).
-		Named("service").
-		Complete(r)
+		Named("service")
+	if r.RouteBindings {
+		bldr = bldr.Owns(&networkingv1alpha1.RouteBinding{})
+	}
+	if r.ManageDNS {
+		bldr = bldr.Owns(newDNSEndpoint())
+	}
+	opts := controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}
+	if r.Debounce > 0 {
+		opts.NewQueue = debounceOptions(r.Debounce).NewQueue
+	}
+	bldr = bldr.WithOptions(opts)
+	if r.EagerRouteCreation || r.ValidatePorts {
+		// Placeholder Routes are promoted, and validated ports reselected and synced, as soon as endpoints change
+		bldr = bldr.Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(mapEndpointSliceToService))
+	}
+	return bldr.Complete(r)
 }
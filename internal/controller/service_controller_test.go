@@ -17,9 +17,102 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	goruntime "runtime"
+	"strings"
+	"sync"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	networkingv1alpha1 "github.com/jctanner/tinylb/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
+// newTestScheme returns a scheme with every type the controllers read or write
+func newTestScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+	Expect(routev1.AddToScheme(s)).To(Succeed())
+	Expect(gatewayv1.AddToScheme(s)).To(Succeed())
+	Expect(gatewayv1beta1.AddToScheme(s)).To(Succeed())
+	Expect(networkingv1alpha1.AddToScheme(s)).To(Succeed())
+	return s
+}
+
+// newFakeClient returns a fake client seeded with objs and status subresources enabled
+func newFakeClient(s *runtime.Scheme, objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objs...).
+		WithStatusSubresource(&corev1.Service{}, &gatewayv1.Gateway{}, &gatewayv1beta1.Gateway{}, &networkingv1alpha1.RouteBinding{}).
+		Build()
+}
+
+// newLoadBalancerService returns a LoadBalancer service exposing a single HTTPS port
+func newLoadBalancerService(name, namespace string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name + "-uid")},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{{Name: "https", Port: 443}},
+		},
+	}
+}
+
+// reconcileService runs a single ServiceReconciler pass for svc
+func reconcileService(r *ServiceReconciler, svc *corev1.Service) (ctrl.Result, error) {
+	return r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(svc)})
+}
+
+// defaultRouteHost returns the host a reconciler with the default configuration generates for svc
+func defaultRouteHost(svc *corev1.Service) string {
+	host, err := (&ServiceReconciler{}).RouteHost(svc)
+	Expect(err).NotTo(HaveOccurred())
+	return host
+}
+
+// stubResolver resolves the hosts it maps and fails every other lookup
+type stubResolver map[string][]string
+
+func (s stubResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := s[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
 var _ = Describe("Service Controller", func() {
 	Context("When reconciling a resource", func() {
 
@@ -29,4 +122,3461 @@ var _ = Describe("Service Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When filtering Service events", func() {
+		newService := func(serviceType corev1.ServiceType, annotations map[string]string) *corev1.Service {
+			return &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Annotations: annotations},
+				Spec:       corev1.ServiceSpec{Type: serviceType},
+			}
+		}
+
+		It("should filter out non-LoadBalancer services", func() {
+			filter := serviceEventFilter("")
+			clusterIP := newService(corev1.ServiceTypeClusterIP, nil)
+			Expect(filter.Create(event.CreateEvent{Object: clusterIP})).To(BeFalse())
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: clusterIP, ObjectNew: clusterIP})).To(BeFalse())
+			Expect(filter.Delete(event.DeleteEvent{Object: clusterIP})).To(BeFalse())
+		})
+
+		It("should admit LoadBalancer services", func() {
+			filter := serviceEventFilter("")
+			lb := newService(corev1.ServiceTypeLoadBalancer, nil)
+			Expect(filter.Create(event.CreateEvent{Object: lb})).To(BeTrue())
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: lb, ObjectNew: lb})).To(BeTrue())
+		})
+
+		It("should require the opt-in annotation when configured", func() {
+			filter := serviceEventFilter("tinylb.io/enabled")
+			Expect(filter.Create(event.CreateEvent{Object: newService(corev1.ServiceTypeLoadBalancer, nil)})).To(BeFalse())
+			annotated := newService(corev1.ServiceTypeLoadBalancer, map[string]string{"tinylb.io/enabled": "true"})
+			Expect(filter.Create(event.CreateEvent{Object: annotated})).To(BeTrue())
+		})
+	})
+
+	Context("When assigning base domains", func() {
+		domains := []string{"apps.one.example.com", "apps.two.example.com", "apps.three.example.com"}
+
+		It("should assign the same domain for the same service UID", func() {
+			svc := newLoadBalancerService("web", "default")
+			first := selectBaseDomain(svc, domains)
+			Expect(domains).To(ContainElement(first))
+			for range 5 {
+				Expect(selectBaseDomain(svc.DeepCopy(), domains)).To(Equal(first))
+			}
+		})
+
+		It("should fall back to the default domain when none are configured", func() {
+			Expect(selectBaseDomain(newLoadBalancerService("web", "default"), nil)).To(Equal(DefaultBaseDomain))
+		})
+
+		It("should keep a persisted assignment while the domain is still configured", func() {
+			svc := newLoadBalancerService("web", "default")
+			svc.Annotations = map[string]string{baseDomainAnnotation: "apps.three.example.com"}
+			Expect(selectBaseDomain(svc, domains)).To(Equal("apps.three.example.com"))
+
+			svc.Annotations[baseDomainAnnotation] = "apps.removed.example.com"
+			Expect(domains).To(ContainElement(selectBaseDomain(svc, domains)))
+		})
+
+		It("should persist the assignment on the Service and use it for the Route host", func() {
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, BaseDomains: domains}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var updated corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &updated)).To(Succeed())
+			domain := updated.Annotations[baseDomainAnnotation]
+			Expect(domain).To(Equal(selectBaseDomain(svc, domains)))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: "tinylb-web", Namespace: "default"}, &route)).To(Succeed())
+			Expect(route.Spec.Host).To(Equal("web-default." + domain))
+			Expect(updated.Status.LoadBalancer.Ingress).To(HaveLen(1))
+			Expect(updated.Status.LoadBalancer.Ingress[0].Hostname).To(Equal(route.Spec.Host))
+		})
+	})
+
+	Context("When the reconcile context is cancelled", func() {
+		It("should not write the Service or create a Route", func() {
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+
+			cancelledCtx, cancelFn := context.WithCancel(context.Background())
+			cancelFn()
+
+			_, err := r.Reconcile(cancelledCtx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(svc)})
+			Expect(err).To(MatchError(context.Canceled))
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(BeEmpty())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).NotTo(HaveKey(baseDomainAnnotation))
+			Expect(current.Status.LoadBalancer.Ingress).To(BeEmpty())
+		})
+	})
+
+	Context("When sharding routes across routers", func() {
+		It("should assign a deterministic shard label within range", func() {
+			svc := newLoadBalancerService("web", "default")
+			shard := routerShard(svc, 4)
+			Expect(shard).To(BeElementOf("shard-0", "shard-1", "shard-2", "shard-3"))
+			recreated := newLoadBalancerService("web", "default")
+			recreated.UID = "another-uid"
+			Expect(routerShard(recreated, 4)).To(Equal(shard))
+		})
+
+		It("should not shard when disabled", func() {
+			Expect(routerShard(newLoadBalancerService("web", "default"), 0)).To(BeEmpty())
+		})
+
+		It("should label the created Route with its shard", func() {
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, RouterShards: 3}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: "tinylb-web", Namespace: "default"}, &route)).To(Succeed())
+			Expect(route.Labels).To(HaveKeyWithValue(routerShardLabel, routerShard(svc, 3)))
+		})
+
+		It("should relabel existing Routes when the shard count changes", func() {
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, RouterShards: 3}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			key := types.NamespacedName{Name: "tinylb-web", Namespace: "default"}
+			var route routev1.Route
+			for _, shards := range []int{7, 0} {
+				r.RouterShards = shards
+				_, err = reconcileService(r, svc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(r.Get(context.Background(), key, &route)).To(Succeed())
+				if shards > 0 {
+					Expect(route.Labels).To(HaveKeyWithValue(routerShardLabel, routerShard(svc, shards)))
+				} else {
+					Expect(route.Labels).NotTo(HaveKey(routerShardLabel))
+					Expect(route.Annotations).NotTo(HaveKey(routerLabelledAnnotation))
+				}
+			}
+		})
+
+		It("should keep a router label set on the Route by hand", func() {
+			svc := newLoadBalancerService("web", "default")
+			route := newManagedRoute(svc, defaultRouteHost(svc))
+			route.Labels[routerShardLabel] = "edge"
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, route), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(route), route)).To(Succeed())
+			Expect(route.Labels).To(HaveKeyWithValue(routerShardLabel, "edge"))
+		})
+	})
+
+	Context("When the service is headless", func() {
+		It("should skip it with a HeadlessUnsupported event instead of creating a Route", func() {
+			svc := newLoadBalancerService("web", "default")
+			svc.Spec.ClusterIP = corev1.ClusterIPNone
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("HeadlessUnsupported")))
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(BeEmpty())
+		})
+
+		It("should not repeat the HeadlessUnsupported event on later reconciles", func() {
+			svc := newLoadBalancerService("web", "default")
+			svc.Spec.ClusterIP = corev1.ClusterIPNone
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			for range 3 {
+				_, err := reconcileService(r, svc)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(recorder.Events).To(Receive(ContainSubstring("HeadlessUnsupported")))
+			Expect(recorder.Events).NotTo(Receive())
+
+			// A re-created service starts over
+			Expect(r.Delete(context.Background(), svc)).To(Succeed())
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			svc.ResourceVersion = ""
+			Expect(r.Create(context.Background(), svc)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("HeadlessUnsupported")))
+		})
+	})
+
+	Context("When duplicate managed Routes exist", func() {
+		It("should keep the canonical Route and delete the extras", func() {
+			svc := newLoadBalancerService("web", "default")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "web-default.apps-crc.testing"}}
+			canonical := newManagedRoute(svc, "web-default.apps-crc.testing")
+			duplicate := newManagedRoute(svc, "web-default.apps-crc.testing")
+			duplicate.Name = "legacy-web"
+
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, canonical, duplicate), Scheme: scheme, Recorder: recorder}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("DuplicateRoutes")))
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(HaveLen(1))
+			Expect(routes.Items[0].Name).To(Equal("tinylb-web"))
+		})
+
+		It("should keep the oldest Route when the canonical one is missing", func() {
+			svc := newLoadBalancerService("web", "default")
+			created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			legacy := func(name string, age time.Duration) *routev1.Route {
+				route := newManagedRoute(svc, "web-default.apps-crc.testing")
+				route.Name = name
+				route.CreationTimestamp = metav1.NewTime(created.Add(age))
+				return route
+			}
+
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc,
+				legacy("legacy-a", 3*time.Hour), legacy("legacy-b", 0), legacy("legacy-c", time.Hour), legacy("legacy-d", 2*time.Hour)),
+				Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			Expect(r.removeDuplicateRoutes(context.Background(), svc)).To(Succeed())
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(HaveLen(1))
+			Expect(routes.Items[0].Name).To(Equal("legacy-b"))
+		})
+	})
+
+	Context("When a service declares extra ports to skip", func() {
+		ports := []corev1.ServicePort{
+			{Name: "admin", Port: 9000},
+			{Name: "debug", Port: 9001},
+			{Name: "app", Port: 7000},
+		}
+
+		It("should merge the annotation with the management ports", func() {
+			svc := newLoadBalancerService("web", "default")
+			svc.Annotations = map[string]string{skipPortsAnnotation: "9000, 9001"}
+			r := &ServiceReconciler{ManagementPorts: []int32{15021}}
+			Expect(r.skippedPorts(svc)).To(ConsistOf(int32(15021), int32(9000), int32(9001)))
+		})
+
+		It("should change the selected port", func() {
+			Expect(selectHTTPPort(ports, nil, nil, DefaultManagementPorts).Port).To(Equal(int32(9000)))
+			Expect(selectHTTPPort(ports, nil, nil, append(DefaultManagementPorts, 9000, 9001)).Port).To(Equal(int32(7000)))
+		})
+
+		It("should ignore an invalid annotation with a warning", func() {
+			svc := newLoadBalancerService("web", "default")
+			svc.Annotations = map[string]string{skipPortsAnnotation: "9000,http"}
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Recorder: recorder}
+			Expect(r.skippedPorts(svc)).To(Equal(DefaultManagementPorts))
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidAnnotation")))
+
+			By("not repeating the warning on the next reconcile")
+			Expect(r.skippedPorts(svc)).To(Equal(DefaultManagementPorts))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should format the default management ports as the flag value", func() {
+			Expect(FormatPortList(DefaultManagementPorts)).To(Equal("15021,15090,9090,8181"))
+			Expect(ParsePortList(FormatPortList(DefaultManagementPorts))).To(Equal(DefaultManagementPorts))
+		})
+	})
+
+	Context("When status updates are forbidden", func() {
+		It("should report the missing RBAC once instead of returning errors", func() {
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc).
+				WithStatusSubresource(&corev1.Service{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourceUpdate: func(_ context.Context, _ client.Client, subResource string, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+						return apierrors.NewForbidden(corev1.Resource("services/status"), obj.GetName(), nil)
+					},
+				}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+			for range 2 {
+				result, err := reconcileService(r, svc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(forbiddenStatusRetryInterval))
+			}
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(ContainSubstring("StatusUpdateForbidden"))
+		})
+	})
+
+	Context("When an equivalent Route exists under another name", func() {
+		It("should reuse it instead of creating a duplicate", func() {
+			svc := newLoadBalancerService("web", "default")
+			legacy := newManagedRoute(svc, defaultRouteHost(svc))
+			legacy.Name = "legacy-web"
+			legacy.Labels = map[string]string{managedLabel: "true"}
+
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, legacy), Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(HaveLen(1))
+			Expect(routes.Items[0].Name).To(Equal("legacy-web"))
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress[0].Hostname).To(Equal(legacy.Spec.Host))
+		})
+	})
+
+	Context("When tracing is enabled", func() {
+		It("should emit spans for the reconcile and its API calls", func() {
+			exporter := tracetest.NewInMemoryExporter()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			previous := otel.GetTracerProvider()
+			otel.SetTracerProvider(provider)
+			DeferCleanup(func() { otel.SetTracerProvider(previous) })
+
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: NewTracingClient(newFakeClient(scheme, svc)), Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			spans := exporter.GetSpans()
+			var names []string
+			for _, span := range spans {
+				names = append(names, span.Name)
+			}
+			Expect(names).To(ContainElements("ServiceReconciler.Reconcile", "Get", "Create", "UpdateStatus"))
+
+			root := spans[len(spans)-1]
+			Expect(root.Name).To(Equal("ServiceReconciler.Reconcile"))
+			for _, span := range spans[:len(spans)-1] {
+				Expect(span.SpanContext.TraceID()).To(Equal(root.SpanContext.TraceID()))
+			}
+		})
+	})
+
+	Context("When predicting generated names", func() {
+		It("should expose the Route name, host and Gateway service name", func() {
+			svc := newLoadBalancerService("web", "shop")
+			Expect(RouteName(svc)).To(Equal("tinylb-web"))
+			r := &ServiceReconciler{BaseDomains: []string{"apps.example.com"}}
+			Expect(r.RouteHost(svc)).To(Equal("web-shop.apps.example.com"))
+			Expect(GatewayServiceName(newGateway("gw", "shop", "istio"))).To(Equal("gw-istio"))
+		})
+
+		It("should match what the reconciler creates", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Spec.Host).To(Equal(defaultRouteHost(svc)))
+		})
+
+		It("should follow the reconciler's host strategy and external-dns hostname", func() {
+			svc := newLoadBalancerService("Web", "shop")
+			svc.Annotations = map[string]string{externalDNSHostnameAnnotation: "Shop.Example.com"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10),
+				BaseDomains: []string{"apps.example.com"}, HonorExternalDNSHostname: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(r.RouteHost(svc)).To(Equal(route.Spec.Host))
+			Expect(route.Spec.Host).To(Equal("shop.example.com"))
+
+			r.DefaultHostStrategy = HostStrategySubdomain
+			r.HonorExternalDNSHostname = false
+			Expect(r.RouteHost(svc)).To(Equal("web.shop.apps.example.com"))
+		})
+	})
+
+	Context("When a managed Route lost its owner reference", func() {
+		It("should restore the owner reference", func() {
+			svc := newLoadBalancerService("web", "default")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: defaultRouteHost(svc)}}
+			route := newManagedRoute(svc, defaultRouteHost(svc))
+
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, route), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(route), &current)).To(Succeed())
+			Expect(current.OwnerReferences).To(HaveLen(1))
+			Expect(current.OwnerReferences[0].UID).To(Equal(svc.UID))
+			Expect(current.OwnerReferences[0].Kind).To(Equal("Service"))
+		})
+
+		It("should not adopt a Route labeled for another service", func() {
+			svc := newLoadBalancerService("web", "default")
+			route := newManagedRoute(svc, defaultRouteHost(svc))
+			route.Labels[serviceUIDLabel] = "someone-else"
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: route.Spec.Host}}
+
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, route), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(route), &current)).To(Succeed())
+			Expect(current.OwnerReferences).To(BeEmpty())
+		})
+	})
+
+	Context("When a host suffix is configured", func() {
+		It("should insert the suffix before the base domain", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, HostSuffix: "-dev", BaseDomains: []string{"apps.example.com"}}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Spec.Host).To(Equal("web-shop-dev.apps.example.com"))
+			Expect(r.RouteHost(svc)).To(Equal(route.Spec.Host))
+		})
+
+		It("should combine the suffix with the subdomain strategy", func() {
+			svc := newLoadBalancerService("web", "shop")
+			r := &ServiceReconciler{HostSuffix: "-dev", BaseDomains: []string{"apps.example.com"}, DefaultHostStrategy: HostStrategySubdomain}
+			Expect(r.RouteHost(svc)).To(Equal("web-dev.shop.apps.example.com"))
+		})
+
+		It("should validate the suffix", func() {
+			Expect(ValidateHostSuffix("")).To(Succeed())
+			Expect(ValidateHostSuffix("-dev")).To(Succeed())
+			Expect(ValidateHostSuffix("_Dev")).NotTo(Succeed())
+			Expect(ValidateHostSuffix("-dev-")).NotTo(Succeed())
+		})
+
+		It("should refuse hosts whose label becomes invalid", func() {
+			r := &ServiceReconciler{HostSuffix: "-" + strings.Repeat("x", 60)}
+			_, _, err := r.routeHost(newLoadBalancerService("web", "shop"), "apps.example.com")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When honoring external-dns hostnames", func() {
+		var svc *corev1.Service
+
+		BeforeEach(func() {
+			svc = newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{externalDNSHostnameAnnotation: "www.example.com,web.example.com"}
+		})
+
+		It("should use the first annotated hostname as the Route host", func() {
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, HonorExternalDNSHostname: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Spec.Host).To(Equal("www.example.com"))
+		})
+
+		It("should ignore the annotation unless enabled", func() {
+			r := &ServiceReconciler{}
+			Expect(r.routeHost(svc, "apps.example.com")).To(Equal("web-shop.apps.example.com"))
+		})
+
+		It("should not create a Route when another Route already uses the host", func() {
+			other := newLoadBalancerService("other", "team-b")
+			taken := newManagedRoute(other, "www.example.com")
+
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, taken), Scheme: scheme, Recorder: recorder, HonorExternalDNSHostname: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("HostConflict")))
+
+			var route routev1.Route
+			err = r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			By("not repeating the warning on the next reconcile")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("HostConflict")))
+
+			By("warning again once the host, claimed in between, is taken again")
+			Expect(r.Delete(context.Background(), taken)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(r.Delete(context.Background(), &route)).To(Succeed())
+			Expect(r.Create(context.Background(), newManagedRoute(other, "www.example.com"))).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("HostConflict")))
+		})
+	})
+
+	Context("When the service already has ingress from another provider", func() {
+		cloud := corev1.LoadBalancerIngress{IP: "203.0.113.7"}
+
+		reconcileWithStrategy := func(strategy IngressMergeStrategy) []corev1.LoadBalancerIngress {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{cloud}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, IngressMergeStrategy: strategy}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			return current.Status.LoadBalancer.Ingress
+		}
+
+		It("should leave the entries alone by default", func() {
+			Expect(reconcileWithStrategy("")).To(Equal([]corev1.LoadBalancerIngress{cloud}))
+		})
+
+		It("should replace the entries when asked to", func() {
+			Expect(reconcileWithStrategy(IngressMergeReplace)).To(Equal([]corev1.LoadBalancerIngress{{Hostname: "web-shop.apps-crc.testing"}}))
+		})
+
+		It("should append after the existing entries", func() {
+			Expect(reconcileWithStrategy(IngressMergeAppend)).To(Equal([]corev1.LoadBalancerIngress{cloud, {Hostname: "web-shop.apps-crc.testing"}}))
+		})
+
+		It("should leave the entries alone with skip-if-present", func() {
+			Expect(reconcileWithStrategy(IngressMergeSkipIfPresent)).To(Equal([]corev1.LoadBalancerIngress{cloud}))
+		})
+
+		It("should not append the same hostname twice", func() {
+			existing := []corev1.LoadBalancerIngress{cloud, {Hostname: "web.example.com"}}
+			Expect(mergeIngress(existing, "web.example.com", IngressMergeAppend)).To(Equal(existing))
+		})
+
+		It("should reject unknown strategies", func() {
+			_, err := ParseIngressMergeStrategy("merge")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When tracking reconciles in flight", func() {
+		It("should return the gauge to zero after a reconcile", func() {
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testutil.ToFloat64(reconcilesInFlight.WithLabelValues("service"))).To(BeZero())
+		})
+
+		It("should decrement the gauge when a reconcile panics", func() {
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(_ context.Context, _ client.WithWatch, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+						panic("boom")
+					},
+				}).
+				Build()
+			r := &ServiceReconciler{Client: c, Scheme: scheme}
+
+			Expect(func() { _, _ = reconcileService(r, svc) }).To(Panic())
+			Expect(testutil.ToFloat64(reconcilesInFlight.WithLabelValues("service"))).To(BeZero())
+		})
+	})
+
+	Context("When the Route host needs sanitizing", func() {
+		It("should lowercase and replace invalid characters with a Warning event", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{externalDNSHostnameAnnotation: "WWW.My_Shop.example.com"}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, HonorExternalDNSHostname: true}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("HostSanitized")))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Spec.Host).To(Equal("www.my-shop.example.com"))
+
+			By("not repeating the warning while the annotation is unchanged")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("HostSanitized")))
+		})
+
+		It("should leave valid hosts untouched", func() {
+			Expect(sanitizeHost("web-shop.apps.example.com")).To(Equal("web-shop.apps.example.com"))
+			Expect(sanitizeHost("_Web!.Example.COM")).To(Equal("web.example.com"))
+		})
+	})
+
+	Context("When Route creation keeps failing in a namespace", func() {
+		var creates int
+
+		newFailingReconciler := func(recorder *record.FakeRecorder, objs ...client.Object) *ServiceReconciler {
+			creates = 0
+			scheme := newTestScheme()
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objs...).
+				WithStatusSubresource(&corev1.Service{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+						creates++
+						return apierrors.NewForbidden(routev1.Resource("routes"), obj.GetName(), nil)
+					},
+				}).
+				Build()
+			return &ServiceReconciler{Client: c, Scheme: scheme, Recorder: recorder, NamespaceFailureThreshold: 2}
+		}
+
+		It("should back off the namespace after the threshold and warn once", func() {
+			svc := newLoadBalancerService("web", "quota")
+			recorder := record.NewFakeRecorder(10)
+			r := newFailingReconciler(recorder, svc)
+
+			for range 2 {
+				_, err := reconcileService(r, svc)
+				Expect(err).To(HaveOccurred())
+			}
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(ContainSubstring("NamespaceBackoff"))
+
+			result, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(creates).To(Equal(2))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should reset after a successful creation", func() {
+			svc := newLoadBalancerService("web", "quota")
+			recorder := record.NewFakeRecorder(10)
+			r := newFailingReconciler(recorder, svc)
+			_, err := reconcileService(r, svc)
+			Expect(err).To(HaveOccurred())
+
+			scheme := newTestScheme()
+			r.Client = newFakeClient(scheme, svc)
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.namespaceBreaker.failures).NotTo(HaveKey("quota"))
+
+			// The next failure starts counting from zero again
+			Expect(r.namespaceBreaker.recordFailure("quota", 2, time.Minute, time.Now())).To(BeFalse())
+			Expect(r.namespaceBreaker.remaining("quota", time.Now())).To(BeZero())
+		})
+	})
+
+	Context("When a service sets tinylb.io/http2", func() {
+		routeFor := func(r *ServiceReconciler, svc *corev1.Service) routev1.Route {
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: svc.Namespace}, &route)).To(Succeed())
+			return route
+		}
+
+		It("should disable HTTP/2 on the Route for false and force it on for true", func() {
+			for value, disabled := range map[string]string{"false": "true", "true": "false"} {
+				svc := newLoadBalancerService("web", "shop")
+				svc.Annotations = map[string]string{http2Annotation: value}
+				scheme := newTestScheme()
+				r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+				Expect(routeFor(r, svc).Annotations).To(HaveKeyWithValue(disableHTTP2RouteAnnotation, disabled), value)
+			}
+		})
+
+		It("should leave the router default when unset and drop its own setting when removed", func() {
+			svc := newLoadBalancerService("web", "shop")
+			route := newManagedRoute(svc, defaultRouteHost(svc))
+			route.Annotations = map[string]string{
+				disableHTTP2RouteAnnotation:     "true",
+				ownedRouteAnnotationsAnnotation: disableHTTP2RouteAnnotation,
+				"example.com/keep":              "yes",
+			}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, route), Scheme: scheme}
+
+			annotations := routeFor(r, svc).Annotations
+			Expect(annotations).NotTo(HaveKey(disableHTTP2RouteAnnotation))
+			Expect(annotations).To(HaveKeyWithValue("example.com/keep", "yes"))
+		})
+
+		It("should ignore invalid values with a Warning event", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{http2Annotation: "sometimes"}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			Expect(routeFor(r, svc).Annotations).NotTo(HaveKey(disableHTTP2RouteAnnotation))
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidAnnotation")))
+
+			By("not repeating the warning on the next reconcile")
+			Expect(routeFor(r, svc).Annotations).NotTo(HaveKey(disableHTTP2RouteAnnotation))
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("InvalidAnnotation")))
+		})
+
+		It("should keep an HTTP/2 setting made on the Route by hand", func() {
+			svc := newLoadBalancerService("web", "shop")
+			route := newManagedRoute(svc, defaultRouteHost(svc))
+			route.Annotations = map[string]string{disableHTTP2RouteAnnotation: "true"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, route), Scheme: scheme}
+
+			Expect(routeFor(r, svc).Annotations).To(HaveKeyWithValue(disableHTTP2RouteAnnotation, "true"))
+		})
+	})
+
+	Context("When eager route creation is enabled", func() {
+		It("should create a placeholder Route and promote it once endpoints are ready", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, EagerRouteCreation: true}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Spec.To.Weight).To(HaveValue(Equal(placeholderRouteWeight)))
+
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "web-abc",
+					Namespace: "shop",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses:  []string{"10.0.0.5"},
+					Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+				}},
+			}
+			Expect(r.Create(context.Background(), slice)).To(Succeed())
+			Expect(mapEndpointSliceToService(context.Background(), slice)).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(svc)}))
+
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Spec.To.Weight).To(HaveValue(Equal(defaultRouteWeight)))
+		})
+
+		It("should create a normal Route when endpoints are already ready", func() {
+			svc := newLoadBalancerService("web", "shop")
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "web-abc",
+					Namespace: "shop",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.5"}}},
+			}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, slice), Scheme: scheme, EagerRouteCreation: true}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Spec.To.Weight).To(BeNil())
+		})
+	})
+
+	Context("When a host registry is configured", func() {
+		registryKey := types.NamespacedName{Namespace: "tinylb-system", Name: "tinylb-hosts"}
+
+		It("should record the assigned host", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, HostRegistry: registryKey}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var registry corev1.ConfigMap
+			Expect(r.Get(context.Background(), registryKey, &registry)).To(Succeed())
+			Expect(registry.Data).To(HaveKeyWithValue(string(svc.UID), "web-shop.apps-crc.testing"))
+			Expect(registry.Labels).To(HaveKeyWithValue(managedLabel, "true"))
+		})
+
+		It("should reuse an existing assignment", func() {
+			svc := newLoadBalancerService("web", "shop")
+			registry := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: registryKey.Namespace, Name: registryKey.Name},
+				Data:       map[string]string{string(svc.UID): "web.legacy.example.com"},
+			}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, registry), Scheme: scheme, HostRegistry: registryKey}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Spec.Host).To(Equal("web.legacy.example.com"))
+		})
+
+		It("should retry registry updates on conflict", func() {
+			svc := newLoadBalancerService("web", "shop")
+			registry := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: registryKey.Namespace, Name: registryKey.Name}}
+			scheme := newTestScheme()
+			conflicts := 1
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc, registry).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+						if _, ok := obj.(*corev1.ConfigMap); ok && conflicts > 0 {
+							conflicts--
+							return apierrors.NewConflict(corev1.Resource("configmaps"), obj.GetName(), nil)
+						}
+						return c.Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			r := &ServiceReconciler{Client: c, Scheme: scheme, HostRegistry: registryKey}
+
+			Expect(r.registerHost(context.Background(), svc, "web.example.com")).To(Succeed())
+			host, err := r.registeredHost(context.Background(), svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(host).To(Equal("web.example.com"))
+		})
+
+		It("should prune the entries of deleted services", func() {
+			svc := newLoadBalancerService("web", "shop")
+			other := newLoadBalancerService("api", "shop")
+			registry := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: registryKey.Namespace, Name: registryKey.Name},
+				Data:       map[string]string{string(other.UID): "api-shop.apps-crc.testing", "gone-uid": "gone-shop.apps-crc.testing"},
+			}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, other, registry), Scheme: scheme, HostRegistry: registryKey}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Delete(context.Background(), svc)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Get(context.Background(), registryKey, registry)).To(Succeed())
+			Expect(registry.Data).To(Equal(map[string]string{string(other.UID): "api-shop.apps-crc.testing"}))
+		})
+
+		It("should label a registry created before TinyLB labelled it, so the cache can see it", func() {
+			svc := newLoadBalancerService("web", "shop")
+			registry := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: registryKey.Namespace, Name: registryKey.Name},
+				Data:       map[string]string{string(svc.UID): "web.legacy.example.com"},
+			}
+			scheme := newTestScheme()
+			apiReader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc, registry).Build()
+			// The cache only holds ConfigMaps labelled as TinyLB's
+			c := interceptor.NewClient(apiReader, interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := c.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					if _, ok := obj.(*corev1.ConfigMap); ok && obj.GetLabels()[managedLabel] != "true" {
+						return apierrors.NewNotFound(corev1.Resource("configmaps"), key.Name)
+					}
+					return nil
+				},
+			})
+			r := &ServiceReconciler{Client: c, Scheme: scheme, HostRegistry: registryKey, APIReader: apiReader}
+
+			Expect(r.registeredHost(context.Background(), svc)).To(Equal("web.legacy.example.com"))
+			Expect(c.Get(context.Background(), registryKey, registry)).To(Succeed())
+			Expect(registry.Labels).To(HaveKeyWithValue(managedLabel, "true"))
+		})
+
+		It("should parse the registry flag", func() {
+			Expect(ParseHostRegistry("tinylb-system/tinylb-hosts")).To(Equal(registryKey))
+			_, err := ParseHostRegistry("tinylb-hosts")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When another provider takes over the service's ingress", func() {
+		var svc *corev1.Service
+		var route *routev1.Route
+
+		BeforeEach(func() {
+			svc = newLoadBalancerService("web", "shop")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.7"}}
+			route = newManagedRoute(svc, defaultRouteHost(svc))
+		})
+
+		It("should remove its Route and leave the service alone", func() {
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, route), Scheme: scheme, Recorder: recorder}
+
+			for range 2 {
+				_, err := reconcileService(r, svc)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(recorder.Events).To(Receive(ContainSubstring("HandedOff")))
+
+			err := r.Get(context.Background(), client.ObjectKeyFromObject(route), &routev1.Route{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).To(HaveKeyWithValue(handedOffAnnotation, "true"))
+			Expect(current.Status.LoadBalancer.Ingress).To(Equal([]corev1.LoadBalancerIngress{{IP: "203.0.113.7"}}))
+		})
+
+		It("should keep its Route when tinylb.io/force is set", func() {
+			svc.Annotations = map[string]string{forceAnnotation: "true"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, route), Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(route), &routev1.Route{})).To(Succeed())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(Equal([]corev1.LoadBalancerIngress{{Hostname: route.Spec.Host}}))
+		})
+
+		It("should resume once the foreign ingress is gone", func() {
+			svc.Annotations = map[string]string{handedOffAnnotation: "true"}
+			svc.Status.LoadBalancer.Ingress = nil
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(route), &routev1.Route{})).To(Succeed())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).NotTo(HaveKey(handedOffAnnotation))
+		})
+	})
+
+	Context("When a service is drained", func() {
+		advertised := corev1.LoadBalancerIngress{Hostname: "web-shop.apps-crc.testing"}
+
+		drainedService := func(started string) *corev1.Service {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{drainAnnotation: "true"}
+			if started != "" {
+				svc.Annotations[drainStartedAnnotation] = started
+			}
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{advertised}
+			return svc
+		}
+
+		It("should keep advertising the host during the grace period", func() {
+			svc := drainedService("")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, DrainGracePeriod: time.Minute}
+
+			result, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).To(HaveKey(drainStartedAnnotation))
+			Expect(current.Status.LoadBalancer.Ingress).To(ConsistOf(advertised))
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &routev1.Route{})).To(Succeed())
+		})
+
+		It("should clear the ingress but keep the Route after the grace period", func() {
+			svc := drainedService(time.Now().Add(-2 * time.Minute).UTC().Format(time.RFC3339))
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, DrainGracePeriod: time.Minute}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("Drained")))
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(BeEmpty())
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &routev1.Route{})).To(Succeed())
+		})
+	})
+
+	Context("When writes go through a field owner client", func() {
+		It("should attribute Route and status writes to the field manager", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			managers := map[string]string{}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc).
+				WithStatusSubresource(&corev1.Service{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						managers["create"] = (&client.CreateOptions{}).ApplyOptions(opts).FieldManager
+						return c.Create(ctx, obj, opts...)
+					},
+					SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						managers["status"] = (&client.SubResourceUpdateOptions{}).ApplyOptions(opts).FieldManager
+						return c.SubResource(subResource).Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			r := &ServiceReconciler{Client: client.WithFieldOwner(c, "tinylb"), Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(managers).To(Equal(map[string]string{"create": "tinylb", "status": "tinylb"}))
+		})
+	})
+
+	Context("When a service requests HSTS", func() {
+		It("should ignore it on the passthrough Route with a Warning event", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{hstsAnnotation: "max-age=31536000"}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring(hstsAnnotation)))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Annotations).NotTo(HaveKey(hstsRouteAnnotation))
+
+			By("not repeating the warning on the next reconcile")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring(hstsAnnotation)))
+		})
+	})
+
+	Context("When verifying DNS for advertised hosts", func() {
+		It("should flag a host that does not resolve once", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, VerifyDNS: true, Resolver: stubResolver{}}
+
+			for range 2 {
+				result, err := reconcileService(r, svc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(dnsRecheckInterval))
+			}
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(ContainSubstring("HostNotResolvable"))
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).To(HaveKeyWithValue(dnsUnresolvableAnnotation, "web-shop.apps-crc.testing"))
+		})
+
+		It("should clear the flag once the host resolves", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{dnsUnresolvableAnnotation: "web-shop.apps-crc.testing"}
+			scheme := newTestScheme()
+			resolver := stubResolver{"web-shop.apps-crc.testing": {"192.0.2.10"}}
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, VerifyDNS: true, Resolver: resolver}
+
+			result, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).NotTo(HaveKey(dnsUnresolvableAnnotation))
+		})
+	})
+
+	Context("When a service uses ClientIP session affinity", func() {
+		It("should balance the Route by source address", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Annotations).To(HaveKeyWithValue(balanceRouteAnnotation, "source"))
+		})
+
+		It("should stop balancing by source once affinity is turned off", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			current.Spec.SessionAffinity = corev1.ServiceAffinityNone
+			Expect(r.Update(context.Background(), &current)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Annotations).NotTo(HaveKey(balanceRouteAnnotation))
+		})
+
+		It("should keep a balance algorithm set on the Route by hand", func() {
+			svc := newLoadBalancerService("web", "shop")
+			route := newManagedRoute(svc, defaultRouteHost(svc))
+			route.Annotations = map[string]string{balanceRouteAnnotation: "leastconn"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, route), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(route), route)).To(Succeed())
+			Expect(route.Annotations).To(HaveKeyWithValue(balanceRouteAnnotation, "leastconn"))
+		})
+
+		It("should leave the router default without affinity", func() {
+			r := &ServiceReconciler{}
+			Expect(r.routeAnnotations(newLoadBalancerService("web", "shop"))).NotTo(HaveKey(balanceRouteAnnotation))
+		})
+	})
+
+	Context("When reconciles fail", func() {
+		It("should report a missing Route API as ErrRouteCRDMissing", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc).
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
+						return &meta.NoKindMatchError{GroupKind: routev1.GroupVersion.WithKind("Route").GroupKind()}
+					},
+				}).
+				Build()
+			r := &ServiceReconciler{Client: c, Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(errors.Is(err, ErrRouteCRDMissing)).To(BeTrue())
+		})
+
+		It("should report a taken host as ErrHostConflict", func() {
+			svc := newLoadBalancerService("web", "shop")
+			taken := newManagedRoute(newLoadBalancerService("other", "team-b"), "www.example.com")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, taken), Scheme: scheme}
+
+			Expect(errors.Is(r.checkHostAvailable(context.Background(), svc, "www.example.com"), ErrHostConflict)).To(BeTrue())
+			Expect(r.checkHostAvailable(context.Background(), svc, "web.example.com")).To(Succeed())
+		})
+
+		It("should leave other errors untyped", func() {
+			err := classifyError(apierrors.NewForbidden(routev1.Resource("routes"), "web", nil))
+			Expect(errors.Is(err, ErrRouteCRDMissing)).To(BeFalse())
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		})
+	})
+
+	Context("When mirroring service labels", func() {
+		It("should copy selected labels and skip reserved ones", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Labels = map[string]string{
+				"app.kubernetes.io/name":      "web",
+				"cost-center":                 "retail",
+				"unrelated":                   "x",
+				"tinylb.io/service":           "spoofed",
+				"kubernetes.io/metadata.name": "shop",
+			}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{
+				Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10),
+				MirrorServiceLabels: []string{"app.kubernetes.io/", "cost-center", "tinylb.io/", "kubernetes.io/"},
+			}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Labels).To(HaveKeyWithValue("app.kubernetes.io/name", "web"))
+			Expect(route.Labels).To(HaveKeyWithValue("cost-center", "retail"))
+			Expect(route.Labels).To(HaveKeyWithValue(serviceLabel, "web"))
+			Expect(route.Labels).NotTo(HaveKey("unrelated"))
+			Expect(route.Labels).NotTo(HaveKey("kubernetes.io/metadata.name"))
+		})
+
+		It("should follow label changes on an existing Route", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Labels = map[string]string{"cost-center": "retail"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{
+				Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10),
+				MirrorServiceLabels: []string{"cost-center", "team"},
+			}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			current.Labels = map[string]string{"team": "payments"}
+			Expect(r.Update(context.Background(), &current)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Labels).To(HaveKeyWithValue("team", "payments"))
+			Expect(route.Labels).NotTo(HaveKey("cost-center"))
+			Expect(route.Labels).To(HaveKeyWithValue(managedLabel, "true"))
+		})
+	})
+
+	Context("When the service port targets a named container port", func() {
+		It("should reference the service port by name", func() {
+			Expect(routeTargetPort(corev1.ServicePort{Name: "https", Port: 443, TargetPort: intstr.FromString("web-tls")}, false)).
+				To(Equal(intstr.FromString("https")))
+		})
+
+		It("should keep numeric target ports as numbers", func() {
+			Expect(routeTargetPort(corev1.ServicePort{Name: "https", Port: 443, TargetPort: intstr.FromInt(8443)}, false)).
+				To(Equal(intstr.FromInt(443)))
+			Expect(routeTargetPort(corev1.ServicePort{Port: 443, TargetPort: intstr.FromString("web-tls")}, false)).
+				To(Equal(intstr.FromInt(443)))
+		})
+
+		It("should create the Route with the named port", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports[0].TargetPort = intstr.FromString("web-tls")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.Port.TargetPort).To(Equal(intstr.FromString("https")))
+		})
+	})
+
+	Context("When a namespace reaches its Route limit", func() {
+		It("should refuse new Routes until capacity frees up", func() {
+			existing := newLoadBalancerService("api", "shop")
+			existingRoute := newManagedRoute(existing, "api.example.com")
+			svc := newLoadBalancerService("web", "shop")
+			other := newLoadBalancerService("web", "blog")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{
+				Client: newFakeClient(scheme, existing, existingRoute, svc, other), Scheme: scheme, Recorder: recorder,
+				MaxRoutesPerNamespace: 1,
+			}
+
+			result, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(recorder.Events).To(Receive(ContainSubstring("RouteQuotaExceeded")))
+			var route routev1.Route
+			Expect(apierrors.IsNotFound(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route))).To(BeTrue())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("RouteQuotaExceeded")))
+
+			// Other namespaces have their own limit
+			_, err = reconcileService(r, other)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "blog", Name: RouteName(other)}, &route)).To(Succeed())
+
+			Expect(r.Delete(context.Background(), existingRoute)).To(Succeed())
+			result, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+		})
+
+		It("should keep serving services that already have a Route", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, MaxRoutesPerNamespace: 1}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("RouteQuotaExceeded")))
+		})
+	})
+
+	Context("When a wildcard policy is requested", func() {
+		reconcileWithPolicy := func(policy string) (*routev1.Route, *record.FakeRecorder) {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{wildcardPolicyAnnotation: policy}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			return &route, recorder
+		}
+
+		It("should set the None policy", func() {
+			route, _ := reconcileWithPolicy("None")
+			Expect(route.Spec.WildcardPolicy).To(Equal(routev1.WildcardPolicyNone))
+		})
+
+		It("should set the Subdomain policy", func() {
+			route, recorder := reconcileWithPolicy("Subdomain")
+			Expect(route.Spec.WildcardPolicy).To(Equal(routev1.WildcardPolicySubdomain))
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("InvalidAnnotation")))
+		})
+
+		It("should ignore unknown policies with a single Warning", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{wildcardPolicyAnnotation: "Everything"}
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Recorder: recorder}
+
+			Expect(r.wildcardPolicy(svc, "web.apps.example.com")).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidAnnotation")))
+			Expect(r.wildcardPolicy(svc, "web.apps.example.com")).To(BeEmpty())
+			Expect(recorder.Events).NotTo(Receive())
+
+			By("warning again once the annotation is fixed and broken again")
+			svc.Annotations[wildcardPolicyAnnotation] = "None"
+			Expect(r.wildcardPolicy(svc, "web.apps.example.com")).To(Equal(routev1.WildcardPolicyNone))
+			svc.Annotations[wildcardPolicyAnnotation] = "Everything"
+			Expect(r.wildcardPolicy(svc, "web.apps.example.com")).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidAnnotation")))
+		})
+
+		It("should reject Subdomain for a host without a parent domain", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{wildcardPolicyAnnotation: "Subdomain"}
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Recorder: recorder}
+
+			Expect(r.wildcardPolicy(svc, "example.com")).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("no parent domain")))
+			Expect(r.wildcardPolicy(svc, "web.apps.example.com")).To(Equal(routev1.WildcardPolicySubdomain))
+		})
+	})
+
+	Context("When a service exposes non-TCP ports", func() {
+		It("should select a TCP port over a UDP one", func() {
+			ports := []corev1.ServicePort{
+				{Name: "https-quic", Port: 443, Protocol: corev1.ProtocolUDP},
+				{Name: "https", Port: 8443, Protocol: corev1.ProtocolTCP},
+			}
+			Expect(selectHTTPPort(ports, nil, nil, DefaultManagementPorts).Port).To(Equal(int32(8443)))
+		})
+
+		It("should skip a UDP-only service with a NoTCPPort event", func() {
+			svc := newLoadBalancerService("dns", "infra")
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP}}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("NoTCPPort")))
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(BeEmpty())
+
+			By("not repeating the warning on the next reconcile")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("NoTCPPort")))
+		})
+	})
+
+	Context("When publishing build info", func() {
+		It("should register tinylb_build_info with the build's labels", func() {
+			SetBuildInfo("v1.2.3", "abc1234")
+
+			count, err := testutil.GatherAndCount(metrics.Registry, "tinylb_build_info")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+			Expect(testutil.ToFloat64(buildInfo.WithLabelValues("v1.2.3", "abc1234", goruntime.Version()))).To(Equal(1.0))
+		})
+	})
+
+	Context("When the service does not allocate node ports", func() {
+		It("should target the service port", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.AllocateLoadBalancerNodePorts = ptr.To(false)
+			svc.Spec.Ports[0].NodePort = 0
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.Port.TargetPort).To(Equal(intstr.FromInt(443)))
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(HaveLen(1))
+			Expect(current.Status.LoadBalancer.Ingress[0].Hostname).To(Equal(route.Spec.Host))
+		})
+	})
+
+	Context("When status-only updates are ignored", func() {
+		update := func(mutate func(*corev1.Service)) event.UpdateEvent {
+			old := newLoadBalancerService("web", "shop")
+			updated := old.DeepCopy()
+			mutate(updated)
+			return event.UpdateEvent{ObjectOld: old, ObjectNew: updated}
+		}
+
+		It("should filter a status-only update", func() {
+			Expect(serviceSpecChangePredicate().Update(update(func(svc *corev1.Service) {
+				svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "web.example.com"}}
+			}))).To(BeFalse())
+		})
+
+		It("should admit a spec change", func() {
+			Expect(serviceSpecChangePredicate().Update(update(func(svc *corev1.Service) {
+				svc.Spec.Ports[0].Port = 8443
+			}))).To(BeTrue())
+			Expect(serviceSpecChangePredicate().Update(update(func(svc *corev1.Service) {
+				svc.Spec.Type = corev1.ServiceTypeClusterIP
+			}))).To(BeTrue())
+			Expect(serviceSpecChangePredicate().Update(update(func(svc *corev1.Service) {
+				svc.Spec.LoadBalancerSourceRanges = []string{"10.0.0.0/8"}
+			}))).To(BeTrue())
+		})
+
+		It("should admit annotation and label changes", func() {
+			Expect(serviceSpecChangePredicate().Update(update(func(svc *corev1.Service) {
+				svc.Annotations = map[string]string{drainAnnotation: "true"}
+			}))).To(BeTrue())
+			Expect(serviceSpecChangePredicate().Update(update(func(svc *corev1.Service) {
+				svc.Labels = map[string]string{"team": "payments"}
+			}))).To(BeTrue())
+		})
+	})
+
+	Context("When RouteBindings are enabled", func() {
+		getBinding := func(c client.Client, svc *corev1.Service) *networkingv1alpha1.RouteBinding {
+			var binding networkingv1alpha1.RouteBinding
+			Expect(c.Get(context.Background(), client.ObjectKey{Namespace: svc.Namespace, Name: RouteName(svc)}, &binding)).To(Succeed())
+			return &binding
+		}
+
+		It("should track the Route from creation to admission", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10), RouteBindings: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			binding := getBinding(r.Client, svc)
+			Expect(binding.Spec.ServiceName).To(Equal("web"))
+			Expect(binding.Spec.RouteName).To(Equal(RouteName(svc)))
+			Expect(binding.OwnerReferences).To(HaveLen(1))
+			Expect(meta.IsStatusConditionTrue(binding.Status.Conditions, networkingv1alpha1.RouteBindingConditionRouteCreated)).To(BeTrue())
+			admitted := meta.FindStatusCondition(binding.Status.Conditions, networkingv1alpha1.RouteBindingConditionAdmitted)
+			Expect(admitted.Status).To(Equal(metav1.ConditionUnknown))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(binding.Status.Host).To(Equal(route.Spec.Host))
+			route.Status.Ingress = []routev1.RouteIngress{{
+				Host: route.Spec.Host, RouterName: "default",
+				Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+			}}
+			Expect(r.Update(context.Background(), &route)).To(Succeed())
+
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(getBinding(r.Client, svc).Status.Conditions, networkingv1alpha1.RouteBindingConditionAdmitted)).To(BeTrue())
+		})
+
+		It("should record a Route creation failure", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc).
+				WithStatusSubresource(&corev1.Service{}, &networkingv1alpha1.RouteBinding{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if _, ok := obj.(*routev1.Route); ok {
+							return apierrors.NewForbidden(routev1.Resource("routes"), obj.GetName(), errors.New("exceeded quota"))
+						}
+						return c.Create(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			r := &ServiceReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), RouteBindings: true}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).To(HaveOccurred())
+
+			created := meta.FindStatusCondition(getBinding(c, svc).Status.Conditions, networkingv1alpha1.RouteBindingConditionRouteCreated)
+			Expect(created).NotTo(BeNil())
+			Expect(created.Status).To(Equal(metav1.ConditionFalse))
+			Expect(created.Reason).To(Equal("CreateFailed"))
+			Expect(created.Message).To(ContainSubstring("exceeded quota"))
+		})
+
+		It("should not create RouteBindings unless enabled", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var bindings networkingv1alpha1.RouteBindingList
+			Expect(r.List(context.Background(), &bindings)).To(Succeed())
+			Expect(bindings.Items).To(BeEmpty())
+		})
+	})
+
+	Context("When Route weights are configured", func() {
+		routeFor := func(r *ServiceReconciler, svc *corev1.Service) *routev1.Route {
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: svc.Namespace, Name: RouteName(svc)}, &route)).To(Succeed())
+			return &route
+		}
+
+		It("should leave the router default without configuration", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			Expect(routeFor(r, svc).Spec.To.Weight).To(BeNil())
+		})
+
+		It("should apply the flag default", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10), DefaultRouteWeight: ptr.To[int32](50)}
+			Expect(routeFor(r, svc).Spec.To.Weight).To(HaveValue(Equal(int32(50))))
+		})
+
+		It("should let the annotation override the flag default", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{routeWeightAnnotation: "200"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10), DefaultRouteWeight: ptr.To[int32](50)}
+			Expect(routeFor(r, svc).Spec.To.Weight).To(HaveValue(Equal(int32(200))))
+		})
+
+		It("should update existing Routes when the weight changes", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			Expect(routeFor(r, svc).Spec.To.Weight).To(BeNil())
+
+			r.DefaultRouteWeight = ptr.To[int32](10)
+			Expect(routeFor(r, svc).Spec.To.Weight).To(HaveValue(Equal(int32(10))))
+		})
+
+		It("should ignore out-of-range annotations with a Warning", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{routeWeightAnnotation: "300"}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, DefaultRouteWeight: ptr.To[int32](50)}
+			Expect(routeFor(r, svc).Spec.To.Weight).To(HaveValue(Equal(int32(50))))
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidAnnotation")))
+
+			By("not repeating the warning on the next reconcile")
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("InvalidAnnotation")))
+		})
+
+		It("should validate the flag range", func() {
+			Expect(ValidateRouteWeight(0)).To(Succeed())
+			Expect(ValidateRouteWeight(256)).To(Succeed())
+			Expect(ValidateRouteWeight(257)).NotTo(Succeed())
+			Expect(ValidateRouteWeight(-2)).NotTo(Succeed())
+		})
+	})
+
+	Context("When TinyLB writes its own service annotations", func() {
+		It("should preserve unrelated annotations, including concurrent writes", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{"service.kubernetes.io/topology-mode": "Auto"}
+			scheme := newTestScheme()
+			patched := false
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc).
+				WithStatusSubresource(&corev1.Service{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if _, ok := obj.(*corev1.Service); ok && !patched {
+							// Another controller annotates the service between TinyLB's read and its write
+							var current corev1.Service
+							Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), &current)).To(Succeed())
+							current.Annotations["example.com/owner"] = "team-a"
+							Expect(c.Update(ctx, &current)).To(Succeed())
+							patched = true
+						}
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).
+				Build()
+			r := &ServiceReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patched).To(BeTrue())
+
+			var current corev1.Service
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).To(HaveKeyWithValue("service.kubernetes.io/topology-mode", "Auto"))
+			Expect(current.Annotations).To(HaveKeyWithValue("example.com/owner", "team-a"))
+			Expect(current.Annotations).To(HaveKey(baseDomainAnnotation))
+			Expect(current.Status.LoadBalancer.Ingress).To(HaveLen(1))
+		})
+	})
+
+	Context("When a TinyLB service is of type ExternalName", func() {
+		newExternalNameService := func() *corev1.Service {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Type = corev1.ServiceTypeExternalName
+			svc.Spec.ExternalName = "web.example.net"
+			svc.Spec.Ports = nil
+			svc.Annotations = map[string]string{baseDomainAnnotation: "apps.example.com"}
+			return svc
+		}
+
+		It("should report it as unsupported instead of creating a Route", func() {
+			svc := newExternalNameService()
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("ExternalNameUnsupported")))
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(BeEmpty())
+
+			By("not repeating the warning on the next reconcile")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("ExternalNameUnsupported")))
+		})
+
+		It("should only admit ExternalName services meant for TinyLB", func() {
+			filter := serviceEventFilter("")
+			Expect(filter.Generic(event.GenericEvent{Object: newExternalNameService()})).To(BeTrue())
+
+			plain := newExternalNameService()
+			plain.Annotations = nil
+			Expect(filter.Generic(event.GenericEvent{Object: plain})).To(BeFalse())
+		})
+	})
+
+	Context("When a Route is deleted", func() {
+		It("should stop advertising its host until the Route is recreated", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			failCreate := false
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc).
+				WithStatusSubresource(&corev1.Service{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if _, ok := obj.(*routev1.Route); ok && failCreate {
+							return apierrors.NewInternalError(errors.New("admission webhook unavailable"))
+						}
+						return c.Create(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(ConsistOf(corev1.LoadBalancerIngress{Hostname: route.Spec.Host}))
+
+			Expect(r.Delete(context.Background(), &route)).To(Succeed())
+			failCreate = true
+			_, err = reconcileService(r, svc)
+			Expect(err).To(HaveOccurred())
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("RouteMissing")))
+
+			failCreate = false
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(ConsistOf(corev1.LoadBalancerIngress{Hostname: route.Spec.Host}))
+		})
+	})
+
+	Context("When preferred ports are configured", func() {
+		ports := []corev1.ServicePort{
+			{Name: "https", Port: 443},
+			{Name: "web", Port: 9443},
+			{Name: "http", Port: 80},
+		}
+
+		It("should keep the standard tiers by default", func() {
+			Expect(selectHTTPPort(ports, nil, nil, DefaultManagementPorts).Port).To(Equal(int32(443)))
+		})
+
+		It("should select preferred ports first, in order", func() {
+			Expect(selectHTTPPort(ports, []int32{9443, 443}, nil, DefaultManagementPorts).Port).To(Equal(int32(9443)))
+			Expect(selectHTTPPort(ports, []int32{80, 9443}, nil, DefaultManagementPorts).Port).To(Equal(int32(80)))
+		})
+
+		It("should fall back to the name hints when no preferred port is exposed", func() {
+			Expect(selectHTTPPort(ports, []int32{6443}, nil, DefaultManagementPorts).Port).To(Equal(int32(443)))
+			Expect(selectHTTPPort(ports[1:], []int32{6443}, nil, DefaultManagementPorts).Port).To(Equal(int32(80)))
+		})
+
+		It("should use them for the Route target port", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports = ports
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10), PreferredPorts: []int32{9443}}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.Port.TargetPort).To(Equal(intstr.FromInt32(9443)))
+		})
+	})
+
+	Context("When service events are debounced", func() {
+		It("should coalesce rapid events into fewer Route writes", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			routeWrites := 0
+			countRouteWrite := func(obj client.Object) {
+				if _, ok := obj.(*routev1.Route); ok {
+					routeWrites++
+				}
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(svc).
+				WithStatusSubresource(&corev1.Service{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						countRouteWrite(obj)
+						return c.Create(ctx, obj, opts...)
+					},
+					Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+						countRouteWrite(obj)
+						return c.Update(ctx, obj, opts...)
+					},
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						countRouteWrite(obj)
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).
+				Build()
+			r := &ServiceReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+
+			queue := newDebounceQueue("service", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](), 100*time.Millisecond)
+			defer queue.ShutDown()
+
+			// A flapping service: each change is an event enqueueing the service
+			const events = 10
+			request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(svc)}
+			for i := range events {
+				var current corev1.Service
+				Expect(c.Get(context.Background(), request.NamespacedName, &current)).To(Succeed())
+				current.Annotations = map[string]string{"rollout.example.com/step": string(rune('a' + i))}
+				Expect(c.Update(context.Background(), &current)).To(Succeed())
+				queue.Add(request)
+				Expect(queue.Len()).To(BeZero())
+			}
+
+			Eventually(queue.Len).Should(Equal(1))
+			item, _ := queue.Get()
+			_, err := r.Reconcile(context.Background(), item)
+			Expect(err).NotTo(HaveOccurred())
+			queue.Done(item)
+
+			Consistently(queue.Len, 200*time.Millisecond).Should(BeZero())
+			Expect(routeWrites).To(BeNumerically(">", 0))
+			Expect(routeWrites).To(BeNumerically("<", events))
+		})
+	})
+
+	Context("When generating hosts with a strategy", func() {
+		var svc *corev1.Service
+
+		BeforeEach(func() {
+			svc = newLoadBalancerService("web", "shop")
+		})
+
+		It("should fill in the template", func() {
+			Expect(TemplateHostStrategy{BaseDomain: "apps.example.com"}.Host(svc)).To(Equal("web-shop.apps.example.com"))
+			Expect(TemplateHostStrategy{BaseDomain: "apps.example.com", Suffix: "-dev"}.Host(svc)).To(Equal("web-shop-dev.apps.example.com"))
+		})
+
+		It("should use the annotated host, falling back without one", func() {
+			strategy := AnnotationHostStrategy{Fallback: TemplateHostStrategy{BaseDomain: "apps.example.com"}}
+			Expect(strategy.Host(svc)).To(Equal("web-shop.apps.example.com"))
+
+			svc.Annotations = map[string]string{externalDNSHostnameAnnotation: "www.example.com."}
+			Expect(strategy.Host(svc)).To(Equal("www.example.com"))
+		})
+
+		It("should give each namespace its own subdomain", func() {
+			Expect(SubdomainHostStrategy{BaseDomain: "apps.example.com"}.Host(svc)).To(Equal("web.shop.apps.example.com"))
+			Expect(SubdomainHostStrategy{BaseDomain: "apps.example.com", Suffix: "-dev"}.Host(svc)).To(Equal("web-dev.shop.apps.example.com"))
+		})
+
+		It("should select the strategy by flag, then by annotation", func() {
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Recorder: recorder, DefaultHostStrategy: HostStrategySubdomain}
+			Expect(r.routeHost(svc, "apps.example.com")).To(Equal("web.shop.apps.example.com"))
+
+			svc.Annotations = map[string]string{hostStrategyAnnotation: HostStrategyTemplate}
+			Expect(r.routeHost(svc, "apps.example.com")).To(Equal("web-shop.apps.example.com"))
+
+			svc.Annotations[hostStrategyAnnotation] = "random"
+			Expect(r.routeHost(svc, "apps.example.com")).To(Equal("web.shop.apps.example.com"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("unknown host strategy")))
+
+			By("not repeating the warning for the next base domain or reconcile")
+			Expect(r.routeHost(svc, "apps.internal")).To(Equal("web.shop.apps.internal"))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should only honor explicit hosts when enabled", func() {
+			recorder := record.NewFakeRecorder(10)
+			svc.Annotations = map[string]string{
+				hostStrategyAnnotation:        HostStrategyAnnotation,
+				externalDNSHostnameAnnotation: "www.example.com",
+			}
+			r := &ServiceReconciler{Recorder: recorder}
+			Expect(r.routeHost(svc, "apps.example.com")).To(Equal("web-shop.apps.example.com"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("explicit hosts are not enabled")))
+
+			r = &ServiceReconciler{Recorder: recorder, DefaultHostStrategy: HostStrategyAnnotation}
+			host, explicit, err := r.routeHost(svc, "apps.example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(host).To(Equal("www.example.com"))
+			Expect(explicit).To(BeTrue())
+		})
+
+		It("should validate strategy names", func() {
+			Expect(ValidateHostStrategy(HostStrategyTemplate)).To(Succeed())
+			Expect(ValidateHostStrategy(HostStrategyAnnotation)).To(Succeed())
+			Expect(ValidateHostStrategy(HostStrategySubdomain)).To(Succeed())
+			Expect(ValidateHostStrategy("")).NotTo(Succeed())
+		})
+	})
+
+	Context("When recording the Route target port", func() {
+		It("should annotate the service with the Route's target port", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "metrics", Port: 9090}, {Name: "https", Port: 8443}}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, RecordTargetPort: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).To(HaveKeyWithValue(routeTargetPortAnnotation, route.Spec.Port.TargetPort.String()))
+			Expect(current.Annotations[routeTargetPortAnnotation]).To(Equal("8443"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("targets service port 8443")))
+
+			// Unchanged ports are not reported again
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should follow a Route targeting a named port", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "https", Port: 443, TargetPort: intstr.FromString("web-tls")}}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10), RecordTargetPort: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).To(HaveKeyWithValue(routeTargetPortAnnotation, "https"))
+		})
+
+		It("should not annotate services unless enabled", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Annotations).NotTo(HaveKey(routeTargetPortAnnotation))
+		})
+	})
+
+	Context("When managing DNS records", func() {
+		var (
+			svc *corev1.Service
+			r   *ServiceReconciler
+		)
+		admit := func(canonical string) {
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			route.Status.Ingress = []routev1.RouteIngress{{
+				Host:                    route.Spec.Host,
+				RouterName:              "default",
+				RouterCanonicalHostname: canonical,
+				Conditions:              []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+			}}
+			Expect(r.Update(context.Background(), &route)).To(Succeed())
+		}
+		getEndpoints := func() []any {
+			endpoint := newDNSEndpoint()
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, endpoint)).To(Succeed())
+			endpoints, _, err := unstructured.NestedSlice(endpoint.Object, "spec", "endpoints")
+			Expect(err).NotTo(HaveOccurred())
+			return endpoints
+		}
+
+		BeforeEach(func() {
+			svc = newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r = &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10),
+				BaseDomains: []string{"apps.example.com"}, ManageDNS: true}
+		})
+
+		It("should point the host at the router once the Route is admitted", func() {
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apierrors.IsNotFound(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, newDNSEndpoint()))).To(BeTrue())
+
+			admit("router-default.apps.example.com")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getEndpoints()).To(Equal([]any{map[string]any{
+				"dnsName":    "web-shop.apps.example.com",
+				"recordType": "CNAME",
+				"targets":    []any{"router-default.apps.example.com"},
+			}}))
+
+			endpoint := newDNSEndpoint()
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, endpoint)).To(Succeed())
+			Expect(endpoint.GetOwnerReferences()).To(HaveLen(1))
+			Expect(endpoint.GetOwnerReferences()[0].Name).To(Equal("web"))
+		})
+
+		It("should follow the router's canonical hostname", func() {
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			admit("router-default.apps.example.com")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			admit("router-sharded.apps.example.com")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getEndpoints()[0]).To(HaveKeyWithValue("targets", []any{"router-sharded.apps.example.com"}))
+		})
+
+		It("should leave a DNSEndpoint it does not own alone", func() {
+			foreign := newDNSEndpoint()
+			foreign.SetName(RouteName(svc))
+			foreign.SetNamespace("shop")
+			Expect(unstructured.SetNestedSlice(foreign.Object, []any{map[string]any{"dnsName": "other.example.com"}}, "spec", "endpoints")).To(Succeed())
+			Expect(r.Create(context.Background(), foreign)).To(Succeed())
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			admit("router-default.apps.example.com")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getEndpoints()[0]).To(HaveKeyWithValue("dnsName", "other.example.com"))
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("DNSEndpointConflict")))
+
+			By("not repeating the warning while the conflict stands")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Recorder.(*record.FakeRecorder).Events).NotTo(Receive(ContainSubstring("DNSEndpointConflict")))
+		})
+
+		It("should not create DNSEndpoints unless enabled", func() {
+			r.ManageDNS = false
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			admit("router-default.apps.example.com")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apierrors.IsNotFound(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, newDNSEndpoint()))).To(BeTrue())
+		})
+	})
+
+	Context("When dispatching on the service type", func() {
+		expose := func(r *ServiceReconciler, svc *corev1.Service) (serviceExposure, bool) {
+			handler, ok := serviceTypeHandlers[svc.Spec.Type]
+			if !ok {
+				return exposureNone, false
+			}
+			return handler(r, context.Background(), svc), true
+		}
+
+		It("should expose LoadBalancer services through a Route", func() {
+			r := &ServiceReconciler{Recorder: record.NewFakeRecorder(10)}
+			exposure, handled := expose(r, newLoadBalancerService("web", "shop"))
+			Expect(handled).To(BeTrue())
+			Expect(exposure).To(Equal(exposureRoute))
+		})
+
+		It("should not expose LoadBalancer services a Route cannot reach", func() {
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Recorder: recorder}
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.ClusterIP = corev1.ClusterIPNone
+			exposure, handled := expose(r, svc)
+			Expect(handled).To(BeTrue())
+			Expect(exposure).To(Equal(exposureNone))
+			Expect(recorder.Events).To(Receive(ContainSubstring("HeadlessUnsupported")))
+		})
+
+		It("should report ExternalName services meant for TinyLB", func() {
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Recorder: recorder}
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Type = corev1.ServiceTypeExternalName
+			svc.Annotations = map[string]string{forceAnnotation: "true"}
+			exposure, handled := expose(r, svc)
+			Expect(handled).To(BeTrue())
+			Expect(exposure).To(Equal(exposureNone))
+			Expect(recorder.Events).To(Receive(ContainSubstring("ExternalNameUnsupported")))
+		})
+
+		It("should have no handler for other types", func() {
+			for _, serviceType := range []corev1.ServiceType{corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort} {
+				svc := newLoadBalancerService("web", "shop")
+				svc.Spec.Type = serviceType
+				_, handled := expose(&ServiceReconciler{}, svc)
+				Expect(handled).To(BeFalse(), string(serviceType))
+			}
+		})
+	})
+
+	Context("When another load balancer provider shares the cluster", func() {
+		withProvider := func(name, provider string) *corev1.Service {
+			svc := newLoadBalancerService(name, "default")
+			if provider != "" {
+				svc.Annotations = map[string]string{ProviderAnnotation: provider}
+			}
+			return svc
+		}
+		routeCount := func(r *ServiceReconciler) int {
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			return len(routes.Items)
+		}
+
+		It("should only admit opted-in services when the provider annotation is the opt-in", func() {
+			filter := predicate.And(serviceEventFilter(ProviderAnnotation), providerFilter())
+			Expect(filter.Create(event.CreateEvent{Object: withProvider("web", "")})).To(BeFalse())
+			Expect(filter.Create(event.CreateEvent{Object: withProvider("web", "other")})).To(BeFalse())
+			Expect(filter.Create(event.CreateEvent{Object: withProvider("web", providerTinyLB)})).To(BeTrue())
+
+			filter = predicate.And(serviceEventFilter(""), providerFilter())
+			Expect(filter.Create(event.CreateEvent{Object: withProvider("web", "")})).To(BeTrue())
+			Expect(filter.Create(event.CreateEvent{Object: withProvider("web", "other")})).To(BeFalse())
+		})
+
+		It("should recheck the opt-in annotation when reconciling", func() {
+			svc := newLoadBalancerService("web", "default")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, OptInAnnotation: "example.com/expose"}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routeCount(r)).To(Equal(0))
+
+			svc.Annotations = map[string]string{"example.com/expose": "", ProviderAnnotation: "metallb"}
+			Expect(r.Update(context.Background(), svc)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routeCount(r)).To(Equal(0))
+		})
+
+		It("should not create a Route for services without the provider annotation", func() {
+			svc := withProvider("web", "")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, OptInAnnotation: ProviderAnnotation}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routeCount(r)).To(Equal(0))
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(BeEmpty())
+		})
+
+		It("should create a Route for opted-in services", func() {
+			svc := withProvider("web", providerTinyLB)
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, OptInAnnotation: ProviderAnnotation}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routeCount(r)).To(Equal(1))
+		})
+
+		It("should leave services naming another provider alone even without opt-in", func() {
+			svc := withProvider("web", "metallb")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routeCount(r)).To(Equal(0))
+		})
+	})
+
+	Context("When service and namespace names are too long for one host label", func() {
+		longName := func(prefix string) string {
+			return prefix + strings.Repeat("x", 45-len(prefix))
+		}
+
+		It("should truncate the label and keep it unique with a hash", func() {
+			svc := newLoadBalancerService(longName("web-"), longName("shop-"))
+			other := newLoadBalancerService(longName("web-")+"y", longName("shop-"))
+
+			r := &ServiceReconciler{BaseDomains: []string{"apps.example.com"}}
+			host, err := r.RouteHost(svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validateHost(host)).To(Succeed())
+			label, _, _ := strings.Cut(host, ".")
+			Expect(len(label)).To(Equal(63))
+			Expect(label).To(HavePrefix("web-"))
+			Expect(r.RouteHost(svc)).To(Equal(host))
+			Expect(r.RouteHost(other)).NotTo(Equal(host))
+		})
+
+		It("should keep the host suffix", func() {
+			svc := newLoadBalancerService(longName("web-"), longName("shop-"))
+			for _, strategy := range []HostStrategy{
+				TemplateHostStrategy{BaseDomain: "apps.example.com", Suffix: "-dev"},
+				SubdomainHostStrategy{BaseDomain: "apps.example.com", Suffix: strings.Repeat("-dev", 5)},
+			} {
+				host, err := strategy.Host(svc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(validateHost(host)).To(Succeed())
+				label, _, _ := strings.Cut(host, ".")
+				Expect(label).To(HaveSuffix("-dev"))
+			}
+		})
+
+		It("should leave labels within the limit untouched", func() {
+			Expect(hostLabel("web-shop", "-dev")).To(Equal("web-shop-dev"))
+			Expect(hostLabel(strings.Repeat("a", 63), "")).To(Equal(strings.Repeat("a", 63)))
+		})
+
+		It("should create a Route with a valid host", func() {
+			svc := newLoadBalancerService(longName("web-"), longName("shop-"))
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: svc.Namespace, Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.Host).To(Equal(defaultRouteHost(svc)))
+			Expect(validateHost(route.Spec.Host)).To(Succeed())
+		})
+	})
+
+	Context("When Route ports are referenced by name", func() {
+		It("should reference every named service port by name", func() {
+			Expect(routeTargetPort(corev1.ServicePort{Name: "https", Port: 443, TargetPort: intstr.FromInt(8443)}, true)).
+				To(Equal(intstr.FromString("https")))
+			Expect(routeTargetPort(corev1.ServicePort{Port: 443, TargetPort: intstr.FromInt(8443)}, true)).
+				To(Equal(intstr.FromInt(443)))
+		})
+
+		It("should create the Route with the port name", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports[0].TargetPort = intstr.FromInt(8443)
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, RoutePortByName: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.Port.TargetPort).To(Equal(intstr.FromString("https")))
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("UnnamedPort")))
+		})
+
+		It("should fall back to the port number and warn for unnamed ports", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports[0].Name = ""
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, RoutePortByName: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.Port.TargetPort).To(Equal(intstr.FromInt(443)))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning UnnamedPort")))
+
+			By("not repeating the warning while the port stays unnamed")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("UnnamedPort")))
+		})
+	})
+
+	Context("When concurrent reconciles assign the same host", func() {
+		reconcileConcurrently := func(r *ServiceReconciler, services ...*corev1.Service) {
+			var wg sync.WaitGroup
+			for _, svc := range services {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					_, err := reconcileService(r, svc)
+					Expect(err).NotTo(HaveOccurred())
+				}()
+			}
+			wg.Wait()
+		}
+		routeHosts := func(r *ServiceReconciler) []string {
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			var hosts []string
+			for _, route := range routes.Items {
+				hosts = append(hosts, route.Spec.Host)
+			}
+			return hosts
+		}
+
+		It("should give services with colliding generated hosts distinct hosts", func() {
+			// Both generate a-b-c.apps.example.com
+			first := newLoadBalancerService("a-b", "c")
+			second := newLoadBalancerService("a", "b-c")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, first, second), Scheme: scheme,
+				Recorder: record.NewFakeRecorder(10), BaseDomains: []string{"apps.example.com"}}
+
+			reconcileConcurrently(r, first, second)
+
+			hosts := routeHosts(r)
+			Expect(hosts).To(HaveLen(2))
+			Expect(hosts).To(ContainElement("a-b-c.apps.example.com"))
+			Expect(hosts[0]).NotTo(Equal(hosts[1]))
+			for _, host := range hosts {
+				Expect(validateHost(host)).To(Succeed())
+			}
+		})
+
+		It("should create one Route for an explicit host requested twice", func() {
+			first := newLoadBalancerService("web", "shop")
+			second := newLoadBalancerService("api", "shop")
+			for _, svc := range []*corev1.Service{first, second} {
+				svc.Annotations = map[string]string{externalDNSHostnameAnnotation: "www.example.com"}
+			}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, first, second), Scheme: scheme,
+				Recorder: recorder, HonorExternalDNSHostname: true}
+
+			reconcileConcurrently(r, first, second)
+
+			Expect(routeHosts(r)).To(Equal([]string{"www.example.com"}))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning HostConflict")))
+		})
+
+		It("should release the host of a deleted service", func() {
+			var claims hostClaims
+			web := types.NamespacedName{Namespace: "shop", Name: "web"}
+			api := types.NamespacedName{Namespace: "shop", Name: "api"}
+			_, ok := claims.claim(web, "www.example.com")
+			Expect(ok).To(BeTrue())
+			owner, ok := claims.claim(api, "www.example.com")
+			Expect(ok).To(BeFalse())
+			Expect(owner).To(Equal(web))
+
+			claims.release(web)
+			_, ok = claims.claim(api, "www.example.com")
+			Expect(ok).To(BeTrue())
+		})
+	})
+
+	Context("When the service port speaks cleartext HTTP/2", func() {
+		newH2CService := func() *corev1.Service {
+			svc := newLoadBalancerService("grpc", "shop")
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "h2c", Port: 8080, AppProtocol: ptr.To(h2cAppProtocol)}}
+			return svc
+		}
+		getRoute := func(r *ServiceReconciler, svc *corev1.Service) *routev1.Route {
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: svc.Namespace, Name: RouteName(svc)}, &route)).To(Succeed())
+			return &route
+		}
+
+		It("should create a plain HTTP Route with HTTP/2 enabled", func() {
+			svc := newH2CService()
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			route := getRoute(r, svc)
+			Expect(route.Spec.TLS).To(BeNil())
+			Expect(route.Spec.Port.TargetPort).To(Equal(intstr.FromInt(8080)))
+			Expect(route.Annotations).To(HaveKeyWithValue(disableHTTP2RouteAnnotation, "false"))
+		})
+
+		It("should let the service turn HTTP/2 off", func() {
+			svc := newH2CService()
+			svc.Annotations = map[string]string{http2Annotation: "false"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getRoute(r, svc).Annotations).To(HaveKeyWithValue(disableHTTP2RouteAnnotation, "true"))
+		})
+
+		It("should switch an existing passthrough Route to plain HTTP and back", func() {
+			svc := newLoadBalancerService("grpc", "shop")
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "h2c", Port: 8080}}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getRoute(r, svc).Spec.TLS.Termination).To(Equal(routev1.TLSTerminationPassthrough))
+
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), svc)).To(Succeed())
+			svc.Spec.Ports[0].AppProtocol = ptr.To(h2cAppProtocol)
+			Expect(r.Update(context.Background(), svc)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getRoute(r, svc).Spec.TLS).To(BeNil())
+
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), svc)).To(Succeed())
+			svc.Spec.Ports[0].AppProtocol = nil
+			Expect(r.Update(context.Background(), svc)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			route := getRoute(r, svc)
+			Expect(route.Spec.TLS.Termination).To(Equal(routev1.TLSTerminationPassthrough))
+			Expect(route.Annotations).NotTo(HaveKey(disableHTTP2RouteAnnotation))
+		})
+	})
+
+	Context("When a service lists a port number twice", func() {
+		ports := []corev1.ServicePort{
+			{Name: "metrics", Port: 9000},
+			{Name: "https-admin", Port: 9000},
+			{Name: "grpc", Port: 7000},
+		}
+
+		It("should select the first entry of the repeated port", func() {
+			unique, duplicates := uniquePorts(ports)
+			Expect(unique).To(HaveLen(2))
+			Expect(duplicates).To(Equal([]int32{9000}))
+
+			// The name hint of the second entry must not win over the first entry
+			for range 5 {
+				selected := selectHTTPPort(ports, nil, nil, DefaultManagementPorts)
+				Expect(selected.Name).To(Equal("metrics"))
+			}
+		})
+
+		It("should warn about the duplicate ports", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports = ports
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(And(HavePrefix("Warning DuplicatePorts"), ContainSubstring("9000"))))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.Port.TargetPort).To(Equal(intstr.FromInt(9000)))
+
+			By("not repeating the warning while the ports are unchanged")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("DuplicatePorts")))
+		})
+	})
+
+	Context("When a service exposes only named ports", func() {
+		ports := []corev1.ServicePort{
+			{Name: "https-admin", Port: 7443},
+			{Name: "grpc", Port: 7000},
+			{Name: "web", Port: 7080},
+		}
+
+		It("should prefer https then http names by default", func() {
+			Expect(selectHTTPPort(ports, nil, nil, DefaultManagementPorts).Port).To(Equal(int32(7443)))
+		})
+
+		It("should follow the name priority list", func() {
+			Expect(selectHTTPPort(ports, nil, []string{"grpc", "https"}, DefaultManagementPorts).Port).To(Equal(int32(7000)))
+			Expect(selectHTTPPort(ports, nil, []string{"WEB", "grpc"}, DefaultManagementPorts).Port).To(Equal(int32(7080)))
+			Expect(selectHTTPPort(ports[1:], nil, []string{"https", "http", "web", "grpc"}, DefaultManagementPorts).Port).To(Equal(int32(7080)))
+		})
+
+		It("should use the name priority list for the Route target port", func() {
+			svc := newLoadBalancerService("api", "shop")
+			svc.Spec.Ports = ports
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: record.NewFakeRecorder(10), PortNamePriority: []string{"grpc", "https"}}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.Port.TargetPort).To(Equal(intstr.FromInt32(7000)))
+		})
+	})
+
+	Context("When a service's ingress already advertises a node IP", func() {
+		newNode := func() *corev1.Node {
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+				Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+					{Type: corev1.NodeInternalIP, Address: "10.0.0.5"},
+					{Type: corev1.NodeHostName, Address: "worker-0"},
+				}},
+			}
+		}
+		newNodeIngressService := func(ip string) *corev1.Service {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: ip}}
+			return svc
+		}
+
+		It("should preserve the node IP ingress and create no Route", func() {
+			svc := newNodeIngressService("10.0.0.5")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, newNode()), Scheme: scheme, PreserveNodeIngress: true}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(BeEmpty())
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(Equal([]corev1.LoadBalancerIngress{{IP: "10.0.0.5"}}))
+		})
+
+		It("should take over ingress that is not a node IP when replacing", func() {
+			svc := newNodeIngressService("192.0.2.10")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, newNode()), Scheme: scheme, Recorder: record.NewFakeRecorder(10),
+				PreserveNodeIngress: true, IngressMergeStrategy: IngressMergeReplace}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes)).To(Succeed())
+			Expect(routes.Items).To(HaveLen(1))
+		})
+
+		It("should override the node IP ingress when forced", func() {
+			svc := newNodeIngressService("10.0.0.5")
+			svc.Annotations = map[string]string{forceAnnotation: "true"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, newNode()), Scheme: scheme, Recorder: record.NewFakeRecorder(10), PreserveNodeIngress: true}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).To(HaveLen(1))
+			Expect(current.Status.LoadBalancer.Ingress[0].Hostname).NotTo(BeEmpty())
+		})
+	})
+
+	Context("When writes are audited", func() {
+		It("should record each Route write and status change with its field manager", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			var buf strings.Builder
+			c := NewAuditClient(newFakeClient(scheme, svc), NewJSONAuditSink(&buf), "tinylb")
+			r := &ServiceReconciler{Client: c, Scheme: scheme}
+			entries := func() []AuditEntry {
+				var parsed []AuditEntry
+				for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+					var entry AuditEntry
+					Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+					Expect(entry.FieldManager).To(Equal("tinylb"))
+					Expect(entry.Time).NotTo(BeZero())
+					parsed = append(parsed, entry)
+				}
+				buf.Reset()
+				return parsed
+			}
+			routeKey := client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries()).To(ContainElements(
+				And(HaveField("Action", "create"), HaveField("Kind", "Route"), HaveField("Name", RouteName(svc))),
+				And(HaveField("Action", "update-status"), HaveField("Kind", "Service"), HaveField("Name", "web")),
+			))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), routeKey, &route)).To(Succeed())
+			route.Spec.Host = "other.example.com"
+			Expect(r.Update(context.Background(), &route)).To(Succeed())
+			patch := client.MergeFrom(route.DeepCopy())
+			route.Labels["team"] = "shop"
+			Expect(r.Patch(context.Background(), &route, patch)).To(Succeed())
+			Expect(r.Delete(context.Background(), &route)).To(Succeed())
+			Expect(r.Delete(context.Background(), &route)).NotTo(Succeed())
+
+			audited := entries()
+			Expect(audited).To(HaveLen(4))
+			Expect(audited[0].Action).To(Equal("update"))
+			Expect(audited[1].Action).To(Equal("patch"))
+			Expect(audited[2].Action).To(Equal("delete"))
+			Expect(audited[2].Error).To(BeEmpty())
+			Expect(audited[3].Action).To(Equal("delete"))
+			Expect(audited[3].Error).NotTo(BeEmpty())
+		})
+
+		It("should not record writes to other objects", func() {
+			scheme := newTestScheme()
+			var buf strings.Builder
+			c := NewAuditClient(newFakeClient(scheme), NewJSONAuditSink(&buf), "tinylb")
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "tinylb-system"}}
+			Expect(c.Create(context.Background(), configMap)).To(Succeed())
+			Expect(buf.String()).To(BeEmpty())
+		})
+
+		It("should discard entries without a sink", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: NewAuditClient(newFakeClient(scheme, svc), nil, "tinylb"), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When a service sets loadBalancerSourceRanges", func() {
+		It("should map the ranges onto the Route allowlist", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.LoadBalancerSourceRanges = []string{"10.0.0.0/8", " 192.168.1.0/24", "2001:db8::/32"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Annotations).To(HaveKeyWithValue(ipAllowlistRouteAnnotation, "10.0.0.0/8 192.168.1.0/24 2001:db8::/32"))
+		})
+
+		It("should drop the allowlist once the ranges are cleared", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.LoadBalancerSourceRanges = []string{"10.0.0.0/8"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			current.Spec.LoadBalancerSourceRanges = nil
+			Expect(r.Update(context.Background(), &current)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Annotations).NotTo(HaveKey(ipAllowlistRouteAnnotation))
+		})
+
+		It("should keep an allowlist set on the Route by hand", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var route routev1.Route
+			key := types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}
+			Expect(r.Get(context.Background(), key, &route)).To(Succeed())
+			metav1.SetMetaDataAnnotation(&route.ObjectMeta, ipAllowlistRouteAnnotation, "172.16.0.0/12")
+			Expect(r.Update(context.Background(), &route)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Get(context.Background(), key, &route)).To(Succeed())
+			Expect(route.Annotations).To(HaveKeyWithValue(ipAllowlistRouteAnnotation, "172.16.0.0/12"))
+		})
+
+		It("should leave out entries that are not CIDRs", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.LoadBalancerSourceRanges = []string{"10.0.0.0/8", "office"}
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Recorder: recorder}
+			Expect(r.routeAnnotations(svc)).To(HaveKeyWithValue(ipAllowlistRouteAnnotation, "10.0.0.0/8"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidSourceRange")))
+		})
+
+		It("should admit no client when no entry is a CIDR", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.LoadBalancerSourceRanges = []string{"office", "10.0.0.0/33"}
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Recorder: recorder}
+			Expect(r.routeAnnotations(svc)).To(HaveKeyWithValue(ipAllowlistRouteAnnotation, denyAllAllowlist))
+			Expect(recorder.Events).To(Receive(ContainSubstring("admits no client")))
+
+			By("not repeating the warning on the next reconcile")
+			Expect(r.routeAnnotations(svc)).To(HaveKeyWithValue(ipAllowlistRouteAnnotation, denyAllAllowlist))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should read the ranges from the annotation when the field is not set", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{sourceRangesAnnotation: "10.0.0.0/8, 192.168.1.0/24"}
+			r := &ServiceReconciler{Recorder: record.NewFakeRecorder(10)}
+			Expect(r.routeAnnotations(svc)).To(HaveKeyWithValue(ipAllowlistRouteAnnotation, "10.0.0.0/8 192.168.1.0/24"))
+
+			svc.Spec.LoadBalancerSourceRanges = []string{"172.16.0.0/12"}
+			Expect(r.routeAnnotations(svc)).To(HaveKeyWithValue(ipAllowlistRouteAnnotation, "172.16.0.0/12"))
+		})
+
+		It("should leave the router default without ranges", func() {
+			r := &ServiceReconciler{}
+			Expect(r.routeAnnotations(newLoadBalancerService("web", "shop"))).NotTo(HaveKey(ipAllowlistRouteAnnotation))
+		})
+	})
+
+	Context("When Routes target companion services", func() {
+		companionKey := client.ObjectKey{Namespace: "shop", Name: "web-tinylb"}
+
+		It("should create a companion service and target it", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Selector = map[string]string{"app": "web"}
+			svc.Spec.Ports[0].NodePort = 31443
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, CompanionServices: true}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var companion corev1.Service
+			Expect(r.Get(context.Background(), companionKey, &companion)).To(Succeed())
+			Expect(companion.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+			Expect(companion.Spec.Selector).To(Equal(map[string]string{"app": "web"}))
+			Expect(companion.Spec.Ports).To(Equal([]corev1.ServicePort{{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP}}))
+			Expect(companion.OwnerReferences).To(HaveLen(1))
+			Expect(companion.OwnerReferences[0].UID).To(Equal(svc.UID))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.To.Name).To(Equal("web-tinylb"))
+		})
+
+		It("should keep the companion in sync with the service's selector and ports", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Selector = map[string]string{"app": "web"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, CompanionServices: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			current.Spec.Selector = map[string]string{"app": "web", "version": "v2"}
+			current.Spec.Ports = append(current.Spec.Ports, corev1.ServicePort{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP})
+			Expect(r.Update(context.Background(), &current)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var companion corev1.Service
+			Expect(r.Get(context.Background(), companionKey, &companion)).To(Succeed())
+			Expect(companion.Spec.Selector).To(HaveKeyWithValue("version", "v2"))
+			Expect(companion.Spec.Ports).To(HaveLen(2))
+		})
+
+		It("should remove the companion and target the service once disabled", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			c := newFakeClient(scheme, svc)
+			_, err := reconcileService(&ServiceReconciler{Client: c, Scheme: scheme, CompanionServices: true}, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			r := &ServiceReconciler{Client: c, Scheme: scheme}
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(apierrors.IsNotFound(r.Get(context.Background(), companionKey, &corev1.Service{}))).To(BeTrue())
+			var route routev1.Route
+			Expect(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &route)).To(Succeed())
+			Expect(route.Spec.To.Name).To(Equal("web"))
+		})
+
+		It("should leave a same-named service TinyLB did not create alone", func() {
+			svc := newLoadBalancerService("web", "shop")
+			other := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web-tinylb", Namespace: "shop"}}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, other), Scheme: scheme}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), companionKey, &corev1.Service{})).To(Succeed())
+		})
+
+		It("should not take over a same-named service TinyLB did not create", func() {
+			svc := newLoadBalancerService("web", "shop")
+			other := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-tinylb", Namespace: "shop"},
+				Spec: corev1.ServiceSpec{
+					Selector: map[string]string{"app": "other"},
+					Ports:    []corev1.ServicePort{{Name: "admin", Port: 9000, Protocol: corev1.ProtocolTCP}},
+				},
+			}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, other), Scheme: scheme, Recorder: recorder, CompanionServices: true}
+
+			result, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(recorder.Events).To(Receive(ContainSubstring("CompanionConflict")))
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), companionKey, &current)).To(Succeed())
+			Expect(current.Spec.Selector).To(Equal(map[string]string{"app": "other"}))
+			Expect(current.Spec.Ports[0].Port).To(Equal(int32(9000)))
+			Expect(apierrors.IsNotFound(r.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: RouteName(svc)}, &routev1.Route{}))).To(BeTrue())
+
+			By("not repeating the warning on the next reconcile")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("CompanionConflict")))
+		})
+	})
+
+	Context("When a LoadBalancer service changes type", func() {
+		exposeThenRetype := func(serviceType corev1.ServiceType) (*ServiceReconciler, *record.FakeRecorder, *corev1.Service) {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			Expect(current.Status.LoadBalancer.Ingress).NotTo(BeEmpty())
+			current.Spec.Type = serviceType
+			if serviceType == corev1.ServiceTypeExternalName {
+				current.Spec.ExternalName = "web.example.net"
+				current.Spec.Ports = nil
+			}
+			Expect(r.Update(context.Background(), &current)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			return r, recorder, svc
+		}
+
+		for _, serviceType := range []corev1.ServiceType{corev1.ServiceTypeExternalName, corev1.ServiceTypeClusterIP} {
+			It("should delete the Route and clear the ingress after a change to "+string(serviceType), func() {
+				r, recorder, svc := exposeThenRetype(serviceType)
+
+				var routes routev1.RouteList
+				Expect(r.List(context.Background(), &routes)).To(Succeed())
+				Expect(routes.Items).To(BeEmpty())
+
+				var current corev1.Service
+				Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+				Expect(current.Status.LoadBalancer.Ingress).To(BeEmpty())
+
+				var events []string
+				for len(recorder.Events) > 0 {
+					events = append(events, <-recorder.Events)
+				}
+				Expect(events).To(ContainElement(ContainSubstring("RouteRemoved")))
+			})
+		}
+
+		It("should release the registered host and forget the service's warnings", func() {
+			registryKey := types.NamespacedName{Namespace: "tinylb-system", Name: "tinylb-hosts"}
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{http2Annotation: "sometimes"}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, HostRegistry: registryKey}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring(http2Annotation)))
+
+			retype := func(serviceType corev1.ServiceType) {
+				var current corev1.Service
+				Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+				current.Spec.Type = serviceType
+				Expect(r.Update(context.Background(), &current)).To(Succeed())
+				_, err := reconcileService(r, svc)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			retype(corev1.ServiceTypeClusterIP)
+			var registry corev1.ConfigMap
+			Expect(r.Get(context.Background(), registryKey, &registry)).To(Succeed())
+			Expect(registry.Data).NotTo(HaveKey(string(svc.UID)))
+
+			Expect(recorder.Events).To(Receive(ContainSubstring("RouteRemoved")))
+
+			By("warning again once the service is a LoadBalancer again")
+			retype(corev1.ServiceTypeLoadBalancer)
+			Expect(recorder.Events).To(Receive(ContainSubstring(http2Annotation)))
+		})
+
+		It("should leave nothing to do on the next reconcile", func() {
+			r, recorder, svc := exposeThenRetype(corev1.ServiceTypeExternalName)
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("RouteRemoved")))
+		})
+
+		It("should admit the update even without TinyLB annotations", func() {
+			filter := serviceEventFilter("")
+			old := newLoadBalancerService("web", "shop")
+			retyped := old.DeepCopy()
+			retyped.Spec.Type = corev1.ServiceTypeClusterIP
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: retyped})).To(BeTrue())
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: retyped, ObjectNew: retyped})).To(BeFalse())
+		})
+	})
+
+	Context("When routes spread across several base domains", func() {
+		It("should count the managed Routes per domain", func() {
+			domains := []string{"apps.one.example.com", "apps.two.example.com", "apps.three.example.com"}
+			pinned := func(name, domain string) *corev1.Service {
+				svc := newLoadBalancerService(name, "shop")
+				svc.Annotations = map[string]string{baseDomainAnnotation: domain}
+				return svc
+			}
+			services := []*corev1.Service{
+				pinned("web", "apps.one.example.com"),
+				pinned("api", "apps.one.example.com"),
+				pinned("admin", "apps.two.example.com"),
+			}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, services[0], services[1], services[2]), Scheme: scheme, BaseDomains: domains}
+			for _, svc := range services {
+				_, err := reconcileService(r, svc)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			r.recordRouteDistribution(context.Background())
+
+			Expect(testutil.ToFloat64(routesPerDomain.WithLabelValues("apps.one.example.com"))).To(Equal(2.0))
+			Expect(testutil.ToFloat64(routesPerDomain.WithLabelValues("apps.two.example.com"))).To(Equal(1.0))
+			Expect(testutil.ToFloat64(routesPerDomain.WithLabelValues("apps.three.example.com"))).To(BeZero())
+
+			Expect(r.Delete(context.Background(), services[0])).To(Succeed())
+			Expect(r.Delete(context.Background(), &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: RouteName(services[0]), Namespace: "shop"}})).To(Succeed())
+			r.recordRouteDistribution(context.Background())
+			Expect(testutil.ToFloat64(routesPerDomain.WithLabelValues("apps.one.example.com"))).To(Equal(1.0))
+		})
+
+		It("should match a host to the longest configured domain", func() {
+			domains := []string{"example.com", "apps.example.com"}
+			Expect(routeDomain("web-shop.apps.example.com", domains)).To(Equal("apps.example.com"))
+			Expect(routeDomain("web-shop.example.com", domains)).To(Equal("example.com"))
+			Expect(routeDomain("web-shop.example.org", domains)).To(BeEmpty())
+		})
+	})
+
+	Context("When port selection is validated against endpoints", func() {
+		newService := func() *corev1.Service {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports = []corev1.ServicePort{
+				{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(8443)},
+				{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(8080)},
+			}
+			return svc
+		}
+		newSlice := func(ready bool, ports ...string) *discoveryv1.EndpointSlice {
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "web-abc",
+					Namespace: "shop",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses:  []string{"10.0.0.5"},
+					Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)},
+				}},
+			}
+			for i, name := range ports {
+				slice.Ports = append(slice.Ports, discoveryv1.EndpointPort{Name: ptr.To(name), Port: ptr.To(int32(8080 + i))})
+			}
+			return slice
+		}
+		routeTarget := func(r *ServiceReconciler, svc *corev1.Service) intstr.IntOrString {
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			return route.Spec.Port.TargetPort
+		}
+
+		It("should keep the preferred port when it has a ready endpoint", func() {
+			svc := newService()
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, newSlice(true, "https", "http")), Scheme: scheme, ValidatePorts: true}
+
+			Expect(routeTarget(r, svc)).To(Equal(intstr.FromInt(443)))
+		})
+
+		It("should fall back to the next candidate with a ready endpoint", func() {
+			svc := newService()
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, newSlice(true, "http")), Scheme: scheme, ValidatePorts: true}
+
+			Expect(routeTarget(r, svc)).To(Equal(intstr.FromInt(80)))
+		})
+
+		It("should move the existing Route to the preferred port once its endpoints become ready", func() {
+			svc := newService()
+			scheme := newTestScheme()
+			slice := newSlice(true, "http")
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, slice), Scheme: scheme, ValidatePorts: true}
+			Expect(routeTarget(r, svc)).To(Equal(intstr.FromInt(80)))
+
+			// The https pods come up: the EndpointSlice now lists both ports
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(slice), slice)).To(Succeed())
+			slice.Ports = newSlice(true, "https", "http").Ports
+			Expect(r.Update(context.Background(), slice)).To(Succeed())
+
+			Expect(mapEndpointSliceToService(context.Background(), slice)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "web", Namespace: "shop"}}))
+			Expect(routeTarget(r, svc)).To(Equal(intstr.FromInt(443)))
+		})
+
+		It("should keep the top candidate with a Warning when no endpoint is ready", func() {
+			svc := newService()
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, newSlice(false, "https", "http")), Scheme: scheme,
+				Recorder: recorder, ValidatePorts: true}
+
+			Expect(routeTarget(r, svc)).To(Equal(intstr.FromInt(443)))
+			Expect(recorder.Events).To(Receive(ContainSubstring("NoReadyPort")))
+
+			By("not repeating the warning on the next reconcile")
+			Expect(routeTarget(r, svc)).To(Equal(intstr.FromInt(443)))
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("NoReadyPort")))
+		})
+
+		It("should ignore endpoints unless enabled", func() {
+			svc := newService()
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc, newSlice(true, "http")), Scheme: scheme}
+
+			Expect(routeTarget(r, svc)).To(Equal(intstr.FromInt(443)))
+		})
+
+		It("should rank every TCP port once, skipped ports last", func() {
+			ports := []corev1.ServicePort{
+				{Name: "status", Port: 15021, Protocol: corev1.ProtocolTCP},
+				{Name: "web", Port: 9000, Protocol: corev1.ProtocolTCP},
+				{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP},
+			}
+			ranked := rankHTTPPorts(ports, nil, nil, DefaultManagementPorts)
+			Expect(ranked).To(HaveLen(3))
+			Expect([]int32{ranked[0].Port, ranked[1].Port, ranked[2].Port}).To(Equal([]int32{443, 9000, 15021}))
+		})
+	})
+
+	Context("When a service names a router shard", func() {
+		routeLabels := func(r *ServiceReconciler, svc *corev1.Service) map[string]string {
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			return route.Labels
+		}
+
+		It("should label the Route for that shard", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{routerShardAnnotation: "internal"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+
+			Expect(routeLabels(r, svc)).To(HaveKeyWithValue(routerShardSelectionLabel, "internal"))
+		})
+
+		It("should follow changes to the annotation and drop the label once it is removed", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{routerShardAnnotation: "internal"}
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			Expect(routeLabels(r, svc)).To(HaveKeyWithValue(routerShardSelectionLabel, "internal"))
+
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			current.Annotations[routerShardAnnotation] = "public"
+			Expect(r.Update(context.Background(), &current)).To(Succeed())
+			Expect(routeLabels(r, svc)).To(HaveKeyWithValue(routerShardSelectionLabel, "public"))
+
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			delete(current.Annotations, routerShardAnnotation)
+			Expect(r.Update(context.Background(), &current)).To(Succeed())
+			labels := routeLabels(r, svc)
+			Expect(labels).NotTo(HaveKey(routerShardSelectionLabel))
+			Expect(labels).To(HaveKeyWithValue(managedLabel, "true"))
+		})
+
+		It("should ignore values that are not valid label values with a Warning event", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{routerShardAnnotation: "internal shard!"}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			Expect(routeLabels(r, svc)).NotTo(HaveKey(routerShardSelectionLabel))
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidAnnotation")))
+
+			// The value is still invalid on the next pass; it was reported already
+			Expect(routeLabels(r, svc)).NotTo(HaveKey(routerShardSelectionLabel))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should leave a router-shard label set by hand alone", func() {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme}
+			Expect(routeLabels(r, svc)).NotTo(HaveKey(routerShardSelectionLabel))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			route.Labels[routerShardSelectionLabel] = "internal"
+			Expect(r.Update(context.Background(), &route)).To(Succeed())
+
+			Expect(routeLabels(r, svc)).To(HaveKeyWithValue(routerShardSelectionLabel, "internal"))
+		})
+	})
+
+	Context("When passthrough would front a plain HTTP port", func() {
+		reconcileWith := func(port corev1.ServicePort, warn bool) []string {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports = []corev1.ServicePort{port}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, WarnPlainHTTPPassthrough: warn}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			return events
+		}
+
+		It("should warn about port 80", func() {
+			events := reconcileWith(corev1.ServicePort{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}, true)
+			Expect(events).To(ContainElement(And(ContainSubstring("PlainHTTPPassthrough"), ContainSubstring("edge"))))
+		})
+
+		It("should not repeat the warning on the next reconcile", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder, WarnPlainHTTPPassthrough: true}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("PlainHTTPPassthrough")))
+
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("PlainHTTPPassthrough")))
+		})
+
+		It("should warn about ports declaring appProtocol http", func() {
+			events := reconcileWith(corev1.ServicePort{Name: "web", Port: 9000, Protocol: corev1.ProtocolTCP, AppProtocol: ptr.To("HTTP")}, true)
+			Expect(events).To(ContainElement(ContainSubstring("PlainHTTPPassthrough")))
+		})
+
+		It("should not warn about HTTPS ports", func() {
+			Expect(reconcileWith(corev1.ServicePort{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP}, true)).
+				NotTo(ContainElement(ContainSubstring("PlainHTTPPassthrough")))
+			Expect(reconcileWith(corev1.ServicePort{Name: "https-alt", Port: 8080, Protocol: corev1.ProtocolTCP}, true)).
+				NotTo(ContainElement(ContainSubstring("PlainHTTPPassthrough")))
+			Expect(reconcileWith(corev1.ServicePort{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP, AppProtocol: ptr.To("https")}, true)).
+				NotTo(ContainElement(ContainSubstring("PlainHTTPPassthrough")))
+		})
+
+		It("should not warn about h2c ports, which get a plain HTTP Route", func() {
+			events := reconcileWith(corev1.ServicePort{Name: "grpc", Port: 8080, Protocol: corev1.ProtocolTCP, AppProtocol: ptr.To(h2cAppProtocol)}, true)
+			Expect(events).NotTo(ContainElement(ContainSubstring("PlainHTTPPassthrough")))
+		})
+
+		It("should stay quiet when disabled", func() {
+			events := reconcileWith(corev1.ServicePort{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}, false)
+			Expect(events).NotTo(ContainElement(ContainSubstring("PlainHTTPPassthrough")))
+		})
+	})
+
+	Context("When a service's host changes", func() {
+		const oldHost, newHost = "web-shop.apps.old.example.com", "web-shop.apps.new.example.com"
+		routeKey := types.NamespacedName{Name: "tinylb-web", Namespace: "shop"}
+
+		// exposeOnOldDomain reconciles svc under the old base domain, then switches to the new one
+		exposeOnOldDomain := func(zeroDowntime bool) (*ServiceReconciler, *record.FakeRecorder, *corev1.Service) {
+			svc := newLoadBalancerService("web", "shop")
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(20)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder,
+				BaseDomains: []string{"apps.old.example.com"}, ZeroDowntimeHostChange: zeroDowntime}
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			r.BaseDomains = []string{"apps.new.example.com"}
+			return r, recorder, svc
+		}
+		getRoute := func(r *ServiceReconciler, key types.NamespacedName) (*routev1.Route, error) {
+			var route routev1.Route
+			err := r.Get(context.Background(), key, &route)
+			return &route, err
+		}
+		// migrationRoutes returns the temporary Routes, which have generated names
+		migrationRoutes := func(r *ServiceReconciler) []routev1.Route {
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes, client.InNamespace("shop"), client.MatchingLabels{hostMigrationLabel: "true"})).To(Succeed())
+			return routes.Items
+		}
+		getMigration := func(r *ServiceReconciler) *routev1.Route {
+			routes := migrationRoutes(r)
+			Expect(routes).To(HaveLen(1))
+			return &routes[0]
+		}
+		advertised := func(r *ServiceReconciler, svc *corev1.Service) []string {
+			var current corev1.Service
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), &current)).To(Succeed())
+			var hosts []string
+			for _, entry := range current.Status.LoadBalancer.Ingress {
+				hosts = append(hosts, entry.Hostname)
+			}
+			return hosts
+		}
+		admit := func(r *ServiceReconciler, route *routev1.Route) {
+			route.Status.Ingress = []routev1.RouteIngress{{
+				Host:       route.Spec.Host,
+				Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+			}}
+			Expect(r.Update(context.Background(), route)).To(Succeed())
+		}
+
+		It("should keep the old host unless migrations are enabled", func() {
+			r, _, svc := exposeOnOldDomain(false)
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			route, err := getRoute(r, routeKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Spec.Host).To(Equal(oldHost))
+			Expect(migrationRoutes(r)).To(BeEmpty())
+		})
+
+		It("should serve the new host from a second Route before leaving the old one", func() {
+			r, recorder, svc := exposeOnOldDomain(true)
+
+			// The new host is served next to the old one until a router admits it
+			result, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(hostMigrationPollInterval))
+			migration := getMigration(r)
+			Expect(migration.Name).NotTo(Equal(RouteName(svc) + "-migration"))
+			Expect(migration.Spec.Host).To(Equal(newHost))
+			Expect(migration.Labels).To(HaveKeyWithValue(serviceUIDLabel, string(svc.UID)))
+			route, err := getRoute(r, routeKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Spec.Host).To(Equal(oldHost))
+			Expect(advertised(r, svc)).To(Equal([]string{oldHost}))
+			Expect(recorder.Events).To(Receive(ContainSubstring("HostMigrationStarted")))
+
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrationRoutes(r)).To(HaveLen(1))
+			Expect(advertised(r, svc)).To(Equal([]string{oldHost}))
+
+			// Once admitted, the status switches over and the service's Route takes the new host
+			admit(r, migration)
+			result, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(hostMigrationPollInterval))
+			Expect(advertised(r, svc)).To(Equal([]string{newHost}))
+			route, err = getRoute(r, routeKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Spec.Host).To(Equal(newHost))
+
+			// The temporary Route keeps serving until the router re-admits the Route on the new host
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrationRoutes(r)).To(HaveLen(1))
+
+			admit(r, route)
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrationRoutes(r)).To(BeEmpty())
+			Expect(advertised(r, svc)).To(Equal([]string{newHost}))
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement(ContainSubstring("HostMigrated")))
+		})
+
+		It("should not take an admission for another host as admission of the new one", func() {
+			r, _, svc := exposeOnOldDomain(true)
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			migration := getMigration(r)
+			migration.Status.Ingress = []routev1.RouteIngress{{
+				Host:       "web-shop.apps.stale.example.com",
+				Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+			}}
+			Expect(r.Update(context.Background(), migration)).To(Succeed())
+
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(advertised(r, svc)).To(Equal([]string{oldHost}))
+			route, err := getRoute(r, routeKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Spec.Host).To(Equal(oldHost))
+		})
+
+		It("should not mistake another service's Route for the temporary Route", func() {
+			r, _, svc := exposeOnOldDomain(true)
+			other := newLoadBalancerService("web-migration", "shop")
+			Expect(r.Create(context.Background(), other)).To(Succeed())
+			_, err := reconcileService(r, other)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getMigration(r).Spec.Host).To(Equal(newHost))
+			otherRoute, err := getRoute(r, types.NamespacedName{Name: RouteName(other), Namespace: "shop"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(otherRoute.Labels).NotTo(HaveKey(hostMigrationLabel))
+		})
+
+		It("should report a rejected new host and keep serving the old one", func() {
+			r, recorder, svc := exposeOnOldDomain(true)
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("HostMigrationStarted")))
+
+			migration := getMigration(r)
+			migration.Status.Ingress = []routev1.RouteIngress{{
+				Host: newHost,
+				Conditions: []routev1.RouteIngressCondition{{
+					Type: routev1.RouteAdmitted, Status: corev1.ConditionFalse, Reason: "HostAlreadyClaimed",
+				}},
+			}}
+			Expect(r.Update(context.Background(), migration)).To(Succeed())
+
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(And(ContainSubstring("HostMigrationBlocked"), ContainSubstring("HostAlreadyClaimed"))))
+			Expect(advertised(r, svc)).To(Equal([]string{oldHost}))
+		})
+
+		It("should abandon the migration when the old host is wanted again", func() {
+			r, _, svc := exposeOnOldDomain(true)
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrationRoutes(r)).To(HaveLen(1))
+
+			r.BaseDomains = []string{"apps.old.example.com"}
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrationRoutes(r)).To(BeEmpty())
+			route, err := getRoute(r, routeKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Spec.Host).To(Equal(oldHost))
+		})
+	})
+
+	Context("When a service requests a rewrite target", func() {
+		It("should ignore it on the passthrough Route with a Warning event", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{rewriteTargetAnnotation: "/"}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring(rewriteTargetAnnotation)))
+
+			var route routev1.Route
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}, &route)).To(Succeed())
+			Expect(route.Annotations).NotTo(HaveKey(rewriteTargetRouteAnnotation))
+
+			By("not repeating the warning on the next reconcile")
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring(rewriteTargetAnnotation)))
+		})
+
+		It("should set it on the plain HTTP Route of an h2c port and remove it with the annotation", func() {
+			svc := newLoadBalancerService("web", "shop")
+			svc.Annotations = map[string]string{rewriteTargetAnnotation: "/api"}
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "h2c", Port: 8080, AppProtocol: ptr.To(h2cAppProtocol)}}
+			scheme := newTestScheme()
+			recorder := record.NewFakeRecorder(10)
+			r := &ServiceReconciler{Client: newFakeClient(scheme, svc), Scheme: scheme, Recorder: recorder}
+
+			_, err := reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring(rewriteTargetAnnotation)))
+
+			var route routev1.Route
+			key := types.NamespacedName{Name: RouteName(svc), Namespace: "shop"}
+			Expect(r.Get(context.Background(), key, &route)).To(Succeed())
+			Expect(route.Spec.TLS).To(BeNil())
+			Expect(route.Annotations).To(HaveKeyWithValue(rewriteTargetRouteAnnotation, "/api"))
+
+			By("removing it again once the service annotation goes away")
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(svc), svc)).To(Succeed())
+			svc.Annotations = nil
+			Expect(r.Update(context.Background(), svc)).To(Succeed())
+			_, err = reconcileService(r, svc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(context.Background(), key, &route)).To(Succeed())
+			Expect(route.Annotations).NotTo(HaveKey(rewriteTargetRouteAnnotation))
+		})
+	})
+
+	Context("When Route creation is rate limited", func() {
+		It("should pace creations and requeue services over the budget", func() {
+			first := newLoadBalancerService("first", "shop")
+			second := newLoadBalancerService("second", "shop")
+			scheme := newTestScheme()
+			r := &ServiceReconciler{Client: newFakeClient(scheme, first, second), Scheme: scheme, RouteCreateQPS: 0.5}
+
+			result, err := reconcileService(r, first)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			result, err = reconcileService(r, second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", time.Second))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 2*time.Second))
+
+			var routes routev1.RouteList
+			Expect(r.List(context.Background(), &routes, client.InNamespace("shop"))).To(Succeed())
+			Expect(routes.Items).To(HaveLen(1))
+			Expect(routes.Items[0].Spec.To.Name).To(Equal("first"))
+		})
+
+		It("should let a creation through once the budget refills", func() {
+			var limiter routeCreateLimiter
+			now := time.Now()
+			Expect(limiter.reserve(10, now)).To(BeZero())
+			Expect(limiter.reserve(10, now)).To(Equal(100 * time.Millisecond))
+			Expect(limiter.reserve(10, now.Add(50*time.Millisecond))).To(Equal(50 * time.Millisecond))
+			Expect(limiter.reserve(10, now.Add(100*time.Millisecond))).To(BeZero())
+			Expect(limiter.reserve(0, now)).To(BeZero())
+		})
+	})
 })
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// serviceExposure is how a service type handler decides a service is exposed
+type serviceExposure int
+
+const (
+	// exposureNone leaves the service alone
+	exposureNone serviceExposure = iota
+	// exposureRoute exposes the service through a Route
+	exposureRoute
+)
+
+// serviceTypeHandler decides how a service of one type is exposed, reporting services it cannot
+// expose through events. Handlers are ServiceReconciler methods, stored as method expressions.
+type serviceTypeHandler func(r *ServiceReconciler, ctx context.Context, service *corev1.Service) serviceExposure
+
+// serviceTypeHandlers maps each service type TinyLB looks at to its handler. Services of other
+// types are ignored. Supporting a new type means adding a handler here and admitting the type
+// in serviceEventFilter.
+var serviceTypeHandlers = map[corev1.ServiceType]serviceTypeHandler{
+	corev1.ServiceTypeLoadBalancer: (*ServiceReconciler).exposeLoadBalancer,
+	corev1.ServiceTypeExternalName: (*ServiceReconciler).exposeExternalName,
+}
+
+// exposeLoadBalancer exposes LoadBalancer services through a Route, unless they have nothing a
+// Route could reach
+func (r *ServiceReconciler) exposeLoadBalancer(ctx context.Context, service *corev1.Service) serviceExposure {
+	logger := log.FromContext(ctx)
+
+	// Headless services have no cluster IP for a Route to target
+	if service.Spec.ClusterIP == corev1.ClusterIPNone {
+		logger.Info("Headless LoadBalancer service cannot be fronted by a Route, skipping", "service", service.Name)
+		r.warnings.warnf(r.Recorder, service, "HeadlessUnsupported", "HeadlessUnsupported",
+			"Headless services (clusterIP: None) cannot be exposed through a Route")
+		return exposureNone
+	}
+	r.warnings.resolve(service, "HeadlessUnsupported")
+
+	// Routes only carry TCP; a service exposing nothing else has no port a Route could front
+	if len(service.Spec.Ports) > 0 && len(tcpPorts(service.Spec.Ports)) == 0 {
+		logger.Info("LoadBalancer service has no TCP port, skipping", "service", service.Name)
+		r.warnings.warnf(r.Recorder, service, "NoTCPPort", "NoTCPPort",
+			"Service exposes no TCP port; Routes can only front TCP")
+		return exposureNone
+	}
+	r.warnings.resolve(service, "NoTCPPort")
+	return exposureRoute
+}
+
+// exposeExternalName never exposes ExternalName services, which have no endpoints for a Route to
+// reach; it says so rather than ignoring services that were meant for TinyLB (or were
+// LoadBalancers before)
+func (r *ServiceReconciler) exposeExternalName(ctx context.Context, service *corev1.Service) serviceExposure {
+	if requestsTinyLB(service, r.OptInAnnotation) {
+		log.FromContext(ctx).Info("ExternalName service cannot be fronted by a Route, skipping", "service", service.Name, "externalName", service.Spec.ExternalName)
+		r.warnings.warnf(r.Recorder, service, "ExternalNameUnsupported", "ExternalNameUnsupported",
+			"ExternalName service (%s) cannot be exposed through a Route, which needs endpoints to send traffic to", service.Spec.ExternalName)
+	} else {
+		r.warnings.resolve(service, "ExternalNameUnsupported")
+	}
+	return exposureNone
+}
+
+// unexpose cleans up after a service that stopped being a LoadBalancer, e.g. one edited to
+// ClusterIP or ExternalName: its Routes (and companion) are deleted, since their target no longer
+// exposes what they front, their hosts are withdrawn from the service's status and released, and
+// the warnings about its Route are forgotten
+func (r *ServiceReconciler) unexpose(ctx context.Context, service *corev1.Service) error {
+	var routes routev1.RouteList
+	if err := r.List(ctx, &routes, client.InNamespace(service.Namespace), client.MatchingLabels{
+		managedLabel:    "true",
+		serviceUIDLabel: string(service.UID),
+	}); err != nil {
+		return err
+	}
+	if err := r.deleteCompanionService(ctx, service); err != nil {
+		return err
+	}
+	r.hostClaims.release(client.ObjectKeyFromObject(service))
+	unregistered, err := r.unregisterHost(ctx, service)
+	if err != nil {
+		return err
+	}
+	// Only forget on the change itself, so warnings about the service's new type are kept
+	if unregistered || len(routes.Items) > 0 {
+		r.warnings.forget(client.ObjectKeyFromObject(service))
+	}
+
+	hosts := map[string]bool{}
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Delete(ctx, route); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		hosts[route.Spec.Host] = true
+		log.FromContext(ctx).Info("Service is no longer a LoadBalancer, deleted its Route", "service", service.Name, "type", service.Spec.Type, "route", route.Name)
+		r.Recorder.Eventf(service, corev1.EventTypeNormal, "RouteRemoved",
+			"Service type changed to %s; removed Route %s and stopped advertising %s", service.Spec.Type, route.Name, route.Spec.Host)
+	}
+
+	ingress := slices.DeleteFunc(slices.Clone(service.Status.LoadBalancer.Ingress), func(entry corev1.LoadBalancerIngress) bool {
+		return entry.Hostname != "" && hosts[entry.Hostname]
+	})
+	if len(ingress) == len(service.Status.LoadBalancer.Ingress) {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	serviceCopy := service.DeepCopy()
+	serviceCopy.Status.LoadBalancer.Ingress = ingress
+	return r.Status().Update(ctx, serviceCopy)
+}
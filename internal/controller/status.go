@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// forbiddenStatusRetryInterval is how long to wait before retrying a status update rejected as Forbidden
+const forbiddenStatusRetryInterval = 5 * time.Minute
+
+// statusForbiddenReporter explains a Forbidden status update once per resource, instead of
+// failing every reconcile with the same opaque error while the RBAC stays broken
+type statusForbiddenReporter struct {
+	reported sync.Map
+}
+
+// handle returns the reconcile result for a failed status update of obj
+// Forbidden errors are reported once and retried slowly; anything else is returned for a normal retry
+func (s *statusForbiddenReporter) handle(ctx context.Context, recorder record.EventRecorder, obj client.Object, resource string, err error) (ctrl.Result, error) {
+	if !errors.IsForbidden(err) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+
+	if _, reported := s.reported.LoadOrStore(resource, true); !reported {
+		log.FromContext(ctx).Error(err, "Status updates are forbidden, check that the TinyLB ClusterRole grants update and patch on the status subresource",
+			"resource", resource)
+		recorder.Eventf(obj, corev1.EventTypeWarning, "StatusUpdateForbidden",
+			"TinyLB is not allowed to update %s; grant get, update and patch on it to the controller's ClusterRole", resource)
+	}
+	return ctrl.Result{RequeueAfter: forbiddenStatusRetryInterval}, nil
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// uncachedRecheckInterval is how often Gateways depending on objects outside the cache are
+// reconciled to pick up their changes. Only TinyLB's own Secrets and ConfigMaps are cached and
+// watched, so the listener Secrets mirrored from and the destination CA ConfigMaps named by users
+// are read directly, and rotations are noticed by polling.
+const uncachedRecheckInterval = 5 * time.Minute
+
+// mirroredFromAnnotation records the {namespace}/{name} of the Secret a mirrored TLS Secret copies
+const mirroredFromAnnotation = "tinylb.io/mirrored-from"
+
+// listenerCertificateSecret returns the Secret holding the certificate of a Terminate listener
+// Only the first certificateRef is used, and only when it names a Secret in the Gateway's own
+// namespace: cross-namespace references would need a ReferenceGrant check TinyLB does not do
+func listenerCertificateSecret(listener gatewayv1.Listener) (string, bool) {
+	if termination, ok := listenerTermination(listener); !ok || termination == routev1.TLSTerminationPassthrough {
+		return "", false
+	}
+	if len(listener.TLS.CertificateRefs) == 0 {
+		return "", false
+	}
+	ref := listener.TLS.CertificateRefs[0]
+	if ref.Group != nil && *ref.Group != "" {
+		return "", false
+	}
+	if ref.Kind != nil && *ref.Kind != "Secret" {
+		return "", false
+	}
+	if ref.Namespace != nil {
+		return "", false
+	}
+	return string(ref.Name), true
+}
+
+// listenerCertificates returns, per listener, the Secret in routeNamespace its reencrypt Route
+// serves. In the Gateway's namespace that is the listener's own Secret; in a central route
+// namespace the router cannot read it there, so the Secret is mirrored next to the Route under
+// the Route's name, and mirrors of listeners that are gone are deleted.
+func (r *GatewayReconciler) listenerCertificates(ctx context.Context, gateway *gatewayv1.Gateway, service *corev1.Service, routeNamespace string) (map[gatewayv1.SectionName]string, error) {
+	logger := log.FromContext(ctx)
+	certificates := map[gatewayv1.SectionName]string{}
+	keep := map[string]bool{}
+	var unserved []string
+	for _, listener := range gateway.Spec.Listeners {
+		secretName, ok := listenerCertificateSecret(listener)
+		if !ok {
+			continue
+		}
+		if _, ok := listenerRouteHost(listener); !ok || !listenerExposed(gateway, listener.Name) {
+			continue
+		}
+		if routeNamespace == gateway.Namespace {
+			certificates[listener.Name] = secretName
+			continue
+		}
+
+		var source corev1.Secret
+		if err := r.apiReader().Get(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: secretName}, &source); err != nil {
+			if errors.IsNotFound(err) {
+				key := "TLSSecret/" + string(listener.Name)
+				unserved = append(unserved, key)
+				r.warnings.warnf(r.Recorder, gateway, key, "TLSSecretNotFound",
+					"Listener %s references Secret %s, which does not exist; its Route uses the router's default certificate", listener.Name, secretName)
+				continue
+			}
+			return nil, err
+		}
+
+		name := listenerRouteName(service.Name, listener.Name)
+		owned, err := r.syncMirroredSecret(ctx, gateway, service, &source, routeNamespace, name)
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			key := "TLSSecret/" + string(listener.Name)
+			unserved = append(unserved, key)
+			r.warnings.warnf(r.Recorder, gateway, key, "TLSSecretConflict",
+				"Secret %s/%s exists and is not TinyLB's mirror of %s; listener %s's Route uses the router's default certificate",
+				routeNamespace, name, secretName, listener.Name)
+			continue
+		}
+		certificates[listener.Name] = name
+		keep[name] = true
+	}
+	r.warnings.resolveOthers(gateway, "TLSSecret/", unserved)
+
+	if routeNamespace == gateway.Namespace {
+		return certificates, nil
+	}
+	mirrors, err := r.mirroredSecrets(ctx, routeNamespace, gateway.Namespace, client.MatchingLabels{
+		managedLabel: "true",
+		serviceLabel: service.Name,
+		gatewayLabel: gateway.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range mirrors {
+		if keep[mirrors[i].Name] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := r.Delete(ctx, &mirrors[i]); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		logger.Info("Deleted stale mirrored TLS Secret", "secret", mirrors[i].Name, "namespace", routeNamespace)
+	}
+	return certificates, nil
+}
+
+// syncMirroredSecret creates or updates the copy of source named name in routeNamespace. It reports
+// false, touching nothing, when a Secret of that name exists that is not a TinyLB mirror for this
+// Gateway: a user's Secret is never overwritten.
+func (r *GatewayReconciler) syncMirroredSecret(ctx context.Context, gateway *gatewayv1.Gateway, service *corev1.Service, source *corev1.Secret, routeNamespace, name string) (bool, error) {
+	mirroredFrom := source.Namespace + "/" + source.Name
+	labels := map[string]string{
+		managedLabel: "true",
+		serviceLabel: service.Name,
+		gatewayLabel: gateway.Name,
+	}
+	var mirror corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Namespace: routeNamespace, Name: name}, &mirror)
+	if errors.IsNotFound(err) {
+		mirror = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   routeNamespace,
+				Annotations: map[string]string{mirroredFromAnnotation: mirroredFrom},
+				Labels:      labels,
+			},
+			Type: source.Type,
+			Data: source.Data,
+		}
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if err := r.Create(ctx, &mirror); err != nil {
+			// Only TinyLB's Secrets are cached, so a user's Secret of the same name shows up here
+			if errors.IsAlreadyExists(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		log.FromContext(ctx).Info("Mirrored TLS Secret", "secret", mirroredFrom, "mirror", name, "namespace", routeNamespace)
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if namespace, _, _ := strings.Cut(mirror.Annotations[mirroredFromAnnotation], "/"); mirror.Labels[managedLabel] != "true" ||
+		mirror.Labels[gatewayLabel] != gateway.Name || namespace != gateway.Namespace {
+		return false, nil
+	}
+	labelsCurrent := true
+	for key, value := range labels {
+		labelsCurrent = labelsCurrent && mirror.Labels[key] == value
+	}
+	if labelsCurrent && mirror.Annotations[mirroredFromAnnotation] == mirroredFrom && mirror.Type == source.Type && equality.Semantic.DeepEqual(mirror.Data, source.Data) {
+		return true, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	for key, value := range labels {
+		mirror.Labels[key] = value
+	}
+	mirror.Annotations[mirroredFromAnnotation] = mirroredFrom
+	mirror.Type = source.Type
+	mirror.Data = source.Data
+	if err := r.Update(ctx, &mirror); err != nil {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Updated mirrored TLS Secret", "secret", mirroredFrom, "mirror", name, "namespace", routeNamespace)
+	return true, nil
+}
+
+// apiReader returns the reader for objects the cache leaves out
+func (r *GatewayReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// mirroredSecrets lists the Secrets in routeNamespace matching labels that mirror a Secret of
+// gatewayNamespace; Gateways of the same name in other namespaces share the labels
+func (r *GatewayReconciler) mirroredSecrets(ctx context.Context, routeNamespace, gatewayNamespace string, labels client.MatchingLabels) ([]corev1.Secret, error) {
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, client.InNamespace(routeNamespace), labels); err != nil {
+		return nil, err
+	}
+	var mirrors []corev1.Secret
+	for _, secret := range secrets.Items {
+		if namespace, _, ok := strings.Cut(secret.Annotations[mirroredFromAnnotation], "/"); ok && namespace == gatewayNamespace {
+			mirrors = append(mirrors, secret)
+		}
+	}
+	return mirrors, nil
+}
+
+// deleteMirroredSecrets removes the TLS Secrets mirrored for a deleted or released Gateway
+// Mirrors live outside the Gateway's namespace, so owner references cannot clean them up
+func (r *GatewayReconciler) deleteMirroredSecrets(ctx context.Context, gateway types.NamespacedName) error {
+	if !r.MirrorTLSSecrets || r.RouteNamespace == "" || r.RouteNamespace == gateway.Namespace {
+		return nil
+	}
+	mirrors, err := r.mirroredSecrets(ctx, r.RouteNamespace, gateway.Namespace, client.MatchingLabels{
+		managedLabel: "true",
+		gatewayLabel: gateway.Name,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range mirrors {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Delete(ctx, &mirrors[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		log.FromContext(ctx).Info("Deleted mirrored TLS Secret", "secret", mirrors[i].Name, "namespace", r.RouteNamespace)
+	}
+	return nil
+}
+
+// mapSecretToGateways enqueues the Gateway a mirrored Secret belongs to, so a mirror that is
+// edited or deleted is restored
+func (r *GatewayReconciler) mapSecretToGateways(_ context.Context, obj client.Object) []reconcile.Request {
+	namespace, _, ok := strings.Cut(obj.GetAnnotations()[mirroredFromAnnotation], "/")
+	gateway := obj.GetLabels()[gatewayLabel]
+	if !ok || gateway == "" || obj.GetLabels()[managedLabel] != "true" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: gateway}}}
+}
+
+// mirrorsTLSSecrets reports whether any of the Gateway's listener Routes serve a mirrored Secret
+func (r *GatewayReconciler) mirrorsTLSSecrets(gateway *gatewayv1.Gateway, routeNamespace string) bool {
+	if !r.MirrorTLSSecrets || routeNamespace == gateway.Namespace {
+		return false
+	}
+	return slices.ContainsFunc(gateway.Spec.Listeners, func(listener gatewayv1.Listener) bool {
+		_, ok := listenerCertificateSecret(listener)
+		return ok
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tracerName identifies spans emitted by TinyLB
+const tracerName = "github.com/jctanner/tinylb"
+
+// startSpan starts a span from the globally registered tracer provider
+// The provider is looked up on every call so it can be installed after the reconcilers are built
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on span (if any) and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// objectAttributes describes obj for span attributes
+func objectAttributes(obj client.Object) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("k8s.kind", reflect.Indirect(reflect.ValueOf(obj)).Type().Name()),
+		attribute.String("k8s.namespace", obj.GetNamespace()),
+		attribute.String("k8s.name", obj.GetName()),
+	}
+}
+
+// tracingClient wraps a client so every API call gets its own span under the reconcile's trace
+type tracingClient struct {
+	client.Client
+}
+
+// NewTracingClient returns c instrumented with OpenTelemetry spans around each API call
+func NewTracingClient(c client.Client) client.Client {
+	return &tracingClient{Client: c}
+}
+
+func (c *tracingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) (err error) {
+	ctx, span := startSpan(ctx, "Get",
+		attribute.String("k8s.kind", reflect.Indirect(reflect.ValueOf(obj)).Type().Name()),
+		attribute.String("k8s.namespace", key.Namespace),
+		attribute.String("k8s.name", key.Name))
+	defer func() { endSpan(span, err) }()
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *tracingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	ctx, span := startSpan(ctx, "List",
+		attribute.String("k8s.kind", reflect.Indirect(reflect.ValueOf(list)).Type().Name()))
+	defer func() { endSpan(span, err) }()
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *tracingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) (err error) {
+	ctx, span := startSpan(ctx, "Create", objectAttributes(obj)...)
+	defer func() { endSpan(span, err) }()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *tracingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) (err error) {
+	ctx, span := startSpan(ctx, "Update", objectAttributes(obj)...)
+	defer func() { endSpan(span, err) }()
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *tracingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) (err error) {
+	ctx, span := startSpan(ctx, "Patch", objectAttributes(obj)...)
+	defer func() { endSpan(span, err) }()
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *tracingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) (err error) {
+	ctx, span := startSpan(ctx, "Delete", objectAttributes(obj)...)
+	defer func() { endSpan(span, err) }()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *tracingClient) Status() client.SubResourceWriter {
+	return &tracingStatusWriter{SubResourceWriter: c.Client.Status()}
+}
+
+// tracingStatusWriter adds spans to status subresource writes
+type tracingStatusWriter struct {
+	client.SubResourceWriter
+}
+
+func (w *tracingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) (err error) {
+	ctx, span := startSpan(ctx, "UpdateStatus", objectAttributes(obj)...)
+	defer func() { endSpan(span, err) }()
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *tracingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) (err error) {
+	ctx, span := startSpan(ctx, "PatchStatus", objectAttributes(obj)...)
+	defer func() { endSpan(span, err) }()
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// warningTracker remembers the Warning events last emitted for each object, so a problem that
+// persists across reconciles is reported when it appears or its message changes, instead of on
+// every reconcile. Each problem is identified by a key, usually the event reason. The record is
+// kept in memory, so a restart reports each standing problem once more.
+type warningTracker struct {
+	mu      sync.Mutex
+	emitted map[types.NamespacedName]map[string]string
+}
+
+// warnf emits a Warning event for obj unless the same message was the last one emitted under key
+func (w *warningTracker) warnf(recorder record.EventRecorder, obj client.Object, key, reason, messageFmt string, args ...any) {
+	message := fmt.Sprintf(messageFmt, args...)
+	name := client.ObjectKeyFromObject(obj)
+
+	w.mu.Lock()
+	if w.emitted[name][key] == message {
+		w.mu.Unlock()
+		return
+	}
+	if w.emitted == nil {
+		w.emitted = map[types.NamespacedName]map[string]string{}
+	}
+	if w.emitted[name] == nil {
+		w.emitted[name] = map[string]string{}
+	}
+	w.emitted[name][key] = message
+	w.mu.Unlock()
+
+	recorder.Event(obj, corev1.EventTypeWarning, reason, message)
+}
+
+// resolve records that the problem under key is gone, so it is reported again if it comes back
+func (w *warningTracker) resolve(obj client.Object, key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	name := client.ObjectKeyFromObject(obj)
+	delete(w.emitted[name], key)
+	if len(w.emitted[name]) == 0 {
+		delete(w.emitted, name)
+	}
+}
+
+// resolveOthers records that every problem under a key starting with prefix is gone, except
+// those under the keys in current
+func (w *warningTracker) resolveOthers(obj client.Object, prefix string, current []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	name := client.ObjectKeyFromObject(obj)
+	for key := range w.emitted[name] {
+		if strings.HasPrefix(key, prefix) && !slices.Contains(current, key) {
+			delete(w.emitted[name], key)
+		}
+	}
+	if len(w.emitted[name]) == 0 {
+		delete(w.emitted, name)
+	}
+}
+
+// forget drops everything recorded for a deleted object
+func (w *warningTracker) forget(name types.NamespacedName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.emitted, name)
+}